@@ -0,0 +1,114 @@
+package lidario
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// BlockIterator yields the points of a LAS file grouped into square XY grid
+// blocks, for block-wise algorithms such as local plane fitting.
+//
+// Because points are rarely stored in spatial order, the iterator first makes
+// a pass over the points to find their extent, then sorts the point indices by
+// block. This costs one int per point (in addition to the point data already
+// held in memory) and O(n log n) time up front, after which blocks are emitted
+// in row-major order starting at the north-west corner. Only occupied blocks
+// are emitted, and points within a block retain their file order.
+type BlockIterator struct {
+	las   *LasFile
+	grid  *Raster
+	order []int // point indices sorted by block
+	cells []int // block of each entry of order
+	pos   int
+	block []LasPointer
+	cell  int
+	err   error
+}
+
+// BlockIterator returns an iterator over the occupied blockSize x blockSize
+// blocks of the file.
+func (las *LasFile) BlockIterator(blockSize float64) (*BlockIterator, error) {
+	if las.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	n := len(las.pointData)
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i := range las.pointData {
+		p := &las.pointData[i]
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	if n == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+	grid, err := newRaster(minX, minY, maxX, maxY, blockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]int, n)
+	order := make([]int, n)
+	for i := range las.pointData {
+		row, column, ok := grid.CellOf(las.pointData[i].X, las.pointData[i].Y)
+		if !ok {
+			return nil, errors.New("point lies outside of the block grid")
+		}
+		keys[i] = row*grid.Columns + column
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return keys[order[a]] < keys[order[b]]
+	})
+	cells := make([]int, n)
+	for i, idx := range order {
+		cells[i] = keys[idx]
+	}
+	it := BlockIterator{las: las, grid: grid, order: order, cells: cells, cell: -1}
+	return &it, nil
+}
+
+// Next advances to the next occupied block, returning false when there are no
+// more blocks or an error has occurred.
+func (it *BlockIterator) Next() bool {
+	if it.err != nil || it.pos >= len(it.order) {
+		it.block = nil
+		return false
+	}
+	it.cell = it.cells[it.pos]
+	end := it.pos
+	for end < len(it.order) && it.cells[end] == it.cell {
+		end++
+	}
+	it.block = make([]LasPointer, 0, end-it.pos)
+	for _, idx := range it.order[it.pos:end] {
+		p, err := it.las.LasPoint(idx)
+		if err != nil {
+			it.err = err
+			it.block = nil
+			return false
+		}
+		it.block = append(it.block, p)
+	}
+	it.pos = end
+	return true
+}
+
+// Block returns the points of the current block.
+func (it *BlockIterator) Block() []LasPointer {
+	return it.block
+}
+
+// Bounds returns the XY extent of the current block.
+func (it *BlockIterator) Bounds() (minX, minY, maxX, maxY float64) {
+	row, column := it.cell/it.grid.Columns, it.cell%it.grid.Columns
+	minX = it.grid.MinX + float64(column)*it.grid.CellSize
+	maxY = it.grid.MaxY - float64(row)*it.grid.CellSize
+	return minX, maxY - it.grid.CellSize, minX + it.grid.CellSize, maxY
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *BlockIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,48 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestBlockIterator(t *testing.T) {
+	// Points are written in a scrambled order so that the blocks must be
+	// assembled from across the file.
+	var points []LasPointer
+	for i := 0; i < 400; i++ {
+		j := (i * 37) % 400
+		points = append(points, &PointRecord0{X: float64(j%20) + 0.5, Y: float64(j/20) + 0.5, Z: 1.0, PointSourceID: uint16(j)})
+	}
+	lf := createTestLasFile(t, 0, points)
+
+	it, err := lf.BlockIterator(5.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[uint16]int)
+	blocks := 0
+	for it.Next() {
+		blocks++
+		minX, minY, maxX, maxY := it.Bounds()
+		for _, p := range it.Block() {
+			pd := p.PointData()
+			seen[pd.PointSourceID]++
+			if pd.X < minX || pd.X > maxX || pd.Y < minY || pd.Y > maxY {
+				t.Errorf("point (%v, %v) lies outside of its block [%v, %v, %v, %v]", pd.X, pd.Y, minX, minY, maxX, maxY)
+			}
+		}
+	}
+	if err = it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if blocks != 16 {
+		t.Errorf("expected 16 occupied blocks, got %v", blocks)
+	}
+	if len(seen) != len(points) {
+		t.Errorf("expected %v distinct points, got %v", len(points), len(seen))
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("point %v appeared in %v blocks", id, n)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package lidario
+
+import (
+	"errors"
+)
+
+// Centroid returns the mean of the coordinates of all points, computed in a
+// single pass. A running mean is maintained rather than a sum of the
+// coordinates, so the result does not lose precision for large clouds with
+// large coordinate values.
+func (las *LasFile) Centroid() (x, y, z float64, err error) {
+	if las.fileMode == "rh" {
+		return NoData, NoData, NoData, errHeaderOnly
+	}
+	if len(las.pointData) == 0 {
+		return NoData, NoData, NoData, errors.New("the file does not contain any points")
+	}
+	for i := range las.pointData {
+		n := float64(i + 1)
+		p := &las.pointData[i]
+		x += (p.X - x) / n
+		y += (p.Y - y) / n
+		z += (p.Z - z) / n
+	}
+	return x, y, z, nil
+}
+
+// ToLocalFrame returns the coordinates of the points relative to the origin
+// (originX, originY, originZ), which is commonly the centroid of the cloud.
+// Local coordinates are small, which preserves precision in algorithms that
+// square or multiply coordinates.
+func (las *LasFile) ToLocalFrame(originX, originY, originZ float64) ([][3]float64, error) {
+	if las.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	local := make([][3]float64, len(las.pointData))
+	for i := range las.pointData {
+		p := &las.pointData[i]
+		local[i] = [3]float64{p.X - originX, p.Y - originY, p.Z - originZ}
+	}
+	return local, nil
+}
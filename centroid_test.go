@@ -0,0 +1,48 @@
+package lidario
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCentroid(t *testing.T) {
+	// The corners of a cube centred on (1000, 2000, 300) and its centre.
+	var points []LasPointer
+	for _, dx := range []float64{-5, 5} {
+		for _, dy := range []float64{-5, 5} {
+			for _, dz := range []float64{-5, 5} {
+				points = append(points, &PointRecord0{X: 1000 + dx, Y: 2000 + dy, Z: 300 + dz})
+			}
+		}
+	}
+	points = append(points, &PointRecord0{X: 1000, Y: 2000, Z: 300})
+	lf := createTestLasFile(t, 0, points)
+
+	x, y, z, err := lf.Centroid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(x-1000) > 1e-9 || math.Abs(y-2000) > 1e-9 || math.Abs(z-300) > 1e-9 {
+		t.Errorf("expected a centroid of (1000, 2000, 300), got (%v, %v, %v)", x, y, z)
+	}
+
+	local, err := lf.ToLocalFrame(x, y, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(local) != len(points) {
+		t.Fatalf("expected %v local coordinates, got %v", len(points), len(local))
+	}
+	var sum [3]float64
+	for _, c := range local {
+		for i := range c {
+			if math.Abs(c[i]) > 5+1e-9 {
+				t.Errorf("local coordinate %v lies outside of the cube", c)
+			}
+			sum[i] += c[i]
+		}
+	}
+	if math.Abs(sum[0]) > 1e-9 || math.Abs(sum[1]) > 1e-9 || math.Abs(sum[2]) > 1e-9 {
+		t.Errorf("expected the local coordinates to sum to zero, got %v", sum)
+	}
+}
@@ -0,0 +1,54 @@
+package lidario
+
+// ReadPointsByClass returns the points whose classification is one of classes,
+// reading the file sequentially. The classes above 31 of point formats 6-10
+// can be selected. An empty slice is returned if no classes are given.
+func (lf *LazFile) ReadPointsByClass(classes ...uint8) ([]LasPointer, error) {
+	if len(classes) == 0 {
+		return []LasPointer{}, nil
+	}
+	return lf.readPointsWhere(lf.inClasses(classes))
+}
+
+// inClasses returns a test for the points whose class, as translated by the
+// classification map, is one of classes. The class is read from the LASzip
+// point, which holds the full class of point formats 6-10.
+func (lf *LazFile) inClasses(classes []uint8) func(*LaszipPoint) bool {
+	var wanted [256]bool
+	for _, c := range classes {
+		wanted[c] = true
+	}
+	return func(lp *LaszipPoint) bool {
+		return wanted[lf.mapClass(lp.Classification)]
+	}
+}
+
+// SetClassificationMap translates the classification of the points read from
+// the file, e.g. from the class codes of a vendor to the ASPRS standard
+// classes: a point whose class is a key of m is returned with the class it
+// maps to. The map applies to every point subsequently returned by LasPoint,
+// ReadPoints and the PointIterator returned by Points, to the operations
+// built on them such as ReadPointsByClass, and to the ground points of
+// HeightAboveGroundGrid. ReadColumnar returns the classes as stored. The map
+// is copied; a nil or empty map returns the classes as stored.
+func (lf *LazFile) SetClassificationMap(m map[uint8]uint8) {
+	var classMap map[uint8]uint8
+	if len(m) != 0 {
+		classMap = make(map[uint8]uint8, len(m))
+		for from, to := range m {
+			classMap[from] = to
+		}
+	}
+	lf.Lock()
+	defer lf.Unlock()
+	lf.classMap = classMap
+}
+
+// mapClass returns the class that class is translated to by the
+// classification map.
+func (lf *LazFile) mapClass(class uint8) uint8 {
+	if to, ok := lf.classMap[class]; ok {
+		return to
+	}
+	return class
+}
@@ -0,0 +1,95 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestReadPointsByClass(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	stats, err := lf.ComputeStatistics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ground, err := lf.ReadPointsByClass(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint64(len(ground)) != stats.Classification[2] {
+		t.Errorf("expected %v ground points, got %v", stats.Classification[2], len(ground))
+	}
+	for _, p := range ground {
+		if c := p.PointData().ClassBitField.Classification(); c != 2 {
+			t.Fatalf("a point of class %v was returned for class 2", c)
+		}
+	}
+
+	var unused uint8
+	for unused = 31; stats.Classification[unused] > 0; unused-- {
+	}
+	none, err := lf.ReadPointsByClass(unused)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none) != 0 {
+		t.Errorf("expected no points of class %v, got %v", unused, len(none))
+	}
+}
+
+func TestInClasses(t *testing.T) {
+	// Classes 8 and 40 share their low five bits.
+	lf := &LazFile{Header: LasHeader{PointFormatID: 6}}
+	vegetation, other := &LaszipPoint{Classification: 8}, &LaszipPoint{Classification: 40}
+	if in := lf.inClasses([]uint8{8}); !in(vegetation) || in(other) {
+		t.Error("expected only the class 8 point to be selected for class 8")
+	}
+	if in := lf.inClasses([]uint8{40}); in(vegetation) || !in(other) {
+		t.Error("expected only the class 40 point to be selected for class 40")
+	}
+
+	// The classes are tested once translated.
+	lf.SetClassificationMap(map[uint8]uint8{40: 2})
+	if in := lf.inClasses([]uint8{2}); !in(other) || in(vegetation) {
+		t.Error("expected the class 40 point to be selected as class 2")
+	}
+}
+
+func TestReadPointsByClassEmpty(t *testing.T) {
+	// No classes are requested, so the file is not read.
+	lf := &LazFile{fileMode: "r"}
+	points, err := lf.ReadPointsByClass()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 0 {
+		t.Errorf("expected no points, got %v", len(points))
+	}
+}
+
+func TestSetClassificationMap(t *testing.T) {
+	lf := &LazFile{Header: LasHeader{PointFormatID: 0}}
+	m := map[uint8]uint8{11: 6, 2: 8}
+	lf.SetClassificationMap(m)
+	// The map is copied when installed.
+	m[11] = 1
+
+	for _, c := range []struct{ stored, expected uint8 }{{11, 6}, {2, 8}, {5, 5}} {
+		p := lf.convertPoint(&LaszipPoint{Classification: c.stored, Keypoint: true}).PointData()
+		if got := p.ClassBitField.Classification(); got != c.expected {
+			t.Errorf("class %v: expected %v, got %v", c.stored, c.expected, got)
+		}
+		if !p.ClassBitField.Keypoint() {
+			t.Errorf("class %v: expected the flags to be kept", c.stored)
+		}
+	}
+
+	lf.SetClassificationMap(nil)
+	if got := lf.convertPoint(&LaszipPoint{Classification: 11}).PointData().ClassBitField.Classification(); got != 11 {
+		t.Errorf("expected the stored class once the map is removed, got %v", got)
+	}
+}
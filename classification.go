@@ -0,0 +1,58 @@
+package lidario
+
+// classificationNames holds the names of the standard ASPRS classes of the LAS
+// 1.4 specification (R15), indexed by class code.
+var classificationNames = [...]string{
+	"Created, Never Classified",
+	"Unclassified",
+	"Ground",
+	"Low Vegetation",
+	"Medium Vegetation",
+	"High Vegetation",
+	"Building",
+	"Low Point (Noise)",
+	"Reserved", // model key-point before LAS 1.4
+	"Water",
+	"Rail",
+	"Road Surface",
+	"Reserved", // overlap points before LAS 1.4
+	"Wire - Guard (Shield)",
+	"Wire - Conductor (Phase)",
+	"Transmission Tower",
+	"Wire-Structure Connector (Insulator)",
+	"Bridge Deck",
+	"High Noise",
+	"Overhead Structure",
+	"Ignored Ground",
+	"Snow",
+	"Temporal Exclusion",
+}
+
+// firstUserDefinedClass is the first class code that the specification leaves
+// to users; the codes between the standard classes and it are reserved.
+const firstUserDefinedClass = 64
+
+// ClassificationName returns the ASPRS name of a class code, e.g. "Ground"
+// for 2 or "Building" for 6. Codes reserved by the specification are named
+// "Reserved" and codes 64-255 "User Definable". Note that point formats 0-5
+// store only the classes 0-31.
+func ClassificationName(code uint8) string {
+	switch {
+	case int(code) < len(classificationNames):
+		return classificationNames[code]
+	case code < firstUserDefinedClass:
+		return "Reserved"
+	}
+	return "User Definable"
+}
+
+// ClassificationCodes returns the name of every class code, 0-255, as given
+// by ClassificationName. The map is a copy that the caller may modify, e.g.
+// to name the user-defined classes of a vendor.
+func ClassificationCodes() map[uint8]string {
+	codes := make(map[uint8]string, 256)
+	for c := 0; c < 256; c++ {
+		codes[uint8(c)] = ClassificationName(uint8(c))
+	}
+	return codes
+}
@@ -0,0 +1,35 @@
+package lidario
+
+import "testing"
+
+func TestClassificationName(t *testing.T) {
+	for code, want := range map[uint8]string{
+		0:   "Created, Never Classified",
+		2:   "Ground",
+		3:   "Low Vegetation",
+		6:   "Building",
+		7:   "Low Point (Noise)",
+		9:   "Water",
+		12:  "Reserved",
+		18:  "High Noise",
+		22:  "Temporal Exclusion",
+		23:  "Reserved",
+		63:  "Reserved",
+		64:  "User Definable",
+		255: "User Definable",
+	} {
+		if got := ClassificationName(code); got != want {
+			t.Errorf("class %v: expected %q, got %q", code, want, got)
+		}
+	}
+
+	codes := ClassificationCodes()
+	if len(codes) != 256 || codes[2] != "Ground" || codes[200] != "User Definable" {
+		t.Errorf("unexpected classification codes: %v entries, 2 is %q and 200 is %q", len(codes), codes[2], codes[200])
+	}
+	// The map is a copy.
+	codes[2] = "Terrain"
+	if ClassificationCodes()[2] != "Ground" {
+		t.Error("modifying the returned map changed the names")
+	}
+}
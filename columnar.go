@@ -0,0 +1,123 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PointColumns holds points in a columnar (struct-of-arrays) layout: element
+// i of every slice belongs to the same point, and each attribute is stored
+// contiguously. Compared with a []LasPointer, which holds a pointer to a
+// separately allocated record per point, the columns need a handful of
+// allocations for any number of points and can be scanned, or copied to a GPU,
+// one attribute at a time. The class and return fields hold the full values
+// of point formats 6 and above. GPSTime, Red, Green and Blue are zero for
+// point formats that do not store them.
+type PointColumns struct {
+	X               []float64
+	Y               []float64
+	Z               []float64
+	Intensity       []uint16
+	Classification  []uint8
+	ReturnNumber    []uint8
+	NumberOfReturns []uint8
+	PointSourceID   []uint16
+	GPSTime         []float64
+	Red             []uint16
+	Green           []uint16
+	Blue            []uint16
+}
+
+// newPointColumns allocates columns with room for n points.
+func newPointColumns(n int) *PointColumns {
+	return &PointColumns{
+		X:               make([]float64, 0, n),
+		Y:               make([]float64, 0, n),
+		Z:               make([]float64, 0, n),
+		Intensity:       make([]uint16, 0, n),
+		Classification:  make([]uint8, 0, n),
+		ReturnNumber:    make([]uint8, 0, n),
+		NumberOfReturns: make([]uint8, 0, n),
+		PointSourceID:   make([]uint16, 0, n),
+		GPSTime:         make([]float64, 0, n),
+		Red:             make([]uint16, 0, n),
+		Green:           make([]uint16, 0, n),
+		Blue:            make([]uint16, 0, n),
+	}
+}
+
+// Len returns the number of points.
+func (pc *PointColumns) Len() int {
+	return len(pc.X)
+}
+
+func (pc *PointColumns) append(lp *LaszipPoint) {
+	pc.X = append(pc.X, lp.X)
+	pc.Y = append(pc.Y, lp.Y)
+	pc.Z = append(pc.Z, lp.Z)
+	pc.Intensity = append(pc.Intensity, lp.Intensity)
+	pc.Classification = append(pc.Classification, lp.Classification)
+	pc.ReturnNumber = append(pc.ReturnNumber, lp.ReturnNumber)
+	pc.NumberOfReturns = append(pc.NumberOfReturns, lp.NumberOfReturns)
+	pc.PointSourceID = append(pc.PointSourceID, lp.PointSourceID)
+	pc.GPSTime = append(pc.GPSTime, lp.GPSTime)
+	pc.Red = append(pc.Red, lp.Red)
+	pc.Green = append(pc.Green, lp.Green)
+	pc.Blue = append(pc.Blue, lp.Blue)
+}
+
+// ReadColumnar reads count consecutive points starting at start into columns.
+// Like ReadPoints, the points are decompressed in batches, and fewer points
+// are returned if the end of the file is reached.
+func (lf *LazFile) ReadColumnar(start, count int) (*PointColumns, error) {
+	if lf.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	lf.Lock()
+	defer lf.Unlock()
+
+	if count < 0 {
+		return nil, errors.New("the point count must not be negative")
+	}
+	if start < 0 || (start >= int(lf.Header.NumberPoints) && !lf.reader.IsStreaming()) {
+		return nil, fmt.Errorf("%w: %v", ErrPointOutOfRange, start)
+	}
+	if start != lf.currentPoint {
+		if err := lf.reader.SeekPoint(uint64(start)); err != nil {
+			return nil, fmt.Errorf("failed to seek to point %v: %w", start, err)
+		}
+		lf.currentPoint = start
+	}
+
+	n := count
+	if remaining := int(lf.Header.NumberPoints) - start; remaining >= 0 && remaining < n && !lf.reader.IsStreaming() {
+		n = remaining
+	}
+	columns := newPointColumns(n)
+	const batchSize = 4096
+	if len(lf.batch) == 0 {
+		lf.batch = make([]LaszipPoint, batchSize)
+	}
+	prog := newProgress(lf.progress, n)
+	for columns.Len() < count {
+		buf := lf.batch
+		if remaining := count - columns.Len(); remaining < len(buf) {
+			buf = buf[:remaining]
+		}
+		read, err := lf.reader.ReadPointsInto(buf)
+		for i := 0; i < read; i++ {
+			columns.append(&buf[i])
+		}
+		lf.currentPoint += read
+		prog.add(read)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return columns, fmt.Errorf("failed to read points: %w", err)
+		}
+	}
+	prog.finish()
+	return columns, nil
+}
@@ -0,0 +1,65 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestPointColumnsAppend(t *testing.T) {
+	pc := newPointColumns(2)
+	pc.append(&LaszipPoint{X: 1, Y: 2, Z: 3, Intensity: 40, Classification: 64, ReturnNumber: 9, NumberOfReturns: 12, Red: 500})
+	pc.append(&LaszipPoint{X: 4, Y: 5, Z: 6, GPSTime: 7.5})
+	if pc.Len() != 2 {
+		t.Fatalf("expected 2 points, got %v", pc.Len())
+	}
+	if pc.X[1] != 4 || pc.Z[0] != 3 || pc.Intensity[0] != 40 || pc.Classification[0] != 64 ||
+		pc.ReturnNumber[0] != 9 || pc.NumberOfReturns[0] != 12 || pc.Red[0] != 500 || pc.GPSTime[1] != 7.5 {
+		t.Errorf("unexpected columns %+v", pc)
+	}
+}
+
+func TestReadColumnar(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	start, count := 100, 5000
+	if lf.Header.NumberPoints < start+count {
+		t.Skip("the sample file has too few points")
+	}
+
+	columns, err := lf.ReadColumnar(start, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lengths := []int{len(columns.X), len(columns.Y), len(columns.Z), len(columns.Intensity),
+		len(columns.Classification), len(columns.ReturnNumber), len(columns.NumberOfReturns),
+		len(columns.PointSourceID), len(columns.GPSTime), len(columns.Red), len(columns.Green), len(columns.Blue)}
+	for i, n := range lengths {
+		if n != count {
+			t.Errorf("column %v holds %v values, expected %v", i, n, count)
+		}
+	}
+
+	points, err := lf.ReadPoints(start, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range points {
+		pd := p.PointData()
+		if columns.X[i] != pd.X || columns.Y[i] != pd.Y || columns.Z[i] != pd.Z || columns.Intensity[i] != pd.Intensity {
+			t.Fatalf("point %v: the columns differ from %+v", start+i, pd)
+		}
+	}
+
+	// Reading past the end returns the remaining points.
+	last := lf.Header.NumberPoints - 10
+	tail, err := lf.ReadColumnar(last, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tail.Len() != 10 {
+		t.Errorf("expected the 10 remaining points, got %v", tail.Len())
+	}
+}
@@ -0,0 +1,79 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// laszipVLRUserID and laszipVLRRecordID identify the VLR in which LASzip
+// describes how the point data are compressed.
+const (
+	laszipVLRUserID   = "laszip encoded"
+	laszipVLRRecordID = 22204
+)
+
+// CompressionInfo describes how the points of a LAZ file were compressed, as
+// recorded in the LASzip VLR.
+type CompressionInfo struct {
+	Compressor      uint16 // 0 none, 1 pointwise, 2 pointwise chunked, 3 layered chunked
+	Coder           uint16 // 0 arithmetic
+	VersionMajor    uint8
+	VersionMinor    uint8
+	VersionRevision uint16
+	Options         uint32
+	// ChunkSize is the number of points per chunk; 0xFFFFFFFF indicates
+	// variable-sized chunks
+	ChunkSize uint32
+}
+
+// CompressorName returns a description of the compressor.
+func (ci CompressionInfo) CompressorName() string {
+	switch ci.Compressor {
+	case 0:
+		return "none"
+	case 1:
+		return "pointwise"
+	case 2:
+		return "pointwise chunked"
+	case 3:
+		return "layered chunked"
+	}
+	return fmt.Sprintf("unknown (%v)", ci.Compressor)
+}
+
+func (ci CompressionInfo) String() string {
+	return fmt.Sprintf("%v compression, LASzip %v.%v r%v, chunk size %v",
+		ci.CompressorName(), ci.VersionMajor, ci.VersionMinor, ci.VersionRevision, ci.ChunkSize)
+}
+
+// CompressionInfo returns the compression parameters of the file. LASzip does
+// not pass its own VLR on to the reader, so the VLR is read directly from the
+// file.
+func (lf *LazFile) CompressionInfo() (CompressionInfo, error) {
+	r, err := lf.openRaw()
+	if err != nil {
+		return CompressionInfo{}, err
+	}
+	defer r.Close()
+	b, err := readRawVLR(r, laszipVLRUserID, laszipVLRRecordID)
+	if err != nil {
+		return CompressionInfo{}, err
+	}
+	return parseCompressionInfo(b)
+}
+
+func parseCompressionInfo(b []byte) (CompressionInfo, error) {
+	if len(b) < 16 {
+		return CompressionInfo{}, errors.New("the LASzip VLR is too short")
+	}
+	return CompressionInfo{
+		Compressor:      binary.LittleEndian.Uint16(b[0:2]),
+		Coder:           binary.LittleEndian.Uint16(b[2:4]),
+		VersionMajor:    b[4],
+		VersionMinor:    b[5],
+		VersionRevision: binary.LittleEndian.Uint16(b[6:8]),
+		Options:         binary.LittleEndian.Uint32(b[8:12]),
+		ChunkSize:       binary.LittleEndian.Uint32(b[12:16]),
+	}, nil
+}
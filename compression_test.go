@@ -0,0 +1,40 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseCompressionInfo(t *testing.T) {
+	b := make([]byte, 34)
+	binary.LittleEndian.PutUint16(b[0:2], 2)
+	b[4], b[5] = 3, 4
+	binary.LittleEndian.PutUint16(b[6:8], 9)
+	binary.LittleEndian.PutUint32(b[12:16], 50000)
+	ci, err := parseCompressionInfo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ci.CompressorName() != "pointwise chunked" || ci.ChunkSize != 50000 || ci.VersionMajor != 3 || ci.VersionMinor != 4 {
+		t.Errorf("unexpected compression info: %v", ci)
+	}
+	if _, err = parseCompressionInfo(b[:10]); err == nil {
+		t.Error("expected an error for a truncated VLR")
+	}
+}
+
+func TestCompressionInfo(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	ci, err := lf.CompressionInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ci.ChunkSize == 0 {
+		t.Errorf("expected a non-zero chunk size: %v", ci)
+	}
+}
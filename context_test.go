@@ -0,0 +1,68 @@
+package lidario
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLazCancelledContext(t *testing.T) {
+	// No point is decompressed once the context is cancelled, so a file that
+	// has no reader behind it is enough.
+	lf := &LazFile{fileMode: "r"}
+	lf.Header.NumberPoints = 10
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	points, err := lf.ReadPointsCtx(ctx, 0, 10)
+	if !errors.Is(err, context.Canceled) || len(points) != 0 {
+		t.Errorf("ReadPointsCtx returned %v points and %v, expected context.Canceled", len(points), err)
+	}
+	it, err := lf.PointsCtx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("expected the iterator to stop with context.Canceled, got %v", it.Err())
+	}
+	if _, err = lf.ComputeStatisticsCtx(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("ComputeStatisticsCtx returned %v, expected context.Canceled", err)
+	}
+}
+
+func TestLazContextCancelledMidScan(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it, err := lf.PointsCtx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for it.Next() {
+		n++
+		if n == 10 {
+			cancel()
+		}
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", it.Err())
+	}
+	if n >= lf.Header.NumberPoints {
+		t.Errorf("all %v points were read despite the cancellation", n)
+	}
+
+	// The progress callback runs between batches, so it can cancel a scan.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	lf.SetProgressFunc(func(done, total uint64) { cancel() })
+	if _, err = lf.ComputeStatisticsCtx(ctx); lf.Header.NumberPoints > progressInterval && !errors.Is(err, context.Canceled) {
+		t.Errorf("ComputeStatisticsCtx returned %v, expected context.Canceled", err)
+	}
+}
@@ -0,0 +1,166 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Compress converts the uncompressed LAS file srcLas to the LAZ file dstLaz.
+// Points are streamed from the source rather than compressed from memory, and
+// the header values and VLRs, including those describing the coordinate
+// system, are carried over. The point count and bounding box of the output
+// are checked against the points that were read. Point formats 0-3 are
+// supported. An existing output is not replaced unless WithOverwrite(true) is
+// supplied; the other options are applied as by NewLazWriter.
+func Compress(srcLas, dstLaz string, opts ...WriterOption) error {
+	if err := newWriterOptions(opts).checkOutput(dstLaz); err != nil {
+		return err
+	}
+	las, err := NewLasFile(srcLas, "rh")
+	if err != nil {
+		return err
+	}
+	defer las.Close()
+	if las.Header.PointFormatID > 3 {
+		return fmt.Errorf("point format %v is not supported", las.Header.PointFormatID)
+	}
+	if las.Header.PointRecordLength < pointFormatRecordLengths[las.Header.PointFormatID]-3 {
+		return errors.New("invalid point record length")
+	}
+	rr, err := newRecordReader(las)
+	if err != nil {
+		return err
+	}
+
+	vlrs := make([]VLR, 0, len(las.VlrData))
+	for _, vlr := range las.VlrData {
+		// LASzip adds its own record describing the compressor.
+		if vlr.UserID == laszipVLRUserID {
+			continue
+		}
+		vlrs = append(vlrs, vlr)
+	}
+	lw, err := NewLazWriter(dstLaz, las.Header, append(opts[:len(opts):len(opts)], WithVLRs(vlrs...))...)
+	if err != nil {
+		return err
+	}
+	b := newConvertBounds()
+	for i := 0; i < las.Header.NumberPoints; i++ {
+		record, err := rr.record(i)
+		if err != nil {
+			lw.Close()
+			return err
+		}
+		p := las.decodeRecord(record)
+		if err = lw.WritePoint(p); err != nil {
+			lw.Close()
+			return fmt.Errorf("point %v: %w", i, err)
+		}
+		b.add(p.PointData())
+	}
+	if err = lw.Close(); err != nil {
+		return err
+	}
+
+	lf, err := NewLazFile(dstLaz, "rh")
+	if err != nil {
+		return fmt.Errorf("failed to reopen %v: %w", dstLaz, err)
+	}
+	defer lf.Close()
+	return b.verify(dstLaz, lf.Header)
+}
+
+// Decompress converts the LAZ file srcLaz to the uncompressed LAS file dstLas.
+// It is the inverse of Compress: points are streamed from the source, the
+// header values and VLRs are carried over and the point count and bounding
+// box of the output are checked. Point formats 0-3 are supported. An existing
+// output is not replaced unless WithOverwrite(true) is supplied; the other
+// options are applied as by NewLasWriter.
+func Decompress(srcLaz, dstLas string, opts ...WriterOption) error {
+	if err := newWriterOptions(opts).checkOutput(dstLas); err != nil {
+		return err
+	}
+	lf, err := NewLazFile(srcLaz, "r")
+	if err != nil {
+		return err
+	}
+	defer lf.Close()
+
+	lw, err := NewLasWriter(dstLas, lf.Header, append(opts[:len(opts):len(opts)], WithVLRs(lf.VlrData...))...)
+	if err != nil {
+		return err
+	}
+	it, err := lf.Points()
+	if err != nil {
+		lw.Close()
+		return err
+	}
+	b := newConvertBounds()
+	for it.Next() {
+		p := it.Point()
+		if err = lw.WritePoint(p); err != nil {
+			lw.Close()
+			return fmt.Errorf("point %v: %w", b.count, err)
+		}
+		b.add(p.PointData())
+	}
+	if err = it.Err(); err != nil {
+		lw.Close()
+		return err
+	}
+	if err = lw.Close(); err != nil {
+		return err
+	}
+
+	las, err := NewLasFile(dstLas, "rh")
+	if err != nil {
+		return fmt.Errorf("failed to reopen %v: %w", dstLas, err)
+	}
+	defer las.Close()
+	return b.verify(dstLas, las.Header)
+}
+
+// convertBounds accumulates the number and extent of the points converted by
+// Compress and Decompress.
+type convertBounds struct {
+	count    int
+	min, max [3]float64
+}
+
+func newConvertBounds() *convertBounds {
+	return &convertBounds{
+		min: [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)},
+		max: [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)},
+	}
+}
+
+func (b *convertBounds) add(pd *PointRecord0) {
+	b.count++
+	for i, v := range [3]float64{pd.X, pd.Y, pd.Z} {
+		b.min[i] = math.Min(b.min[i], v)
+		b.max[i] = math.Max(b.max[i], v)
+	}
+}
+
+// verify checks the header of the converted file against the points that were
+// written. The bounds may differ from those read by up to half of the scale
+// factor, since the coordinates are stored as scaled integers.
+func (b *convertBounds) verify(fileName string, h LasHeader) error {
+	if h.NumberPoints != b.count {
+		return fmt.Errorf("%v holds %v points, expected %v", fileName, h.NumberPoints, b.count)
+	}
+	if b.count == 0 {
+		return nil
+	}
+	scales := [3]float64{h.XScaleFactor, h.YScaleFactor, h.ZScaleFactor}
+	mins := [3]float64{h.MinX, h.MinY, h.MinZ}
+	maxs := [3]float64{h.MaxX, h.MaxY, h.MaxZ}
+	for i, axis := range "XYZ" {
+		tolerance := scales[i]/2 + 1e-9*math.Max(math.Abs(b.min[i]), math.Abs(b.max[i]))
+		if math.Abs(mins[i]-b.min[i]) > tolerance || math.Abs(maxs[i]-b.max[i]) > tolerance {
+			return fmt.Errorf("%v has %c bounds [%v, %v], expected [%v, %v]", fileName, axis, mins[i], maxs[i], b.min[i], b.max[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package lidario
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// compareConverted checks that the points of a converted file match those of
+// the source to within the scale factor of the source.
+func compareConverted(t *testing.T, src, dst LidarFile, stride int) {
+	t.Helper()
+	if src.GetPointCount() != dst.GetPointCount() {
+		t.Fatalf("%v points, expected %v", dst.GetPointCount(), src.GetPointCount())
+	}
+	h := src.GetHeader()
+	for i := 0; i < int(src.GetPointCount()); i += stride {
+		x1, y1, z1, err := src.GetXYZ(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x2, y2, z2, err := dst.GetXYZ(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(x1-x2) > h.XScaleFactor || math.Abs(y1-y2) > h.YScaleFactor || math.Abs(z1-z2) > h.ZScaleFactor {
+			t.Fatalf("point %v: (%v, %v, %v), expected (%v, %v, %v)", i, x2, y2, z2, x1, y1, z1)
+		}
+	}
+	// Both LasFile and LazFile provide GetCRS.
+	type crsFile interface{ GetCRS() (string, error) }
+	want, _ := src.(crsFile).GetCRS()
+	if crs, err := dst.(crsFile).GetCRS(); err != nil || crs != want {
+		t.Errorf("CRS %q (%v), expected %q", crs, err, want)
+	}
+}
+
+func TestCompressDecompress(t *testing.T) {
+	requireLaszip(t)
+	dir := t.TempDir()
+	lazFile := filepath.Join(dir, "sample.laz")
+	lasFile := filepath.Join(dir, "sample.las")
+	if err := Compress("testdata/sample.las", lazFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := Decompress(lazFile, lasFile); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := NewLidarFile("testdata/sample.las", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	laz, err := NewLidarFile(lazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer laz.Close()
+	las, err := NewLidarFile(lasFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+	compareConverted(t, src, laz, 997)
+	compareConverted(t, src, las, 997)
+}
+
+func TestDecompressSampleLaz(t *testing.T) {
+	requireSampleLaz(t)
+	requireLaszip(t)
+	dir := t.TempDir()
+	lasFile := filepath.Join(dir, "sample.las")
+	lazFile := filepath.Join(dir, "sample.laz")
+	if err := Decompress(sampleLazFile, lasFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := Compress(lasFile, lazFile); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := NewLidarFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	laz, err := NewLidarFile(lazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer laz.Close()
+	compareConverted(t, src, laz, 1)
+}
+
+func TestConvertExistingOutput(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "exists")
+	if err := os.WriteFile(dst, []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Compress("testdata/sample.las", dst); !errors.Is(err, ErrOutputExists) {
+		t.Errorf("Compress: expected ErrOutputExists, got %v", err)
+	}
+	if err := Decompress("testdata/sample.las", dst); !errors.Is(err, ErrOutputExists) {
+		t.Errorf("Decompress: expected ErrOutputExists, got %v", err)
+	}
+	if b, _ := os.ReadFile(dst); string(b) != "keep" {
+		t.Errorf("the existing file was modified: %q", b)
+	}
+}
@@ -0,0 +1,88 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// CoordinateIterator iterates over the points of a LAS file, exposing both the
+// raw integer coordinates, as stored in the file, and the real-world
+// coordinates derived from them (raw*scale + offset). Each record is decoded
+// only once. Point records are read directly from the file in blocks (see
+// WithReadBufferSize), so the iterator also works for files opened in 'rh' mode.
+type CoordinateIterator struct {
+	las   *LasFile
+	rr    *recordReader
+	index int
+	raw   [3]int32
+	xyz   [3]float64
+	err   error
+	done  bool
+}
+
+// CoordinateIterator returns an iterator positioned before the first point.
+func (las *LasFile) CoordinateIterator() (*CoordinateIterator, error) {
+	if las.fileMode != "r" && las.fileMode != "rh" {
+		return nil, errors.New("the coordinate iterator requires a file opened in 'r' or 'rh' mode")
+	}
+	if las.Header.PointRecordLength < 12 {
+		return nil, errors.New("invalid point record length")
+	}
+	rr, err := newRecordReader(las)
+	if err != nil {
+		return nil, err
+	}
+	it := CoordinateIterator{
+		las:   las,
+		rr:    rr,
+		index: -1,
+	}
+	return &it, nil
+}
+
+// Next advances to the next point, returning false at the end of the points
+// or if an error occurs.
+func (it *CoordinateIterator) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+	it.index++
+	if it.index >= it.las.Header.NumberPoints {
+		it.done = true
+		return false
+	}
+	b, err := it.rr.record(it.index)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	h := &it.las.Header
+	it.raw[0] = int32(binary.LittleEndian.Uint32(b[0:4]))
+	it.raw[1] = int32(binary.LittleEndian.Uint32(b[4:8]))
+	it.raw[2] = int32(binary.LittleEndian.Uint32(b[8:12]))
+	it.xyz[0] = float64(it.raw[0])*h.XScaleFactor + h.XOffset
+	it.xyz[1] = float64(it.raw[1])*h.YScaleFactor + h.YOffset
+	it.xyz[2] = float64(it.raw[2])*h.ZScaleFactor + h.ZOffset
+	return true
+}
+
+// Index returns the index of the current point.
+func (it *CoordinateIterator) Index() int {
+	return it.index
+}
+
+// XYZ returns the real-world coordinates of the current point.
+func (it *CoordinateIterator) XYZ() [3]float64 {
+	return it.xyz
+}
+
+// RawXYZ returns the raw integer coordinates of the current point as stored in the file.
+func (it *CoordinateIterator) RawXYZ() [3]int32 {
+	return it.raw
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *CoordinateIterator) Err() error {
+	return it.err
+}
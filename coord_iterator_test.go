@@ -0,0 +1,41 @@
+package lidario
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCoordinateIterator(t *testing.T) {
+	lf, err := NewLasFile("testdata/sample.las", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	it, err := lf.CoordinateIterator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := lf.Header
+	count := 0
+	for it.Next() {
+		xyz := it.XYZ()
+		raw := it.RawXYZ()
+		if float64(raw[0])*h.XScaleFactor+h.XOffset != xyz[0] ||
+			float64(raw[1])*h.YScaleFactor+h.YOffset != xyz[1] ||
+			float64(raw[2])*h.ZScaleFactor+h.ZOffset != xyz[2] {
+			t.Fatalf("point %v: raw coordinates %v do not map onto %v", it.Index(), raw, xyz)
+		}
+		x, y, z, _ := lf.GetXYZ(it.Index())
+		if math.Abs(x-xyz[0]) > 1e-9 || math.Abs(y-xyz[1]) > 1e-9 || math.Abs(z-xyz[2]) > 1e-9 {
+			t.Fatalf("point %v: iterator coordinates %v differ from GetXYZ (%v, %v, %v)", it.Index(), xyz, x, y, z)
+		}
+		count++
+	}
+	if err = it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count != h.NumberPoints {
+		t.Errorf("expected %v points, iterated %v", h.NumberPoints, count)
+	}
+}
@@ -0,0 +1,277 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// CopcInfo holds the contents of the COPC info VLR (user ID "copc", record ID 1).
+type CopcInfo struct {
+	CenterX        float64
+	CenterY        float64
+	CenterZ        float64
+	HalfSize       float64
+	Spacing        float64
+	RootHierOffset uint64
+	RootHierSize   uint64
+	GpsTimeMinimum float64
+	GpsTimeMaximum float64
+}
+
+// VoxelKey identifies a node of the COPC octree.
+type VoxelKey struct {
+	Level int32
+	X     int32
+	Y     int32
+	Z     int32
+}
+
+// parent returns the key of the node containing this node.
+func (k VoxelKey) parent() VoxelKey {
+	return VoxelKey{Level: k.Level - 1, X: k.X >> 1, Y: k.Y >> 1, Z: k.Z >> 1}
+}
+
+func (k VoxelKey) String() string {
+	return fmt.Sprintf("%v-%v-%v-%v", k.Level, k.X, k.Y, k.Z)
+}
+
+// CopcNode is an entry of the COPC hierarchy describing an octree node.
+type CopcNode struct {
+	Key        VoxelKey
+	Offset     uint64
+	ByteSize   int32
+	PointCount int32
+}
+
+// nodeBounds returns the cube covered by an octree node.
+func (info *CopcInfo) nodeBounds(k VoxelKey) (minX, minY, minZ, maxX, maxY, maxZ float64) {
+	side := 2 * info.HalfSize / math.Pow(2, float64(k.Level))
+	minX = info.CenterX - info.HalfSize + float64(k.X)*side
+	minY = info.CenterY - info.HalfSize + float64(k.Y)*side
+	minZ = info.CenterZ - info.HalfSize + float64(k.Z)*side
+	return minX, minY, minZ, minX + side, minY + side, minZ + side
+}
+
+// ValidationIssue describes a problem found while validating a file.
+type ValidationIssue struct {
+	Check   string
+	Message string
+}
+
+func (vi ValidationIssue) String() string {
+	return fmt.Sprintf("%v: %v", vi.Check, vi.Message)
+}
+
+// copcFile provides raw access to the COPC structures of a file. The COPC
+// info VLR and the hierarchy pages are not compressed, so they are read
+// directly from the file rather than through LASzip.
+type copcFile struct {
+	f           rawReader
+	pointCount  uint64
+	info        CopcInfo
+	nodes       []CopcNode
+	pageIssues  []ValidationIssue
+	pagesLoaded map[uint64]bool
+}
+
+func openCopcFile(fileName string) (*copcFile, error) {
+	f, err := openRawFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return newCopcFile(f)
+}
+
+// newCopcFile reads the COPC structures of r, which is closed with the
+// copcFile.
+func newCopcFile(r rawReader) (*copcFile, error) {
+	cf := &copcFile{f: r, pagesLoaded: make(map[uint64]bool)}
+	if err := cf.readHeader(); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return cf, nil
+}
+
+func (cf *copcFile) Close() error {
+	return cf.f.Close()
+}
+
+// readHeader reads the point count and the COPC info VLR.
+func (cf *copcFile) readHeader() error {
+	header := make([]byte, 375)
+	if _, err := cf.f.ReadAt(header, 0); err != nil && err != io.EOF {
+		return err
+	}
+	if string(header[0:4]) != "LASF" {
+		return errors.New("the file is not a LAS/LAZ file")
+	}
+	cf.pointCount = uint64(binary.LittleEndian.Uint32(header[107:111]))
+	if header[24] == 1 && header[25] >= 4 {
+		if extended := binary.LittleEndian.Uint64(header[247:255]); extended != 0 {
+			cf.pointCount = extended
+		}
+	}
+
+	headerSize := int64(binary.LittleEndian.Uint16(header[94:96]))
+	numberOfVLRs := int(binary.LittleEndian.Uint32(header[100:104]))
+	offset := headerSize
+	vlrHeader := make([]byte, 54)
+	for i := 0; i < numberOfVLRs; i++ {
+		if _, err := cf.f.ReadAt(vlrHeader, offset); err != nil {
+			return err
+		}
+		userID := strings.TrimRight(string(vlrHeader[2:18]), "\x00 ")
+		recordID := binary.LittleEndian.Uint16(vlrHeader[18:20])
+		length := int64(binary.LittleEndian.Uint16(vlrHeader[20:22]))
+		if userID == "copc" && recordID == 1 {
+			b := make([]byte, 160)
+			if _, err := cf.f.ReadAt(b, offset+54); err != nil {
+				return err
+			}
+			cf.info = parseCopcInfo(b)
+			return nil
+		}
+		offset += 54 + length
+	}
+	return errors.New("the file does not contain a COPC info VLR")
+}
+
+func parseCopcInfo(b []byte) CopcInfo {
+	f64 := func(o int) float64 { return math.Float64frombits(binary.LittleEndian.Uint64(b[o : o+8])) }
+	return CopcInfo{
+		CenterX:        f64(0),
+		CenterY:        f64(8),
+		CenterZ:        f64(16),
+		HalfSize:       f64(24),
+		Spacing:        f64(32),
+		RootHierOffset: binary.LittleEndian.Uint64(b[40:48]),
+		RootHierSize:   binary.LittleEndian.Uint64(b[48:56]),
+		GpsTimeMinimum: f64(56),
+		GpsTimeMaximum: f64(64),
+	}
+}
+
+// loadHierarchy reads every hierarchy page, starting from the root page.
+func (cf *copcFile) loadHierarchy() error {
+	return cf.loadPage(cf.info.RootHierOffset, cf.info.RootHierSize)
+}
+
+func (cf *copcFile) loadPage(offset, size uint64) error {
+	if cf.pagesLoaded[offset] {
+		cf.pageIssues = append(cf.pageIssues, ValidationIssue{Check: "hierarchy",
+			Message: fmt.Sprintf("hierarchy page at offset %v is referenced more than once", offset)})
+		return nil
+	}
+	cf.pagesLoaded[offset] = true
+	if size%32 != 0 {
+		return fmt.Errorf("hierarchy page at offset %v has a size (%v) that is not a multiple of 32", offset, size)
+	}
+	// The size is checked before it is allocated, so that a corrupt entry
+	// cannot exhaust memory.
+	if fileSize := uint64(cf.f.Size()); size > fileSize || offset > fileSize-size {
+		return fmt.Errorf("%w: hierarchy page at offset %v (%v bytes) extends beyond the end of the file (%v bytes)",
+			ErrCorruptFile, offset, size, fileSize)
+	}
+	b := make([]byte, size)
+	if _, err := cf.f.ReadAt(b, int64(offset)); err != nil {
+		return fmt.Errorf("reading hierarchy page at offset %v: %v", offset, err)
+	}
+	for o := 0; o < len(b); o += 32 {
+		node := CopcNode{
+			Key: VoxelKey{
+				Level: int32(binary.LittleEndian.Uint32(b[o : o+4])),
+				X:     int32(binary.LittleEndian.Uint32(b[o+4 : o+8])),
+				Y:     int32(binary.LittleEndian.Uint32(b[o+8 : o+12])),
+				Z:     int32(binary.LittleEndian.Uint32(b[o+12 : o+16])),
+			},
+			Offset:     binary.LittleEndian.Uint64(b[o+16 : o+24]),
+			ByteSize:   int32(binary.LittleEndian.Uint32(b[o+24 : o+28])),
+			PointCount: int32(binary.LittleEndian.Uint32(b[o+28 : o+32])),
+		}
+		if node.PointCount == -1 {
+			// The entry refers to a child hierarchy page.
+			if node.ByteSize < 0 {
+				return fmt.Errorf("%w: hierarchy page at offset %v has a negative size (%v)", ErrCorruptFile, node.Offset, node.ByteSize)
+			}
+			if err := cf.loadPage(node.Offset, uint64(node.ByteSize)); err != nil {
+				return err
+			}
+			continue
+		}
+		cf.nodes = append(cf.nodes, node)
+	}
+	return nil
+}
+
+// ValidateCOPC walks the COPC hierarchy and checks that the node point counts
+// sum to the total in the header, that every node lies within the root cube
+// and has a parent node, and that the point data of every node lie within the
+// file. An empty slice is returned for a consistent file.
+func (lf *LazFile) ValidateCOPC() []ValidationIssue {
+	issues := []ValidationIssue{}
+	r, err := lf.openRaw()
+	if err != nil {
+		return append(issues, ValidationIssue{Check: "copc info", Message: err.Error()})
+	}
+	cf, err := newCopcFile(r)
+	if err != nil {
+		return append(issues, ValidationIssue{Check: "copc info", Message: err.Error()})
+	}
+	defer cf.Close()
+	if err = cf.loadHierarchy(); err != nil {
+		return append(issues, ValidationIssue{Check: "hierarchy", Message: err.Error()})
+	}
+	issues = append(issues, cf.pageIssues...)
+
+	nodes := make(map[VoxelKey]CopcNode, len(cf.nodes))
+	var total uint64
+	for _, node := range cf.nodes {
+		if _, ok := nodes[node.Key]; ok {
+			issues = append(issues, ValidationIssue{Check: "hierarchy",
+				Message: fmt.Sprintf("node %v appears more than once", node.Key)})
+		}
+		nodes[node.Key] = node
+		if node.PointCount < 0 {
+			issues = append(issues, ValidationIssue{Check: "point count",
+				Message: fmt.Sprintf("node %v has a negative point count (%v)", node.Key, node.PointCount)})
+			continue
+		}
+		total += uint64(node.PointCount)
+		if size := uint64(cf.f.Size()); node.ByteSize < 0 || node.Offset > size || uint64(node.ByteSize) > size-node.Offset {
+			issues = append(issues, ValidationIssue{Check: "data",
+				Message: fmt.Sprintf("the point data of node %v (%v bytes at offset %v) lie beyond the end of the file", node.Key, node.ByteSize, node.Offset)})
+		}
+	}
+	if total != cf.pointCount {
+		issues = append(issues, ValidationIssue{Check: "point count",
+			Message: fmt.Sprintf("node point counts sum to %v but the header declares %v points", total, cf.pointCount)})
+	}
+
+	for _, node := range cf.nodes {
+		k := node.Key
+		if k.Level < 0 || k.Level > 30 {
+			issues = append(issues, ValidationIssue{Check: "bounds",
+				Message: fmt.Sprintf("node %v has an invalid level", k)})
+			continue
+		}
+		cells := int32(1) << uint(k.Level)
+		if k.X < 0 || k.Y < 0 || k.Z < 0 || k.X >= cells || k.Y >= cells || k.Z >= cells {
+			issues = append(issues, ValidationIssue{Check: "bounds",
+				Message: fmt.Sprintf("node %v lies outside of the root octree cube", k)})
+			continue
+		}
+		if k.Level == 0 {
+			continue
+		}
+		if _, ok := nodes[k.parent()]; !ok {
+			issues = append(issues, ValidationIssue{Check: "hierarchy",
+				Message: fmt.Sprintf("node %v has no parent node %v", k, k.parent())})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,179 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CopcFile is a COPC (Cloud Optimized Point Cloud) file. The points of each
+// node of the octree are stored as a separate LAZ chunk, so the points of a
+// region can be read without decompressing the whole file. The embedded
+// LazFile gives access to the header and to the points in file order.
+type CopcFile struct {
+	*LazFile
+	Info CopcInfo
+	// nodes are the non-empty nodes of the hierarchy in file order
+	nodes []copcNodePoints
+}
+
+// copcNodePoints locates the points of an octree node. The nodes are stored
+// as consecutive chunks, so the index of the first point of a node is the sum
+// of the point counts of the nodes that precede it in the file.
+type copcNodePoints struct {
+	CopcNode
+	firstPoint int
+}
+
+// NewCopcFile opens a COPC file for reading and loads its hierarchy.
+func NewCopcFile(fileName string) (*CopcFile, error) {
+	info, nodes, err := readCopcHierarchy(fileName)
+	if err != nil {
+		return nil, err
+	}
+	lf, err := NewLazFile(fileName, "r")
+	if err != nil {
+		return nil, err
+	}
+	return &CopcFile{LazFile: lf, Info: info, nodes: nodes}, nil
+}
+
+// readCopcHierarchy reads the COPC info VLR and the hierarchy pages, and
+// locates the points of the non-empty nodes.
+func readCopcHierarchy(fileName string) (CopcInfo, []copcNodePoints, error) {
+	cf, err := openCopcFile(fileName)
+	if err != nil {
+		return CopcInfo{}, nil, err
+	}
+	defer cf.Close()
+	if err = cf.loadHierarchy(); err != nil {
+		return CopcInfo{}, nil, err
+	}
+
+	nodes := make([]copcNodePoints, 0, len(cf.nodes))
+	for _, node := range cf.nodes {
+		if node.PointCount > 0 {
+			nodes = append(nodes, copcNodePoints{CopcNode: node})
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Offset < nodes[j].Offset })
+	first := 0
+	for i := range nodes {
+		nodes[i].firstPoint = first
+		first += int(nodes[i].PointCount)
+	}
+	if uint64(first) != cf.pointCount {
+		return CopcInfo{}, nil, fmt.Errorf("%w: the COPC hierarchy holds %v points but the header declares %v",
+			ErrCorruptFile, first, cf.pointCount)
+	}
+	return cf.info, nodes, nil
+}
+
+// nodesInBounds returns the nodes whose cube intersects the given box.
+func (cf *CopcFile) nodesInBounds(minX, minY, minZ, maxX, maxY, maxZ float64) []copcNodePoints {
+	nodes := []copcNodePoints{}
+	for _, node := range cf.nodes {
+		nMinX, nMinY, nMinZ, nMaxX, nMaxY, nMaxZ := cf.Info.nodeBounds(node.Key)
+		if nMinX <= maxX && nMaxX >= minX && nMinY <= maxY && nMaxY >= minY && nMinZ <= maxZ && nMaxZ >= minZ {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// QueryBounds returns the points that lie within the given box, bounds
+// included. Only the octree nodes that intersect the box are decompressed,
+// which for a small box is a small fraction of the file. The points are
+// returned in file order.
+func (cf *CopcFile) QueryBounds(minX, minY, minZ, maxX, maxY, maxZ float64) ([]LasPointer, error) {
+	if minX > maxX || minY > maxY || minZ > maxZ {
+		return nil, errors.New("the minimum of the query bounds exceeds the maximum")
+	}
+	points := []LasPointer{}
+	for _, node := range cf.nodesInBounds(minX, minY, minZ, maxX, maxY, maxZ) {
+		nodePoints, err := cf.ReadPoints(node.firstPoint, int(node.PointCount))
+		if err != nil {
+			return points, fmt.Errorf("reading node %v: %w", node.Key, err)
+		}
+		for _, p := range nodePoints {
+			pd := p.PointData()
+			if pd.X >= minX && pd.X <= maxX && pd.Y >= minY && pd.Y <= maxY && pd.Z >= minZ && pd.Z <= maxZ {
+				points = append(points, p)
+			}
+		}
+	}
+	return points, nil
+}
+
+// nodesNear returns the nodes whose cube intersects the vertical cylinder of
+// the given radius around (cx, cy).
+func (cf *CopcFile) nodesNear(cx, cy, radius float64) []copcNodePoints {
+	nodes := []copcNodePoints{}
+	for _, node := range cf.nodesInBounds(cx-radius, cy-radius, math.Inf(-1), cx+radius, cy+radius, math.Inf(1)) {
+		nMinX, nMinY, _, nMaxX, nMaxY, _ := cf.Info.nodeBounds(node.Key)
+		if circleIntersectsRect(cx, cy, radius, nMinX, nMinY, nMaxX, nMaxY) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// ReadPointsNear returns the points whose XY coordinates lie within radius of
+// (cx, cy), the circle included. Unlike LazFile.ReadPointsNear, only the
+// octree nodes that intersect the circle are decompressed. The points are
+// returned in file order.
+func (cf *CopcFile) ReadPointsNear(cx, cy, radius float64) ([]LasPointer, error) {
+	if !(radius >= 0) {
+		return nil, errors.New("the radius must not be negative")
+	}
+	points := []LasPointer{}
+	h := &cf.Header
+	if !circleIntersectsRect(cx, cy, radius, h.MinX, h.MinY, h.MaxX, h.MaxY) {
+		return points, nil
+	}
+	r2 := radius * radius
+	for _, node := range cf.nodesNear(cx, cy, radius) {
+		nodePoints, err := cf.ReadPoints(node.firstPoint, int(node.PointCount))
+		if err != nil {
+			return points, fmt.Errorf("reading node %v: %w", node.Key, err)
+		}
+		for _, p := range nodePoints {
+			pd := p.PointData()
+			if dx, dy := pd.X-cx, pd.Y-cy; dx*dx+dy*dy <= r2 {
+				points = append(points, p)
+			}
+		}
+	}
+	return points, nil
+}
+
+// rangesToDepth returns the point ranges of the nodes at or above the given
+// octree depth, in file order.
+func (cf *CopcFile) rangesToDepth(maxDepth int) []pointRange {
+	ranges := []pointRange{}
+	for _, node := range cf.nodes {
+		if int(node.Key.Level) <= maxDepth {
+			ranges = append(ranges, pointRange{start: node.firstPoint, count: int(node.PointCount)})
+		}
+	}
+	return ranges
+}
+
+// QueryResolution returns an iterator over the points of the octree nodes at
+// or above maxDepth, the root being at depth 0. Each level holds points at
+// half the spacing of the level above, so a shallow query gives a fast, low
+// detail preview of a large cloud. The points are returned in file order.
+func (cf *CopcFile) QueryResolution(maxDepth int) (*PointIterator, error) {
+	if maxDepth < 0 {
+		return nil, errors.New("the octree depth must not be negative")
+	}
+	ranges := cf.rangesToDepth(maxDepth)
+	total := 0
+	for _, r := range ranges {
+		total += r.count
+	}
+	it := PointIterator{lf: cf.LazFile, buf: make([]LaszipPoint, pointIteratorBatchSize),
+		ranged: true, ranges: ranges, progress: newProgress(cf.progress, total)}
+	return &it, nil
+}
@@ -0,0 +1,222 @@
+package lidario
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// testCopcOctree returns a two level octree over the [0, 100] cube of
+// writeTestCopcFile: the root, its eight children and the eight children of
+// the first child. The offsets place the nodes out of key order.
+func testCopcOctree() []CopcNode {
+	nodes := []CopcNode{{Key: VoxelKey{0, 0, 0, 0}, Offset: 10000, PointCount: 100}}
+	for i := int32(0); i < 8; i++ {
+		nodes = append(nodes, CopcNode{Key: VoxelKey{1, i & 1, i >> 1 & 1, i >> 2}, Offset: 9000 - 100*uint64(i), PointCount: 100})
+		nodes = append(nodes, CopcNode{Key: VoxelKey{2, i & 1, i >> 1 & 1, i >> 2}, Offset: 20000 + 100*uint64(i), PointCount: 50})
+	}
+	// An empty node has no chunk.
+	return append(nodes, CopcNode{Key: VoxelKey{2, 3, 3, 3}})
+}
+
+func TestCopcHierarchy(t *testing.T) {
+	info, nodes, err := readCopcHierarchy(writeTestCopcFile(t, 1300, testCopcOctree()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.HalfSize != 50 || len(nodes) != 17 {
+		t.Fatalf("unexpected hierarchy: %+v, %v nodes", info, len(nodes))
+	}
+	first := 0
+	for i, node := range nodes {
+		if i > 0 && node.Offset <= nodes[i-1].Offset {
+			t.Errorf("node %v is out of file order", node.Key)
+		}
+		if node.firstPoint != first {
+			t.Errorf("node %v: first point %v, expected %v", node.Key, node.firstPoint, first)
+		}
+		first += int(node.PointCount)
+	}
+	if nodes[0].Key != (VoxelKey{1, 1, 1, 1}) {
+		t.Errorf("expected node 1-1-1-1 to come first, got %v", nodes[0].Key)
+	}
+
+	if _, _, err = readCopcHierarchy(writeTestCopcFile(t, 1000, testCopcOctree())); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("expected ErrCorruptFile for a point count mismatch, got %v", err)
+	}
+}
+
+func TestCopcNodesInBounds(t *testing.T) {
+	info, nodes, err := readCopcHierarchy(writeTestCopcFile(t, 1300, testCopcOctree()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf := &CopcFile{Info: info, nodes: nodes}
+
+	// A small box near the origin only touches the root, node 1-0-0-0 and
+	// node 2-0-0-0.
+	selected := cf.nodesInBounds(1, 1, 1, 2, 2, 2)
+	count := 0
+	for _, node := range selected {
+		count += int(node.PointCount)
+	}
+	if len(selected) != 3 || count != 250 {
+		t.Errorf("expected 3 nodes holding 250 of the 1300 points, got %v nodes holding %v points", len(selected), count)
+	}
+	if selected := cf.nodesInBounds(0, 0, 0, 100, 100, 100); len(selected) != len(nodes) {
+		t.Errorf("expected every node to intersect the root cube, got %v of %v", len(selected), len(nodes))
+	}
+	if selected := cf.nodesInBounds(200, 200, 200, 300, 300, 300); len(selected) != 0 {
+		t.Errorf("expected no nodes outside of the root cube, got %v", len(selected))
+	}
+	if _, err = cf.QueryBounds(2, 0, 0, 1, 1, 1); err == nil {
+		t.Error("expected an error for inverted bounds")
+	}
+}
+
+func TestCopcQueryBoundsSample(t *testing.T) {
+	if _, err := os.Stat(sampleCopcFile); err != nil {
+		t.Skipf("sample COPC file not available: %v", err)
+	}
+	requireLaszip(t)
+	cf, err := NewCopcFile(sampleCopcFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cf.Close()
+
+	h := cf.Header
+	dx, dy := (h.MaxX-h.MinX)/20, (h.MaxY-h.MinY)/20
+	cx, cy := (h.MinX+h.MaxX)/2, (h.MinY+h.MaxY)/2
+	minX, minY, maxX, maxY := cx-dx, cy-dy, cx+dx, cy+dy
+	points, err := cf.QueryBounds(minX, minY, h.MinZ, maxX, maxY, h.MaxZ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := 0
+	for _, node := range cf.nodesInBounds(minX, minY, h.MinZ, maxX, maxY, h.MaxZ) {
+		read += int(node.PointCount)
+	}
+	if read*4 > int(cf.GetPointCount()) {
+		t.Errorf("the query decompressed %v of %v points", read, cf.GetPointCount())
+	}
+
+	// The query must find the same points as a scan of the whole file.
+	it, err := cf.Points()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := 0
+	for it.Next() {
+		pd := it.Point().PointData()
+		if pd.X >= minX && pd.X <= maxX && pd.Y >= minY && pd.Y <= maxY {
+			expected++
+		}
+	}
+	if err = it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != expected {
+		t.Errorf("the query returned %v points, a scan found %v", len(points), expected)
+	}
+}
+
+func TestCopcRangesToDepth(t *testing.T) {
+	info, nodes, err := readCopcHierarchy(writeTestCopcFile(t, 1300, testCopcOctree()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf := &CopcFile{Info: info, nodes: nodes}
+	for depth, expected := range []int{100, 900, 1300, 1300} {
+		count := 0
+		for _, r := range cf.rangesToDepth(depth) {
+			count += r.count
+		}
+		if count != expected {
+			t.Errorf("depth %v: %v points, expected %v", depth, count, expected)
+		}
+	}
+	if _, err = cf.QueryResolution(-1); err == nil {
+		t.Error("expected an error for a negative depth")
+	}
+}
+
+func TestCopcQueryResolutionSample(t *testing.T) {
+	if _, err := os.Stat(sampleCopcFile); err != nil {
+		t.Skipf("sample COPC file not available: %v", err)
+	}
+	requireLaszip(t)
+	cf, err := NewCopcFile(sampleCopcFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cf.Close()
+
+	maxLevel := 0
+	root := 0
+	for _, node := range cf.nodes {
+		if int(node.Key.Level) > maxLevel {
+			maxLevel = int(node.Key.Level)
+		}
+		if node.Key.Level == 0 {
+			root = int(node.PointCount)
+		}
+	}
+	previous := 0
+	for depth := 0; depth <= maxLevel; depth++ {
+		it, err := cf.QueryResolution(depth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for it.Next() {
+			count++
+		}
+		if err = it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if depth == 0 && count != root {
+			t.Errorf("depth 0: %v points, expected the %v points of the root node", count, root)
+		}
+		if count <= previous {
+			t.Errorf("depth %v: %v points, expected more than the %v of depth %v", depth, count, previous, depth-1)
+		}
+		previous = count
+	}
+	if previous != int(cf.GetPointCount()) {
+		t.Errorf("the deepest query returned %v of %v points", previous, cf.GetPointCount())
+	}
+}
+
+func TestCopcNodesNear(t *testing.T) {
+	info, nodes, err := readCopcHierarchy(writeTestCopcFile(t, 1300, testCopcOctree()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf := &CopcFile{LazFile: &LazFile{fileMode: "r", Header: LasHeader{MaxX: 100, MaxY: 100, MaxZ: 100}},
+		Info: info, nodes: nodes}
+
+	// A small circle near the origin only touches the root and the columns
+	// of nodes 1-0-0-z and 2-0-0-z.
+	selected := cf.nodesNear(1, 1, 0.5)
+	count := 0
+	for _, node := range selected {
+		count += int(node.PointCount)
+	}
+	if len(selected) != 5 || count != 400 {
+		t.Errorf("expected 5 nodes holding 400 of the 1300 points, got %v nodes holding %v points", len(selected), count)
+	}
+	// The corner of the square around the circle lies in node 2-1-1-0,
+	// but the circle does not reach it.
+	for _, node := range cf.nodesNear(24, 24, 1.2) {
+		if node.Key == (VoxelKey{2, 1, 1, 0}) {
+			t.Errorf("node %v does not intersect the circle", node.Key)
+		}
+	}
+
+	// No node is read for a circle outside of the extent.
+	points, err := cf.ReadPointsNear(200, 200, 10)
+	if err != nil || len(points) != 0 {
+		t.Errorf("expected no points, got %v and %v", len(points), err)
+	}
+}
@@ -0,0 +1,151 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sampleCopcFile is the COPC file used by the COPC tests. Like the sample LAZ
+// file it is not distributed with the repository.
+const sampleCopcFile = "../PNOA_2020_AND_288-4006_ORT-CLA-IRC.copc.laz"
+
+// writeTestCopcFile writes a minimal COPC-style file holding a LAS 1.4 header,
+// the COPC info VLR and a single hierarchy page containing the nodes. The
+// file contains no point data; it is only suitable for hierarchy tests.
+func writeTestCopcFile(t *testing.T, pointCount uint64, nodes []CopcNode) string {
+	t.Helper()
+	const headerSize = 375
+	const vlrSize = 54 + 160
+	b := make([]byte, headerSize+vlrSize+32*len(nodes))
+
+	copy(b[0:4], "LASF")
+	b[24], b[25] = 1, 4
+	binary.LittleEndian.PutUint16(b[94:96], headerSize)
+	binary.LittleEndian.PutUint32(b[96:100], headerSize+vlrSize)
+	binary.LittleEndian.PutUint32(b[100:104], 1)
+	b[104] = 6 | 0x80
+	binary.LittleEndian.PutUint16(b[105:107], 30)
+	binary.LittleEndian.PutUint64(b[247:255], pointCount)
+
+	vlr := b[headerSize:]
+	copy(vlr[2:18], "copc")
+	binary.LittleEndian.PutUint16(vlr[18:20], 1)
+	binary.LittleEndian.PutUint16(vlr[20:22], 160)
+	info := vlr[54:]
+	for i, v := range []float64{50, 50, 50, 50, 1} {
+		binary.LittleEndian.PutUint64(info[i*8:i*8+8], math.Float64bits(v))
+	}
+	binary.LittleEndian.PutUint64(info[40:48], headerSize+vlrSize)
+	binary.LittleEndian.PutUint64(info[48:56], uint64(32*len(nodes)))
+
+	page := b[headerSize+vlrSize:]
+	for i, n := range nodes {
+		e := page[i*32:]
+		binary.LittleEndian.PutUint32(e[0:4], uint32(n.Key.Level))
+		binary.LittleEndian.PutUint32(e[4:8], uint32(n.Key.X))
+		binary.LittleEndian.PutUint32(e[8:12], uint32(n.Key.Y))
+		binary.LittleEndian.PutUint32(e[12:16], uint32(n.Key.Z))
+		binary.LittleEndian.PutUint64(e[16:24], n.Offset)
+		binary.LittleEndian.PutUint32(e[24:28], uint32(n.ByteSize))
+		binary.LittleEndian.PutUint32(e[28:32], uint32(n.PointCount))
+	}
+
+	fileName := filepath.Join(t.TempDir(), "test.copc.laz")
+	if err := os.WriteFile(fileName, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return fileName
+}
+
+func TestValidateCOPC(t *testing.T) {
+	nodes := []CopcNode{
+		{Key: VoxelKey{0, 0, 0, 0}, PointCount: 10},
+		{Key: VoxelKey{1, 0, 0, 0}, PointCount: 5},
+		{Key: VoxelKey{1, 1, 1, 1}, PointCount: 5},
+		{Key: VoxelKey{2, 3, 3, 3}, PointCount: 2},
+	}
+	fileName := writeTestCopcFile(t, 22, nodes)
+	lf := &LazFile{fileName: fileName}
+	if issues := lf.ValidateCOPC(); len(issues) != 0 {
+		t.Errorf("expected a consistent hierarchy, got %v", issues)
+	}
+}
+
+func TestValidateCOPCInconsistent(t *testing.T) {
+	nodes := []CopcNode{
+		{Key: VoxelKey{0, 0, 0, 0}, PointCount: 10},
+		{Key: VoxelKey{1, 0, 0, 0}, PointCount: 5},
+		{Key: VoxelKey{2, 0, 3, 0}, PointCount: 5}, // parent 1-0-1-0 is missing
+		{Key: VoxelKey{1, 2, 0, 0}, PointCount: 1}, // outside of the root cube
+	}
+	fileName := writeTestCopcFile(t, 100, nodes)
+	lf := &LazFile{fileName: fileName}
+	issues := lf.ValidateCOPC()
+
+	var countIssue, parentIssue, boundsIssue bool
+	for _, issue := range issues {
+		switch {
+		case issue.Check == "point count":
+			countIssue = true
+		case issue.Check == "hierarchy" && strings.Contains(issue.Message, "no parent"):
+			parentIssue = true
+		case issue.Check == "bounds":
+			boundsIssue = true
+		}
+	}
+	if !countIssue || !parentIssue || !boundsIssue {
+		t.Errorf("expected point count, parent and bounds issues, got %v", issues)
+	}
+}
+
+func TestValidateCOPCSample(t *testing.T) {
+	if _, err := os.Stat(sampleCopcFile); err != nil {
+		t.Skipf("sample COPC file not available: %v", err)
+	}
+	lf := &LazFile{fileName: sampleCopcFile}
+	if issues := lf.ValidateCOPC(); len(issues) != 0 {
+		t.Errorf("expected the sample COPC file to validate, got %v", issues)
+	}
+}
+
+func TestCopcFileTypeDetection(t *testing.T) {
+	fileName := writeTestCopcFile(t, 10, []CopcNode{{Key: VoxelKey{}, PointCount: 10}})
+	if fileType := GetFileType(fileName); fileType != "COPC" {
+		t.Errorf("GetFileType(%s) = %s, expected COPC", fileName, fileType)
+	}
+	if isCopcFile("testdata/sample.las") {
+		t.Error("a LAS file without the COPC info VLR was detected as COPC")
+	}
+}
+
+func TestValidateCOPCCorruptPages(t *testing.T) {
+	// A child page entry with a negative size must not be allocated.
+	fileName := writeTestCopcFile(t, 0, []CopcNode{
+		{Key: VoxelKey{0, 0, 0, 0}, Offset: 100, ByteSize: -32, PointCount: -1},
+	})
+	lf := &LazFile{fileName: fileName}
+	if issues := lf.ValidateCOPC(); len(issues) != 1 || issues[0].Check != "hierarchy" {
+		t.Errorf("negative page size: expected a hierarchy issue, got %v", issues)
+	}
+
+	// Nor one extending beyond the end of the file.
+	fileName = writeTestCopcFile(t, 0, []CopcNode{
+		{Key: VoxelKey{0, 0, 0, 0}, Offset: 100, ByteSize: 1 << 30, PointCount: -1},
+	})
+	lf = &LazFile{fileName: fileName}
+	if issues := lf.ValidateCOPC(); len(issues) != 1 || !strings.Contains(issues[0].Message, "beyond the end") {
+		t.Errorf("oversized page: expected a hierarchy issue, got %v", issues)
+	}
+
+	fileName = writeTestCopcFile(t, 5, []CopcNode{
+		{Key: VoxelKey{0, 0, 0, 0}, Offset: 1 << 40, ByteSize: 64, PointCount: 5},
+	})
+	lf = &LazFile{fileName: fileName}
+	if issues := lf.ValidateCOPC(); len(issues) != 1 || issues[0].Check != "data" {
+		t.Errorf("node data beyond the file: expected a data issue, got %v", issues)
+	}
+}
@@ -0,0 +1,30 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoCreationDate is returned when the header of a file does not record
+// the day or year of its creation, which are then zero.
+var ErrNoCreationDate = errors.New("the file does not record its creation date")
+
+// creationDate converts the day of year and year of the header to a date.
+func (h *LasHeader) creationDate() (time.Time, error) {
+	if h.FileCreationDay == 0 || h.FileCreationYear == 0 {
+		return time.Time{}, ErrNoCreationDate
+	}
+	jan1 := time.Date(h.FileCreationYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if h.FileCreationDay > jan1.AddDate(1, 0, -1).YearDay() {
+		return time.Time{}, fmt.Errorf("%w: creation day %v of year %v", ErrCorruptFile, h.FileCreationDay, h.FileCreationYear)
+	}
+	return jan1.AddDate(0, 0, h.FileCreationDay-1), nil
+}
+
+// CreationDate returns the date, in UTC, on which the file was created, as
+// recorded by the day of year and year of the header. ErrNoCreationDate is
+// returned if the header leaves either unset.
+func (lf *LazFile) CreationDate() (time.Time, error) {
+	return lf.Header.creationDate()
+}
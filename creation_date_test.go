@@ -0,0 +1,55 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCreationDate(t *testing.T) {
+	for _, c := range []struct {
+		day, year int
+		want      time.Time
+		err       error
+	}{
+		{1, 2020, time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC), nil},
+		{60, 2020, time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC), nil},
+		{366, 2020, time.Date(2020, time.December, 31, 0, 0, 0, 0, time.UTC), nil},
+		{366, 2021, time.Time{}, ErrCorruptFile},
+		{0, 2021, time.Time{}, ErrNoCreationDate},
+		{100, 0, time.Time{}, ErrNoCreationDate},
+	} {
+		h := LasHeader{FileCreationDay: c.day, FileCreationYear: c.year}
+		got, err := h.creationDate()
+		if !errors.Is(err, c.err) || !got.Equal(c.want) {
+			t.Errorf("day %v of %v: got %v (%v), expected %v (%v)", c.day, c.year, got, err, c.want, c.err)
+		}
+	}
+}
+
+func TestLazCreationDate(t *testing.T) {
+	fileName := writeHeaderOnlyLaz(t)
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.LittleEndian.PutUint16(data[90:92], 288)
+	binary.LittleEndian.PutUint16(data[92:94], 2019)
+	if err = os.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	lf, err := NewLazFile(fileName, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	got, err := lf.CreationDate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2019, time.October, 15, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
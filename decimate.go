@@ -0,0 +1,147 @@
+package lidario
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// ReadPointsDecimated returns every stride-th point of the file, starting with
+// the first, reading the file sequentially.
+func (lf *LazFile) ReadPointsDecimated(stride int) ([]LasPointer, error) {
+	if stride <= 0 {
+		return nil, errors.New("the stride must be positive")
+	}
+	it, err := lf.Points()
+	if err != nil {
+		return nil, err
+	}
+	points := make([]LasPointer, 0, (int(lf.GetPointCount64())+stride-1)/stride)
+	for i := 0; it.Next(); i++ {
+		if i%stride == 0 {
+			points = append(points, it.Point())
+		}
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return points, nil
+}
+
+// ReadPointsSampled returns at most maxPoints points, evenly spread through
+// the file, using the smallest stride that keeps the count within maxPoints.
+func (lf *LazFile) ReadPointsSampled(maxPoints int) ([]LasPointer, error) {
+	if maxPoints <= 0 {
+		return nil, errors.New("the maximum number of points must be positive")
+	}
+	return lf.ReadPointsDecimated(sampleStride(int(lf.GetPointCount64()), maxPoints))
+}
+
+// sampleStride returns the smallest stride that selects at most maxPoints of
+// count points.
+func sampleStride(count, maxPoints int) int {
+	stride := (count + maxPoints - 1) / maxPoints
+	if stride < 1 {
+		stride = 1
+	}
+	return stride
+}
+
+// SampleOption modifies the behaviour of ReservoirSample.
+type SampleOption func(*sampleOptions)
+
+type sampleOptions struct {
+	seed    int64
+	seedSet bool
+}
+
+// WithSeed seeds the random number generator of ReservoirSample, so that the
+// same points are chosen from the same file on every run. Without it the
+// generator is seeded from the clock.
+func WithSeed(seed int64) SampleOption {
+	return func(o *sampleOptions) {
+		o.seed = seed
+		o.seedSet = true
+	}
+}
+
+// ReservoirSample returns k points chosen uniformly at random from the file,
+// in file order, or every point if the file holds no more than k. The file
+// is read in a single sequential pass and only the k chosen points are held in
+// memory, however large the file. Unlike ReadPointsSampled, the sample is not
+// biased when the order of the points is spatially correlated.
+func (lf *LazFile) ReservoirSample(k int, opts ...SampleOption) ([]LasPointer, error) {
+	if k <= 0 {
+		return nil, errors.New("the sample size must be positive")
+	}
+	o := sampleOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.seedSet {
+		o.seed = time.Now().UnixNano()
+	}
+	r := newReservoir(k, rand.New(rand.NewSource(o.seed)))
+
+	it, err := lf.Points()
+	if err != nil {
+		return nil, err
+	}
+	for it.Next() {
+		// Only the points that are kept are converted.
+		if i := r.offer(); i >= 0 {
+			r.points[i] = it.Point()
+		}
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return r.sorted(), nil
+}
+
+// reservoir chooses k items uniformly at random from a stream of unknown
+// length (Vitter's algorithm R). The reservoir grows as items are kept, so a
+// large k does not allocate more than the stream holds.
+type reservoir struct {
+	rng     *rand.Rand
+	k       int
+	seen    int
+	points  []LasPointer
+	indices []int // the position in the stream of each point
+}
+
+func newReservoir(k int, rng *rand.Rand) *reservoir {
+	return &reservoir{rng: rng, k: k}
+}
+
+// offer counts the next item of the stream and returns the slot of the
+// reservoir that it takes, or -1 if it is not kept.
+func (r *reservoir) offer() int {
+	i := r.seen
+	r.seen++
+	if len(r.points) < r.k {
+		r.points = append(r.points, nil)
+		r.indices = append(r.indices, i)
+		return len(r.points) - 1
+	}
+	if j := r.rng.Intn(i + 1); j < len(r.points) {
+		r.indices[j] = i
+		return j
+	}
+	return -1
+}
+
+// sorted returns the points of the reservoir in stream order.
+func (r *reservoir) sorted() []LasPointer {
+	order := make([]int, len(r.points))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return r.indices[order[a]] < r.indices[order[b]] })
+	points := make([]LasPointer, len(order))
+	for i, j := range order {
+		points[i] = r.points[j]
+	}
+	return points
+}
@@ -0,0 +1,131 @@
+package lidario
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestReadPointsDecimated(t *testing.T) {
+	lf := &LazFile{fileMode: "r"}
+	for _, stride := range []int{0, -1} {
+		if _, err := lf.ReadPointsDecimated(stride); err == nil {
+			t.Errorf("expected an error for a stride of %v", stride)
+		}
+	}
+	if _, err := lf.ReadPointsSampled(0); err == nil {
+		t.Error("expected an error for a maximum of 0 points")
+	}
+
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	total := int(lf.GetPointCount())
+
+	const stride = 7
+	points, err := lf.ReadPointsDecimated(stride)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (total + stride - 1) / stride; len(points) != expected {
+		t.Errorf("expected %v points, got %v", expected, len(points))
+	}
+	second, err := lf.LasPoint(stride)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *points[1].PointData() != *second.PointData() {
+		t.Errorf("the second point is not point %v", stride)
+	}
+
+	sampled, err := lf.ReadPointsSampled(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sampled) > 1000 || len(sampled) == 0 {
+		t.Errorf("expected at most 1000 points, got %v", len(sampled))
+	}
+}
+
+func TestReservoir(t *testing.T) {
+	sample := func(seed int64, n, k int) []int {
+		r := newReservoir(k, rand.New(rand.NewSource(seed)))
+		for i := 0; i < n; i++ {
+			if slot := r.offer(); slot >= 0 {
+				r.points[slot] = &PointRecord0{X: float64(i)}
+			}
+		}
+		indices := []int{}
+		for _, p := range r.sorted() {
+			indices = append(indices, int(p.PointData().X))
+		}
+		return indices
+	}
+
+	// The same seed chooses the same points, in stream order.
+	first, second := sample(42, 10000, 20), sample(42, 10000, 20)
+	if len(first) != 20 || !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected the same 20 points for the same seed, got %v and %v", first, second)
+	}
+	if !sort.IntsAreSorted(first) {
+		t.Errorf("expected the points in stream order, got %v", first)
+	}
+	if reflect.DeepEqual(first, sample(43, 10000, 20)) {
+		t.Error("expected a different sample for a different seed")
+	}
+
+	// A short stream is returned whole, without allocating for k points.
+	for _, k := range []int{10, math.MaxInt32} {
+		if got := sample(1, 5, k); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4}) {
+			t.Errorf("k %v: expected every point of a short stream, got %v", k, got)
+		}
+	}
+
+	// Every item is equally likely to be chosen.
+	counts := make([]int, 10)
+	for seed := int64(0); seed < 2000; seed++ {
+		for _, i := range sample(seed, 10, 3) {
+			counts[i]++
+		}
+	}
+	for i, c := range counts {
+		// 600 expected; the standard deviation is about 20.
+		if c < 500 || c > 700 {
+			t.Errorf("item %v chosen %v times, expected about 600", i, c)
+		}
+	}
+}
+
+func TestReservoirSample(t *testing.T) {
+	if _, err := (&LazFile{fileMode: "r"}).ReservoirSample(0); err == nil {
+		t.Error("expected an error for a sample of 0 points")
+	}
+
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	first, err := lf.ReservoirSample(100, WithSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := lf.ReservoirSample(100, WithSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 100 || len(second) != 100 {
+		t.Fatalf("expected 100 points, got %v and %v", len(first), len(second))
+	}
+	for i := range first {
+		if *first[i].PointData() != *second[i].PointData() {
+			t.Fatalf("point %v differs between runs with the same seed", i)
+		}
+	}
+}
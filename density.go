@@ -0,0 +1,78 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PointDensity returns the average number of points per square meter of the
+// XY extent in the header. If the GeoKeys give the linear unit of a projected
+// coordinate system, such as US survey feet, the extent is converted to
+// meters; otherwise the coordinates are assumed to be in meters. Files in a
+// geographic coordinate system are rejected, since their extent is not an
+// area. Note that the extent includes any empty margins of the tile.
+func (lf *LazFile) PointDensity() (float64, error) {
+	meters, known, geographic := lf.geokeys.horizontalUnit()
+	if geographic {
+		return 0, errors.New("the coordinates are geographic; the density requires projected coordinates")
+	}
+	if !known {
+		meters = 1
+	}
+	h := &lf.Header
+	area := (h.MaxX - h.MinX) * (h.MaxY - h.MinY) * meters * meters
+	if !(area > 0) {
+		return 0, fmt.Errorf("the extent [%v, %v] x [%v, %v] has no area", h.MinX, h.MaxX, h.MinY, h.MaxY)
+	}
+	return float64(lf.GetPointCount64()) / area, nil
+}
+
+// DensityGrid counts the points that fall within each XY cell of the given
+// size, in the units of the coordinates, with a single pass over the points.
+// The grid is laid out as a Raster anchored on the header's minimum X and
+// maximum Y: the first row is the northern-most and the first column the
+// western-most. Dividing a count by the cell area gives the density of the
+// cell.
+func (lf *LazFile) DensityGrid(cellSize float64) ([][]int, error) {
+	h := &lf.Header
+	g, err := newDensityGrid(h.MinX, h.MinY, h.MaxX, h.MaxY, cellSize)
+	if err != nil {
+		return nil, err
+	}
+	it, err := lf.Points()
+	if err != nil {
+		return nil, err
+	}
+	for it.Next() {
+		p := it.laszipPoint()
+		g.add(p.X, p.Y)
+	}
+	if err = it.Err(); err != nil {
+		return nil, err
+	}
+	return g.counts, nil
+}
+
+// densityGrid counts points in the cells of a raster.
+type densityGrid struct {
+	raster *Raster
+	counts [][]int
+}
+
+func newDensityGrid(minX, minY, maxX, maxY, cellSize float64) (*densityGrid, error) {
+	r, err := newRaster(minX, minY, maxX, maxY, cellSize)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([][]int, r.Rows)
+	for i := range counts {
+		counts[i] = make([]int, r.Columns)
+	}
+	return &densityGrid{raster: r, counts: counts}, nil
+}
+
+func (g *densityGrid) add(x, y float64) {
+	if row, column, ok := g.raster.CellOf(x, y); ok {
+		g.counts[row][column]++
+	}
+}
@@ -0,0 +1,78 @@
+package lidario
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPointDensity(t *testing.T) {
+	h := LasHeader{MinX: 1000, MaxX: 1100, MinY: 2000, MaxY: 2050, NumberPoints: 10000}
+	lf := &LazFile{Header: h}
+	if d, err := lf.PointDensity(); err != nil || d != 2 {
+		t.Errorf("expected 2 points per square meter, got %v (%v)", d, err)
+	}
+
+	// The same extent in feet covers a smaller area.
+	lf.geokeys.GeoKeyDirectory = []uint16{1, 1, 0, 2, 1024, 0, 1, 1, 3076, 0, 1, 9002}
+	d, err := lf.PointDensity()
+	if expected := 2 / (0.3048 * 0.3048); err != nil || math.Abs(d-expected) > 1e-9 {
+		t.Errorf("expected %v points per square meter, got %v (%v)", expected, d, err)
+	}
+
+	lf.geokeys.GeoKeyDirectory = []uint16{1, 1, 0, 2, 1024, 0, 1, 2, 2048, 0, 1, 4326}
+	if _, err = lf.PointDensity(); err == nil {
+		t.Error("expected an error for geographic coordinates")
+	}
+	lf = &LazFile{Header: LasHeader{MinX: 5, MaxX: 5, MinY: 0, MaxY: 10, NumberPoints: 10}}
+	if _, err = lf.PointDensity(); err == nil {
+		t.Error("expected an error for an extent without area")
+	}
+}
+
+func TestDensityGridUniform(t *testing.T) {
+	// Two points per meter in X and Y over [0, 10] x [0, 10]
+	g, err := newDensityGrid(0, 0, 10, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 20; j++ {
+			g.add(0.25+0.5*float64(i), 0.25+0.5*float64(j))
+		}
+	}
+	total := 0
+	for row, counts := range g.counts {
+		for column, n := range counts {
+			total += n
+			// The last row and column hold the points on the maximum edges.
+			if row < 10 && column < 10 && n != 4 {
+				t.Errorf("cell (%v, %v) holds %v points, expected 4", row, column, n)
+			}
+		}
+	}
+	if total != 400 {
+		t.Errorf("the grid holds %v points, expected 400", total)
+	}
+}
+
+func TestDensityGridSample(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	grid, err := lf.DensityGrid(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, counts := range grid {
+		for _, n := range counts {
+			total += n
+		}
+	}
+	if total != lf.Header.NumberPoints {
+		t.Errorf("the grid holds %v of %v points", total, lf.Header.NumberPoints)
+	}
+}
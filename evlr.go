@@ -0,0 +1,111 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// waveformDataRecordID is the record ID of the EVLR that holds the waveform
+// data packets of a LAS 1.4 file.
+const waveformDataRecordID = 65535
+
+// EVLR is an extended variable length record. LAS 1.4 files store EVLRs after
+// the point data; unlike a VLR, the payload length is a 64-bit value.
+type EVLR struct {
+	Reserved                int
+	UserID                  string // 16 characters
+	RecordID                int
+	RecordLengthAfterHeader uint64
+	Description             string // 32 characters
+	BinaryData              []uint8
+}
+
+// vlr returns the record as a VLR so that it can be used in place of one,
+// e.g. to describe the coordinate system.
+func (e EVLR) vlr() VLR {
+	return VLR{
+		Reserved:                e.Reserved,
+		UserID:                  e.UserID,
+		RecordID:                e.RecordID,
+		RecordLengthAfterHeader: int(e.RecordLengthAfterHeader),
+		Description:             e.Description,
+		BinaryData:              e.BinaryData,
+	}
+}
+
+// evlrHeaderSize is the length of the header of an EVLR.
+const evlrHeaderSize = 60
+
+// parseEVLRHeader decodes the header of an EVLR, leaving the payload empty.
+func parseEVLRHeader(b []byte) EVLR {
+	return EVLR{
+		Reserved:                int(binary.LittleEndian.Uint16(b[0:2])),
+		UserID:                  strings.TrimRight(string(b[2:18]), "\x00 "),
+		RecordID:                int(binary.LittleEndian.Uint16(b[18:20])),
+		RecordLengthAfterHeader: binary.LittleEndian.Uint64(b[20:28]),
+		Description:             strings.TrimRight(string(b[28:60]), "\x00 "),
+	}
+}
+
+// readEVLRs reads count EVLRs starting at the given offset of a file of the
+// given size. The payload of the waveform data packet record, which can be as
+// large as the point data, is not read; GetWaveform reads the packets it needs.
+// The slice grows as records are read, since count comes from the header.
+func readEVLRs(r io.ReaderAt, size int64, start uint64, count int) ([]EVLR, error) {
+	var evlrs []EVLR
+	b := make([]byte, evlrHeaderSize)
+	offset := int64(start)
+	for i := 0; i < count; i++ {
+		if offset+evlrHeaderSize > size {
+			return evlrs, fmt.Errorf("%w: EVLR %v starts beyond the end of the file", ErrCorruptFile, i)
+		}
+		if _, err := r.ReadAt(b, offset); err != nil {
+			return evlrs, fmt.Errorf("reading EVLR %v: %v", i, err)
+		}
+		evlr := parseEVLRHeader(b)
+		offset += evlrHeaderSize
+		if evlr.RecordLengthAfterHeader > uint64(size-offset) {
+			return evlrs, fmt.Errorf("%w: the payload of EVLR %v extends beyond the end of the file", ErrCorruptFile, i)
+		}
+		if evlr.RecordID != waveformDataRecordID {
+			evlr.BinaryData = make([]byte, evlr.RecordLengthAfterHeader)
+			if _, err := r.ReadAt(evlr.BinaryData, offset); err != nil {
+				return evlrs, fmt.Errorf("reading EVLR %v: %v", i, err)
+			}
+		}
+		evlrs = append(evlrs, evlr)
+		offset += int64(evlr.RecordLengthAfterHeader)
+	}
+	return evlrs, nil
+}
+
+// readEVLRs reads the EVLRs of a LAS 1.4 file. LASzip only exposes the VLRs,
+// so the records are read from the file, or stream, directly.
+func (lf *LazFile) readEVLRs() error {
+	if lf.Header.NumberOfEVLRs == 0 || lf.Header.StartOfFirstEVLR == 0 {
+		return nil
+	}
+	r, err := lf.openRaw()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	evlrs, err := readEVLRs(r, r.Size(), lf.Header.StartOfFirstEVLR, lf.Header.NumberOfEVLRs)
+	if err != nil {
+		return err
+	}
+	lf.EVlrData = evlrs
+	for _, evlr := range evlrs {
+		lf.addVLR(evlr.vlr())
+	}
+	return nil
+}
+
+// GetEVLRs returns the extended variable length records of a LAS 1.4 file,
+// including their payloads. The payload of the waveform data packet record is
+// not loaded.
+func (lf *LazFile) GetEVLRs() []EVLR {
+	return lf.EVlrData
+}
@@ -0,0 +1,59 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// encodeEVLR encodes an EVLR header followed by its payload.
+func encodeEVLR(userID string, recordID int, data []byte) []byte {
+	b := make([]byte, 60)
+	copy(b[2:18], userID)
+	binary.LittleEndian.PutUint16(b[18:20], uint16(recordID))
+	binary.LittleEndian.PutUint64(b[20:28], uint64(len(data)))
+	copy(b[28:60], "test record")
+	return append(b, data...)
+}
+
+func TestLazEVLRs(t *testing.T) {
+	const wkt = `PROJCS["NAD83 / UTM zone 18N",AUTHORITY["EPSG","26918"]]`
+	const start = 100
+	b := make([]byte, start)
+	b = append(b, encodeEVLR("LASF_Projection", wktRecordID, []byte(wkt+"\x00"))...)
+	b = append(b, encodeEVLR("LASF_Spec", waveformDataRecordID, make([]byte, 1000))...)
+	fileName := filepath.Join(t.TempDir(), "evlrs.laz")
+	if err := os.WriteFile(fileName, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lf := &LazFile{fileName: fileName, Header: LasHeader{VersionMajor: 1, VersionMinor: 4, StartOfFirstEVLR: start, NumberOfEVLRs: 2}}
+	if err := lf.readEVLRs(); err != nil {
+		t.Fatal(err)
+	}
+	evlrs := lf.GetEVLRs()
+	if len(evlrs) != lf.Header.NumberOfEVLRs {
+		t.Fatalf("%v EVLRs, expected %v", len(evlrs), lf.Header.NumberOfEVLRs)
+	}
+	if evlrs[0].UserID != "LASF_Projection" || evlrs[0].RecordID != wktRecordID || evlrs[0].Description != "test record" {
+		t.Errorf("unexpected EVLR %+v", evlrs[0])
+	}
+	if evlrs[1].RecordLengthAfterHeader != 1000 || evlrs[1].BinaryData != nil {
+		t.Errorf("the waveform data payload should not be loaded, got %v of %v bytes", len(evlrs[1].BinaryData), evlrs[1].RecordLengthAfterHeader)
+	}
+	if crs, err := lf.GetCRS(); err != nil || crs != wkt {
+		t.Errorf("CRS %q (%v), expected the WKT of the EVLR", crs, err)
+	}
+
+	// A count that runs past the end of the file signals a corrupt file,
+	// however large it is.
+	for _, count := range []int{3, math.MaxInt32} {
+		lf = &LazFile{fileName: fileName, Header: LasHeader{VersionMajor: 1, VersionMinor: 4, StartOfFirstEVLR: start, NumberOfEVLRs: count}}
+		if err := lf.readEVLRs(); !errors.Is(err, ErrCorruptFile) {
+			t.Errorf("%v EVLRs: expected ErrCorruptFile, got %v", count, err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package lidario
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ExportField identifies an attribute written by ExportXYZ.
+type ExportField int
+
+// The attributes that can be exported. ExportRGB writes three columns.
+const (
+	ExportX ExportField = iota
+	ExportY
+	ExportZ
+	ExportIntensity
+	ExportClassification
+	ExportRGB
+	ExportGPSTime
+)
+
+var exportFieldNames = [...]string{"x", "y", "z", "intensity", "classification", "red,green,blue", "gps_time"}
+
+func (f ExportField) String() string {
+	if f < 0 || int(f) >= len(exportFieldNames) {
+		return fmt.Sprintf("ExportField(%d)", int(f))
+	}
+	return exportFieldNames[f]
+}
+
+// ExportOptions configures ExportXYZ.
+type ExportOptions struct {
+	// Fields lists the columns to write, in order. The default is X, Y and Z.
+	Fields []ExportField
+	// Delimiter separates the columns. The default is a comma.
+	Delimiter string
+	// Precision is the number of decimals written for the coordinates and GPS
+	// times. If zero, the coordinates are written with the number of decimals
+	// of the scale factors and the GPS times with six decimals.
+	Precision int
+	// Header writes a first line naming the columns.
+	Header bool
+}
+
+// hasRGB returns true for the point formats that store colour.
+func hasRGB(format uint8) bool {
+	switch format {
+	case 2, 3, 5, 7, 8, 10:
+		return true
+	}
+	return false
+}
+
+// hasGPSTime returns true for the point formats that store a GPS time.
+func hasGPSTime(format uint8) bool {
+	return format != 0 && format != 2
+}
+
+// scaleDecimals returns the number of decimals needed to represent values
+// stored with the given scale factor.
+func scaleDecimals(scale float64) int {
+	if scale <= 0 || scale >= 1 {
+		return 0
+	}
+	return int(math.Ceil(-math.Log10(scale) - 1e-9))
+}
+
+// ExportXYZ writes the points as delimited text, one point per line, with the
+// columns chosen in opts. The points are streamed to w, so memory use does not
+// grow with the size of the file.
+func (lf *LazFile) ExportXYZ(w io.Writer, opts ExportOptions) error {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = []ExportField{ExportX, ExportY, ExportZ}
+	}
+	delimiter := opts.Delimiter
+	if delimiter == "" {
+		delimiter = ","
+	}
+	precision, timePrecision := opts.Precision, opts.Precision
+	if precision == 0 {
+		h := &lf.Header
+		precision = scaleDecimals(math.Min(h.XScaleFactor, math.Min(h.YScaleFactor, h.ZScaleFactor)))
+		timePrecision = 6
+	}
+	format := lf.Header.PointFormatID
+	for _, f := range fields {
+		switch {
+		case f < ExportX || f > ExportGPSTime:
+			return fmt.Errorf("unknown export field %v", f)
+		case f == ExportRGB && !hasRGB(format):
+			return errors.New("the point format does not contain RGB data")
+		case f == ExportGPSTime && !hasGPSTime(format):
+			return errors.New("the point format does not contain GPS times")
+		}
+	}
+
+	it, err := lf.Points()
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if opts.Header {
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = strings.ReplaceAll(f.String(), ",", delimiter)
+		}
+		bw.WriteString(strings.Join(names, delimiter) + "\n")
+	}
+	xf := xyzFormat{fields: fields, delimiter: delimiter, precision: precision, timePrecision: timePrecision, mapClass: lf.mapClass}
+	var line []byte
+	for it.Next() {
+		line = xf.appendPoint(line[:0], it.Point().PointData(), it.laszipPoint())
+		if _, err = bw.Write(line); err != nil {
+			return err
+		}
+	}
+	if it.Err() != nil {
+		return it.Err()
+	}
+	return bw.Flush()
+}
+
+// xyzFormat formats the lines written by ExportXYZ.
+type xyzFormat struct {
+	fields                   []ExportField
+	delimiter                string
+	precision, timePrecision int
+	// mapClass translates the classes by the classification map of the file
+	mapClass func(uint8) uint8
+}
+
+// appendPoint appends the line of a point to line. The class, GPS time and
+// colour are read from the LASzip point, which holds them, including the
+// classes above 31 of point formats 6-10, for every point format.
+func (xf *xyzFormat) appendPoint(line []byte, pd *PointRecord0, lp *LaszipPoint) []byte {
+	for i, f := range xf.fields {
+		if i > 0 {
+			line = append(line, xf.delimiter...)
+		}
+		switch f {
+		case ExportX:
+			line = strconv.AppendFloat(line, pd.X, 'f', xf.precision, 64)
+		case ExportY:
+			line = strconv.AppendFloat(line, pd.Y, 'f', xf.precision, 64)
+		case ExportZ:
+			line = strconv.AppendFloat(line, pd.Z, 'f', xf.precision, 64)
+		case ExportIntensity:
+			line = strconv.AppendUint(line, uint64(pd.Intensity), 10)
+		case ExportClassification:
+			line = strconv.AppendUint(line, uint64(xf.mapClass(lp.Classification)), 10)
+		case ExportRGB:
+			line = strconv.AppendUint(line, uint64(lp.Red), 10)
+			line = append(line, xf.delimiter...)
+			line = strconv.AppendUint(line, uint64(lp.Green), 10)
+			line = append(line, xf.delimiter...)
+			line = strconv.AppendUint(line, uint64(lp.Blue), 10)
+		case ExportGPSTime:
+			line = strconv.AppendFloat(line, lp.GPSTime, 'f', xf.timePrecision, 64)
+		}
+	}
+	return append(line, '\n')
+}
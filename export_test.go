@@ -0,0 +1,99 @@
+package lidario
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestScaleDecimals(t *testing.T) {
+	cases := map[float64]int{0.01: 2, 0.001: 3, 0.0001: 4, 0.025: 2, 1: 0}
+	for scale, expected := range cases {
+		if d := scaleDecimals(scale); d != expected {
+			t.Errorf("scaleDecimals(%v) = %v, expected %v", scale, d, expected)
+		}
+	}
+}
+
+func TestExportXYZUnavailableFields(t *testing.T) {
+	// The fields are checked before any points are read.
+	lf := &LazFile{fileMode: "r", Header: LasHeader{PointFormatID: 0}}
+	var buf bytes.Buffer
+	for _, f := range []ExportField{ExportRGB, ExportGPSTime, ExportField(42)} {
+		if err := lf.ExportXYZ(&buf, ExportOptions{Fields: []ExportField{ExportX, f}}); err == nil {
+			t.Errorf("expected an error exporting %v from point format 0", f)
+		}
+	}
+}
+
+func TestExportXYZPointFormat7(t *testing.T) {
+	lf := &LazFile{fileMode: "r", Header: LasHeader{PointFormatID: 7}}
+	lp := &LaszipPoint{X: 1.5, Y: 2.25, Z: -3, Intensity: 40, Classification: 40, GPSTime: 123456.789, Red: 100, Green: 200, Blue: 300}
+	xf := xyzFormat{
+		fields:        []ExportField{ExportX, ExportY, ExportZ, ExportIntensity, ExportClassification, ExportRGB, ExportGPSTime},
+		delimiter:     ",",
+		precision:     2,
+		timePrecision: 3,
+		mapClass:      lf.mapClass,
+	}
+	// Class 40 does not fit in the five bits of the legacy class.
+	line := xf.appendPoint(nil, lf.convertPoint(lp).PointData(), lp)
+	if expected := "1.50,2.25,-3.00,40,40,100,200,300,123456.789\n"; string(line) != expected {
+		t.Errorf("got %q, expected %q", line, expected)
+	}
+	lf.SetClassificationMap(map[uint8]uint8{40: 5})
+	line = xf.appendPoint(nil, lf.convertPoint(lp).PointData(), lp)
+	if expected := "1.50,2.25,-3.00,40,5,100,200,300,123456.789\n"; string(line) != expected {
+		t.Errorf("with a classification map, got %q, expected %q", line, expected)
+	}
+}
+
+func TestExportXYZ(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	var buf bytes.Buffer
+	opts := ExportOptions{
+		Fields:    []ExportField{ExportX, ExportY, ExportZ, ExportIntensity, ExportClassification},
+		Delimiter: " ",
+		Precision: 3,
+		Header:    true,
+	}
+	if err = lf.ExportXYZ(&buf, opts); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if lines[0] != "x y z intensity classification" {
+		t.Errorf("unexpected header line %q", lines[0])
+	}
+	if len(lines) != int(lf.GetPointCount())+1 {
+		t.Fatalf("expected %v lines, got %v", lf.GetPointCount()+1, len(lines))
+	}
+	for i := 0; i < 10; i++ {
+		p, err := lf.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pd := p.PointData()
+		columns := strings.Fields(lines[i+1])
+		if len(columns) != 5 {
+			t.Fatalf("line %v: expected 5 columns, got %q", i+1, lines[i+1])
+		}
+		values := make([]float64, len(columns))
+		for j, c := range columns {
+			if values[j], err = strconv.ParseFloat(c, 64); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if math.Abs(values[0]-pd.X) > 5e-4 || math.Abs(values[1]-pd.Y) > 5e-4 || math.Abs(values[2]-pd.Z) > 5e-4 ||
+			values[3] != float64(pd.Intensity) || values[4] != float64(pd.ClassBitField.Classification()) {
+			t.Errorf("point %v: exported %v, expected %+v", i, values, pd)
+		}
+	}
+}
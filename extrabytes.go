@@ -0,0 +1,184 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// extraBytesRecordID is the record ID of the LASF_Spec VLR describing the
+// extra bytes stored at the end of each point record.
+const extraBytesRecordID = 4
+
+// extraBytesDescriptorSize is the length of a field descriptor of the Extra
+// Bytes VLR.
+const extraBytesDescriptorSize = 192
+
+// ErrNoExtraBytes is returned when an extra bytes field is requested from a
+// file without an Extra Bytes VLR describing it.
+var ErrNoExtraBytes = errors.New("the file does not describe the extra bytes field")
+
+// extraBytesTypeSizes are the sizes of the data types 1-10 of the Extra Bytes
+// VLR: uint8, int8, uint16, int16, uint32, int32, uint64, int64, float32 and
+// float64. Types 11-30 are the deprecated arrays of two and three of these.
+var extraBytesTypeSizes = [11]int{0, 1, 1, 2, 2, 4, 4, 8, 8, 4, 8}
+
+// ExtraBytesField describes a user-defined attribute stored in the extra
+// bytes of each point, such as the amplitude, reflectance or deviation
+// recorded by some sensors.
+type ExtraBytesField struct {
+	Name        string
+	Description string
+	// DataType is the type code of the Extra Bytes VLR; 0 denotes
+	// undocumented bytes, 1-10 the types listed in extraBytesTypeSizes.
+	DataType uint8
+	// Start is the offset of the field within the extra bytes of a point
+	Start int
+	// Size is the number of bytes of the field
+	Size int
+	// Scale and Offset convert the stored value to the attribute value,
+	// value = stored * Scale + Offset; Scaled is false if the descriptor
+	// defines neither.
+	Scale  float64
+	Offset float64
+	Scaled bool
+}
+
+// parseExtraBytesVLR parses the field descriptors of an Extra Bytes VLR. The
+// fields are stored in the order of the descriptors.
+func parseExtraBytesVLR(data []byte) ([]ExtraBytesField, error) {
+	if len(data)%extraBytesDescriptorSize != 0 {
+		return nil, fmt.Errorf("%w: the Extra Bytes VLR of %v bytes is not a whole number of descriptors",
+			ErrCorruptFile, len(data))
+	}
+	fields := make([]ExtraBytesField, 0, len(data)/extraBytesDescriptorSize)
+	start := 0
+	for i := 0; i < len(data); i += extraBytesDescriptorSize {
+		d := data[i : i+extraBytesDescriptorSize]
+		f := ExtraBytesField{
+			DataType:    d[2],
+			Name:        strings.TrimRight(string(d[4:36]), "\x00 "),
+			Description: strings.TrimRight(string(d[160:192]), "\x00 "),
+			Start:       start,
+			Scale:       1,
+		}
+		options := d[3]
+		switch {
+		case f.DataType == 0:
+			// The options hold the number of undocumented bytes.
+			f.Size = int(options)
+		case f.DataType <= 10:
+			f.Size = extraBytesTypeSizes[f.DataType]
+		case f.DataType <= 30:
+			f.Size = extraBytesTypeSizes[(f.DataType-1)%10+1] * (int(f.DataType-1)/10 + 1)
+		default:
+			return nil, fmt.Errorf("%w: extra bytes field %q has the unknown data type %v", ErrCorruptFile, f.Name, f.DataType)
+		}
+		// Only the first value of the scale and offset arrays applies to a
+		// field that is not a deprecated array.
+		if options&0x08 != 0 {
+			f.Scale = math.Float64frombits(binary.LittleEndian.Uint64(d[112:120]))
+			f.Scaled = true
+		}
+		if options&0x10 != 0 {
+			f.Offset = math.Float64frombits(binary.LittleEndian.Uint64(d[136:144]))
+			f.Scaled = true
+		}
+		fields = append(fields, f)
+		start += f.Size
+	}
+	return fields, nil
+}
+
+// value decodes the field from the extra bytes of a point. Numeric fields are
+// returned as the Go type of their data type, or as a float64 if the field is
+// scaled; undocumented bytes are returned as a []byte.
+func (f ExtraBytesField) value(extra []byte) (interface{}, error) {
+	if f.Start+f.Size > len(extra) {
+		return nil, fmt.Errorf("%w: the point has %v extra bytes but field %q ends at byte %v",
+			ErrCorruptFile, len(extra), f.Name, f.Start+f.Size)
+	}
+	b := extra[f.Start : f.Start+f.Size]
+	var v interface{}
+	var n float64
+	switch f.DataType {
+	case 0:
+		return append([]byte(nil), b...), nil
+	case 1:
+		v, n = b[0], float64(b[0])
+	case 2:
+		v, n = int8(b[0]), float64(int8(b[0]))
+	case 3:
+		u := binary.LittleEndian.Uint16(b)
+		v, n = u, float64(u)
+	case 4:
+		i := int16(binary.LittleEndian.Uint16(b))
+		v, n = i, float64(i)
+	case 5:
+		u := binary.LittleEndian.Uint32(b)
+		v, n = u, float64(u)
+	case 6:
+		i := int32(binary.LittleEndian.Uint32(b))
+		v, n = i, float64(i)
+	case 7:
+		u := binary.LittleEndian.Uint64(b)
+		v, n = u, float64(u)
+	case 8:
+		i := int64(binary.LittleEndian.Uint64(b))
+		v, n = i, float64(i)
+	case 9:
+		x := math.Float32frombits(binary.LittleEndian.Uint32(b))
+		v, n = x, float64(x)
+	case 10:
+		x := math.Float64frombits(binary.LittleEndian.Uint64(b))
+		v, n = x, x
+	default:
+		return nil, fmt.Errorf("extra bytes field %q has the deprecated array type %v, which is not supported", f.Name, f.DataType)
+	}
+	if f.Scaled {
+		return n*f.Scale + f.Offset, nil
+	}
+	return v, nil
+}
+
+// ExtraBytesFields returns the fields described by the Extra Bytes VLR of the
+// file, or an empty slice if the file has none.
+func (lf *LazFile) ExtraBytesFields() ([]ExtraBytesField, error) {
+	for _, vlr := range lf.VlrData {
+		if vlr.UserID == "LASF_Spec" && vlr.RecordID == extraBytesRecordID {
+			return parseExtraBytesVLR(vlr.BinaryData)
+		}
+	}
+	return []ExtraBytesField{}, nil
+}
+
+// GetExtraBytes returns the value of the named extra bytes field of a point.
+// Numeric fields are returned as the Go type of their data type (uint8, int8,
+// uint16, int16, uint32, int32, uint64, int64, float32 or float64), or as a
+// float64 if the Extra Bytes VLR defines a scale or offset for the field;
+// undocumented bytes are returned as a []byte.
+func (lf *LazFile) GetExtraBytes(pointIndex int, fieldName string) (interface{}, error) {
+	fields, err := lf.ExtraBytesFields()
+	if err != nil {
+		return nil, err
+	}
+	var field *ExtraBytesField
+	for i := range fields {
+		if fields[i].Name == fieldName {
+			field = &fields[i]
+			break
+		}
+	}
+	if field == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNoExtraBytes, fieldName)
+	}
+
+	lf.Lock()
+	defer lf.Unlock()
+	if _, err = lf.readPoint(pointIndex); err != nil {
+		return nil, err
+	}
+	return field.value(lf.reader.ExtraBytes())
+}
@@ -0,0 +1,69 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// encodeExtraBytesField encodes a descriptor of the Extra Bytes VLR.
+func encodeExtraBytesField(name string, dataType, options uint8, scale, offset float64) []byte {
+	d := make([]byte, extraBytesDescriptorSize)
+	d[2] = dataType
+	d[3] = options
+	copy(d[4:36], name)
+	binary.LittleEndian.PutUint64(d[112:120], math.Float64bits(scale))
+	binary.LittleEndian.PutUint64(d[136:144], math.Float64bits(offset))
+	copy(d[160:192], name+" description")
+	return d
+}
+
+func TestExtraBytes(t *testing.T) {
+	var vlr []byte
+	vlr = append(vlr, encodeExtraBytesField("Amplitude", 3, 0, 0, 0)...)
+	vlr = append(vlr, encodeExtraBytesField("Reflectance", 4, 0x18, 0.01, -10)...)
+	vlr = append(vlr, encodeExtraBytesField("Padding", 0, 3, 0, 0)...)
+	vlr = append(vlr, encodeExtraBytesField("Deviation", 10, 0, 0, 0)...)
+	fields, err := parseExtraBytesVLR(vlr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	starts := []int{0, 2, 4, 7}
+	for i, f := range fields {
+		if f.Start != starts[i] {
+			t.Errorf("field %q starts at %v, expected %v", f.Name, f.Start, starts[i])
+		}
+	}
+	if fields[1].Description != "Reflectance description" || !fields[1].Scaled || fields[0].Scaled {
+		t.Errorf("unexpected descriptors %+v", fields)
+	}
+
+	extra := make([]byte, 15)
+	binary.LittleEndian.PutUint16(extra[0:], 1234)
+	binary.LittleEndian.PutUint16(extra[2:], uint16(0xFFFF-99)) // -100
+	copy(extra[4:7], []byte{1, 2, 3})
+	binary.LittleEndian.PutUint64(extra[7:], math.Float64bits(0.25))
+	expected := []interface{}{uint16(1234), -11.0, []byte{1, 2, 3}, 0.25}
+	for i, f := range fields {
+		v, err := f.value(extra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(v, expected[i]) {
+			t.Errorf("field %q: got %#v, expected %#v", f.Name, v, expected[i])
+		}
+	}
+	if _, err = fields[3].value(extra[:10]); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("expected ErrCorruptFile for truncated extra bytes, got %v", err)
+	}
+
+	if _, err = parseExtraBytesVLR(vlr[:100]); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("expected ErrCorruptFile for a truncated VLR, got %v", err)
+	}
+	lf := &LazFile{VlrData: []VLR{{UserID: "LASF_Spec", RecordID: extraBytesRecordID, BinaryData: vlr}}}
+	if _, err = lf.GetExtraBytes(0, "Intensity"); !errors.Is(err, ErrNoExtraBytes) {
+		t.Errorf("expected ErrNoExtraBytes for an unknown field, got %v", err)
+	}
+}
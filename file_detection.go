@@ -1,68 +1,118 @@
 package lidario
 
 import (
+	"encoding/binary"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
-// isLazFile determines if a file is a LAZ file based on extension and magic bytes
+// isLazFile determines if a file is a LAZ file from its content
 func isLazFile(filename string) bool {
-	// Quick check by file extension
-	ext := strings.ToLower(filepath.Ext(filename))
-	if ext != ".laz" {
-		return false
-	}
-	
-	// Verify by reading magic bytes - LAZ files start with "LASF" like LAS files
-	// but have compressed data after the header
+	return detectFileType(filename) == "LAZ"
+}
+
+// isLasFile determines if a file is an uncompressed LAS file from its content
+func isLasFile(filename string) bool {
+	return detectFileType(filename) == "LAS"
+}
+
+// detectFileType returns "LAZ" or "LAS" for files starting with the "LASF"
+// signature, depending on whether the point data are compressed, and
+// "UNKNOWN" otherwise. The extension is only used as a hint: LASzip sets the
+// high bit of the point data format, so a file named .las without that bit is
+// taken to be uncompressed without scanning its VLRs for the LASzip record.
+func detectFileType(filename string) string {
 	file, err := os.Open(filename)
 	if err != nil {
-		return false
+		return "UNKNOWN"
 	}
 	defer file.Close()
-	
-	// Read the first 4 bytes to check for LAS/LAZ signature
-	signature := make([]byte, 4)
-	n, err := file.Read(signature)
-	if err != nil || n != 4 {
-		return false
+
+	header := make([]byte, 105)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return "UNKNOWN"
+	}
+	if string(header[0:4]) != "LASF" {
+		return "UNKNOWN"
 	}
-	
-	// Both LAS and LAZ files start with "LASF"
-	return string(signature) == "LASF"
+	if header[104]&0xC0 != 0 {
+		return "LAZ"
+	}
+	if strings.ToLower(filepath.Ext(filename)) == ".las" {
+		return "LAS"
+	}
+	if isCompressedFile(filename) {
+		return "LAZ"
+	}
+	return "LAS"
 }
 
-// isLasFile determines if a file is an uncompressed LAS file
-func isLasFile(filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-	if ext != ".las" {
-		return false
-	}
-	
-	// Verify by reading magic bytes
-	file, err := os.Open(filename)
+// isCopcFile determines if a file is a Cloud Optimized Point Cloud by looking
+// for the COPC info VLR (user ID "copc", record ID 1).
+func isCopcFile(filename string) bool {
+	cf, err := openCopcFile(filename)
 	if err != nil {
 		return false
 	}
-	defer file.Close()
-	
-	signature := make([]byte, 4)
-	n, err := file.Read(signature)
-	if err != nil || n != 4 {
-		return false
-	}
-	
-	return string(signature) == "LASF"
+	cf.Close()
+	return true
 }
 
-// GetFileType returns the detected file type
+// GetFileType returns the detected file type: "COPC", "LAZ", "LAS" or "UNKNOWN".
 func GetFileType(filename string) string {
 	if isLazFile(filename) {
+		if isCopcFile(filename) {
+			return "COPC"
+		}
 		return "LAZ"
 	}
 	if isLasFile(filename) {
 		return "LAS"
 	}
 	return "UNKNOWN"
-}
\ No newline at end of file
+}
+
+// isCompressedFile determines if a file holds LASzip-compressed point data by
+// inspecting its content rather than its extension. LASzip marks compressed
+// files by setting the high bits of the point data format byte and by adding
+// a "laszip encoded" VLR (record ID 22204).
+func isCompressedFile(filename string) bool {
+	file, err := os.Open(filename)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, 227)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	if string(header[0:4]) != "LASF" {
+		return false
+	}
+
+	// Bits 7 and 6 of the point data format are set in compressed files.
+	if header[104]&0xC0 != 0 {
+		return true
+	}
+
+	// Otherwise look for the LASzip VLR.
+	headerSize := int64(binary.LittleEndian.Uint16(header[94:96]))
+	numberOfVLRs := int(binary.LittleEndian.Uint32(header[100:104]))
+	offset := headerSize
+	vlrHeader := make([]byte, 54)
+	for i := 0; i < numberOfVLRs; i++ {
+		if _, err := file.ReadAt(vlrHeader, offset); err != nil {
+			return false
+		}
+		userID := strings.TrimRight(string(vlrHeader[2:18]), "\x00 ")
+		recordID := binary.LittleEndian.Uint16(vlrHeader[18:20])
+		if userID == laszipVLRUserID && recordID == laszipVLRRecordID {
+			return true
+		}
+		offset += 54 + int64(binary.LittleEndian.Uint16(vlrHeader[20:22]))
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package lidario
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// geoJSONFeature is a point feature of the GeoJSON export.
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   geoJSONPoint      `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [3]float64 `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	Classification uint8  `json:"classification"`
+	Intensity      uint16 `json:"intensity"`
+}
+
+// geoJSONWriter streams a FeatureCollection one feature at a time.
+type geoJSONWriter struct {
+	w        *bufio.Writer
+	features int
+}
+
+// begin writes the opening of the collection. The coordinate system is given
+// in a "crs" member; if it is unknown, a "warning" member is written instead.
+func (gw *geoJSONWriter) begin(crs string) error {
+	gw.w.WriteString(`{"type":"FeatureCollection",`)
+	member, value := "crs", crs
+	if crs == "" {
+		member, value = "warning", "the coordinate reference system of the file is unknown; coordinates are in the file's units"
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	gw.w.WriteString(`"` + member + `":`)
+	gw.w.Write(b)
+	_, err = gw.w.WriteString(`,"features":[`)
+	return err
+}
+
+// writeFeature writes a point as a feature of the given class, which is
+// passed separately so that it can be translated by the classification map.
+func (gw *geoJSONWriter) writeFeature(lp *LaszipPoint, class uint8) error {
+	b, err := json.Marshal(geoJSONFeature{
+		Type:       "Feature",
+		Geometry:   geoJSONPoint{Type: "Point", Coordinates: [3]float64{lp.X, lp.Y, lp.Z}},
+		Properties: geoJSONProperties{Classification: class, Intensity: lp.Intensity},
+	})
+	if err != nil {
+		return err
+	}
+	if gw.features > 0 {
+		gw.w.WriteByte(',')
+	}
+	gw.features++
+	_, err = gw.w.Write(b)
+	return err
+}
+
+func (gw *geoJSONWriter) end() error {
+	gw.w.WriteString("]}\n")
+	return gw.w.Flush()
+}
+
+// ExportGeoJSON writes a GeoJSON FeatureCollection of Point features, with the
+// classification and intensity of each point as properties. The points are
+// decimated to at most maxPoints (see ReadPointsSampled) and streamed to w.
+// The coordinates are not reprojected: they are in the coordinate system of
+// the file, which is named in a "crs" member when it is known (see GetCRS).
+func (lf *LazFile) ExportGeoJSON(w io.Writer, maxPoints int) error {
+	if maxPoints <= 0 {
+		return errors.New("the maximum number of points must be positive")
+	}
+	stride := sampleStride(int(lf.GetPointCount64()), maxPoints)
+	crs, err := lf.GetCRS()
+	if err != nil && err != ErrNoCRS {
+		return err
+	}
+	it, err := lf.Points()
+	if err != nil {
+		return err
+	}
+	gw := &geoJSONWriter{w: bufio.NewWriter(w)}
+	if err = gw.begin(crs); err != nil {
+		return err
+	}
+	for i := 0; it.Next() && gw.features < maxPoints; i++ {
+		if i%stride != 0 {
+			continue
+		}
+		// The LASzip point holds the classes above 31 of point formats 6-10.
+		lp := it.laszipPoint()
+		if err = gw.writeFeature(lp, lf.mapClass(lp.Classification)); err != nil {
+			return err
+		}
+	}
+	if it.Err() != nil {
+		return it.Err()
+	}
+	return gw.end()
+}
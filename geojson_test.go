@@ -0,0 +1,79 @@
+package lidario
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// geoJSONCollection is the decoded output of the GeoJSON export.
+type geoJSONCollection struct {
+	Type     string           `json:"type"`
+	CRS      string           `json:"crs"`
+	Warning  string           `json:"warning"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+func TestGeoJSONWriter(t *testing.T) {
+	// Class 40 of point formats 6-10 does not fit in the legacy class.
+	p := &LaszipPoint{X: 431000.25, Y: 4582000.5, Z: 12.75, Intensity: 300, Classification: 40}
+	for _, crs := range []string{"", "EPSG:25830"} {
+		var buf bytes.Buffer
+		gw := &geoJSONWriter{w: bufio.NewWriter(&buf)}
+		if err := gw.begin(crs); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := gw.writeFeature(p, p.Classification); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := gw.end(); err != nil {
+			t.Fatal(err)
+		}
+
+		var fc geoJSONCollection
+		if err := json.Unmarshal(buf.Bytes(), &fc); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.Bytes())
+		}
+		if fc.Type != "FeatureCollection" || len(fc.Features) != 3 {
+			t.Fatalf("unexpected collection %+v", fc)
+		}
+		if fc.CRS != crs || (crs == "") != (fc.Warning != "") {
+			t.Errorf("crs %q: got crs %q and warning %q", crs, fc.CRS, fc.Warning)
+		}
+		f := fc.Features[0]
+		if f.Geometry.Type != "Point" || f.Geometry.Coordinates != [3]float64{p.X, p.Y, p.Z} ||
+			f.Properties.Classification != 40 || f.Properties.Intensity != 300 {
+			t.Errorf("unexpected feature %+v", f)
+		}
+	}
+}
+
+func TestExportGeoJSON(t *testing.T) {
+	lf := &LazFile{fileMode: "r"}
+	if err := lf.ExportGeoJSON(&bytes.Buffer{}, 0); err == nil {
+		t.Error("expected an error for a maximum of 0 points")
+	}
+
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	for _, maxPoints := range []int{1, 999, int(lf.GetPointCount()) + 10} {
+		var buf bytes.Buffer
+		if err = lf.ExportGeoJSON(&buf, maxPoints); err != nil {
+			t.Fatal(err)
+		}
+		var fc geoJSONCollection
+		if err = json.Unmarshal(buf.Bytes(), &fc); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(fc.Features) == 0 || len(fc.Features) > maxPoints {
+			t.Errorf("maxPoints %v: got %v features", maxPoints, len(fc.Features))
+		}
+	}
+}
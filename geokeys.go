@@ -15,12 +15,105 @@ type GeoKeys struct {
 	GeoDoubleParams []float64
 	GeoASCIIParams  string
 	Tags            []GeoTiffTag
+	// OGC coordinate system WKT (record ID 2112)
+	WKT string
+}
+
+// ErrNoCRS is returned when a file does not describe its coordinate reference system.
+var ErrNoCRS = errors.New("the file does not contain coordinate reference system information")
+
+// addVLR adds the georeferencing information carried by a VLR, if any. Record
+// 2111 holds a math transform rather than a coordinate system and is ignored.
+func (gk *GeoKeys) addVLR(vlr VLR) {
+	switch vlr.RecordID {
+	case 34735:
+		// GeoKey directory
+		gk.addKeyDirectory(vlr.BinaryData)
+	case 34736:
+		// Double GeoKey parameters
+		gk.addDoubleParams(vlr.BinaryData)
+	case 34737:
+		// ASCII GeoKey parameters
+		gk.addASCIIParams(vlr.BinaryData)
+	case wktRecordID:
+		gk.WKT = strings.TrimRight(string(vlr.BinaryData), "\x00 ")
+	}
+}
+
+// codes returns the values of the keys of the GeoKey directory that hold an
+// EPSG code, i.e. whose value is stored in the directory itself. User-defined
+// (32767) and undefined (0) codes are not reported.
+func (gk *GeoKeys) codes() map[uint16]int {
+	codes := map[uint16]int{}
+	if len(gk.GeoKeyDirectory) < 4 {
+		return codes
+	}
+	numKeys := int(gk.GeoKeyDirectory[3])
+	for i := 0; i < numKeys; i++ {
+		offset := 4 * (i + 1)
+		if offset+3 >= len(gk.GeoKeyDirectory) {
+			break
+		}
+		keyID := gk.GeoKeyDirectory[offset]
+		tiffTagLocation := gk.GeoKeyDirectory[offset+1]
+		value := gk.GeoKeyDirectory[offset+3]
+		if tiffTagLocation == 0 && value != 0 && value != 32767 {
+			codes[keyID] = int(value)
+		}
+	}
+	return codes
+}
+
+// epsgCode returns the EPSG code of the projected or, failing that, the
+// geographic coordinate system in the GeoKey directory.
+func (gk *GeoKeys) epsgCode() (int, bool) {
+	codes := gk.codes()
+	if code, ok := codes[tProjectedCSTypeGeoKey]; ok {
+		return code, true
+	}
+	if code, ok := codes[tGeographicTypeGeoKey]; ok {
+		return code, true
+	}
+	return 0, false
+}
+
+// linearUnitMeters are the lengths in meters of the linear units of
+// projected coordinate systems, by EPSG unit code.
+var linearUnitMeters = map[int]float64{
+	9001: 1,
+	9002: 0.3048,
+	9003: 1200.0 / 3937,
+}
+
+// horizontalUnit returns the length in meters of the horizontal coordinate
+// unit, which is only known for a projected coordinate system whose linear
+// unit is given in the GeoKey directory. geographic is true if the model type
+// of the GeoKeys is geographic, i.e. the coordinates are angles.
+func (gk *GeoKeys) horizontalUnit() (meters float64, known, geographic bool) {
+	codes := gk.codes()
+	if codes[tGTModelTypeGeoKey] == 2 {
+		return 0, false, true
+	}
+	meters, known = linearUnitMeters[codes[tProjLinearUnitsGeoKey]]
+	return meters, known, false
+}
+
+// crs returns the WKT of the coordinate system, which takes precedence, or
+// an "EPSG:<code>" string derived from the GeoKeys.
+func (gk *GeoKeys) crs() (string, error) {
+	if gk.WKT != "" {
+		return gk.WKT, nil
+	}
+	if code, ok := gk.epsgCode(); ok {
+		return fmt.Sprintf("EPSG:%v", code), nil
+	}
+	return "", ErrNoCRS
 }
 
 func (gk *GeoKeys) addKeyDirectory(data []uint8) {
 	// convert the binary data to an array of u16's
 	i := 0
-	for i < len(data) {
+	for i+2 <= len(data) {
 		k := binary.LittleEndian.Uint16(data[i : i+2])
 		// k := uint16(data[i]) | (uint16(data[i+1]) << uint16(8))
 		gk.GeoKeyDirectory = append(gk.GeoKeyDirectory, k)
@@ -30,7 +123,7 @@ func (gk *GeoKeys) addKeyDirectory(data []uint8) {
 
 func (gk *GeoKeys) addDoubleParams(data []uint8) {
 	i := 0
-	for i < len(data) {
+	for i+8 <= len(data) {
 		k := math.Float64frombits(binary.LittleEndian.Uint64(data[i : i+8]))
 		gk.GeoDoubleParams = append(gk.GeoDoubleParams, k)
 		i += 8
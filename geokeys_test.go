@@ -0,0 +1,69 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// geoKeyDirectoryVLR returns a GeoKeyDirectoryTag VLR holding the given
+// (key ID, value) pairs, each stored directly in the directory.
+func geoKeyDirectoryVLR(keys ...[2]uint16) VLR {
+	directory := []uint16{1, 1, 0, uint16(len(keys))}
+	for _, k := range keys {
+		directory = append(directory, k[0], 0, 1, k[1])
+	}
+	data := make([]byte, 2*len(directory))
+	for i, v := range directory {
+		binary.LittleEndian.PutUint16(data[2*i:], v)
+	}
+	return VLR{UserID: "LASF_Projection", RecordID: 34735, RecordLengthAfterHeader: len(data), BinaryData: data}
+}
+
+func TestGetCRS(t *testing.T) {
+	gk := GeoKeys{}
+	if _, err := gk.crs(); err != ErrNoCRS {
+		t.Errorf("expected ErrNoCRS without georeferencing, got %v", err)
+	}
+
+	// A projected system takes precedence over the geographic system.
+	gk.addVLR(geoKeyDirectoryVLR([2]uint16{1024, 1}, [2]uint16{2048, 4258}, [2]uint16{3072, 25830}))
+	crs, err := gk.crs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crs != "EPSG:25830" {
+		t.Errorf("expected EPSG:25830, got %v", crs)
+	}
+
+	// The WKT is preferred when both encodings are present.
+	wkt := `PROJCS["ETRS89 / UTM zone 30N"]`
+	gk.addVLR(VLR{UserID: "LASF_Projection", RecordID: 2112, BinaryData: append([]byte(wkt), 0)})
+	if crs, _ = gk.crs(); crs != wkt {
+		t.Errorf("expected the WKT to be preferred, got %v", crs)
+	}
+}
+
+func TestGetCRSGeographic(t *testing.T) {
+	gk := GeoKeys{}
+	// A user-defined projected system is not reported.
+	gk.addVLR(geoKeyDirectoryVLR([2]uint16{2048, 4326}, [2]uint16{3072, 32767}))
+	if crs, err := gk.crs(); err != nil || crs != "EPSG:4326" {
+		t.Errorf("expected EPSG:4326, got %v (%v)", crs, err)
+	}
+}
+
+func TestLazGetCRS(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	crs, err := lf.GetCRS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crs == "" {
+		t.Error("expected a coordinate reference system")
+	}
+}
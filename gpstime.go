@@ -0,0 +1,297 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrNoGPSTime is returned when GPS time information is requested from a
+// file whose point format does not store it.
+var ErrNoGPSTime = errors.New("the point format does not contain GPS time data")
+
+// gpsEpoch is the origin of GPS time.
+var gpsEpoch = time.Date(1980, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+// adjustedGPSTimeOffset is subtracted from GPS standard time to give the
+// adjusted standard GPS time stored in LAS files.
+const adjustedGPSTimeOffset = 1e9
+
+// GPSTimeType is the type of the GPS times of the points, as recorded in bit 0
+// of the global encoding. It is an alias of GpsTimeType.
+type GPSTimeType = GpsTimeType
+
+const (
+	// GPSWeekTime is the number of seconds into the GPS week.
+	GPSWeekTime GPSTimeType = GpsWeekTime
+	// GPSAdjustedStandard is standard GPS time minus 10^9 seconds.
+	GPSAdjustedStandard GPSTimeType = SatelliteGpsTime
+)
+
+// GPSTimeOption modifies the behaviour of the GPS time queries.
+type GPSTimeOption func(*gpsTimeOptions)
+
+type gpsTimeOptions struct {
+	ignoreZero bool
+	sorted     bool
+}
+
+// WithIgnoreZeroGPSTime skips points whose GPS time is exactly zero. Some
+// producers leave the GPS time at 0.0 to indicate that it was never set.
+func WithIgnoreZeroGPSTime() GPSTimeOption {
+	return func(o *gpsTimeOptions) {
+		o.ignoreZero = true
+	}
+}
+
+// WithTimeSorted declares that the points of the file are stored in order of
+// increasing GPS time, as written by most acquisition systems, which lets
+// ReadPointsByTimeWindow locate the window by binary search and stop reading
+// at its end rather than scan the whole file. The results are undefined if the
+// points are not sorted.
+func WithTimeSorted() GPSTimeOption {
+	return func(o *gpsTimeOptions) {
+		o.sorted = true
+	}
+}
+
+// GPSTimeGap is a gap in the acquisition time between two consecutive GPS times.
+type GPSTimeGap struct {
+	Start float64
+	End   float64
+}
+
+// Duration returns the length of the gap.
+func (g GPSTimeGap) Duration() float64 {
+	return g.End - g.Start
+}
+
+// gpsTimes returns the GPS times of the points, honouring the options.
+func (las *LasFile) gpsTimes(opts []GPSTimeOption) ([]float64, error) {
+	if las.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	if las.Header.PointFormatID != 1 && las.Header.PointFormatID != 3 {
+		return nil, ErrNoGPSTime
+	}
+	o := gpsTimeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.ignoreZero {
+		return las.gpsData, nil
+	}
+	times := make([]float64, 0, len(las.gpsData))
+	for _, t := range las.gpsData {
+		if t != 0 {
+			times = append(times, t)
+		}
+	}
+	return times, nil
+}
+
+// GPSTimeRange returns the minimum and maximum GPS times of the points in the file.
+func (las *LasFile) GPSTimeRange(opts ...GPSTimeOption) (min, max float64, err error) {
+	times, err := las.gpsTimes(opts)
+	if err != nil {
+		return NoData, NoData, err
+	}
+	if len(times) == 0 {
+		return NoData, NoData, errors.New("the file does not contain any GPS times")
+	}
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, t := range times {
+		if t < min {
+			min = t
+		}
+		if t > max {
+			max = t
+		}
+	}
+	return min, max, nil
+}
+
+// GPSTimeGaps returns the gaps between consecutive GPS times that are longer
+// than minGap, in ascending time order. Gaps typically separate flight lines.
+func (las *LasFile) GPSTimeGaps(minGap float64, opts ...GPSTimeOption) ([]GPSTimeGap, error) {
+	times, err := las.gpsTimes(opts)
+	if err != nil {
+		return nil, err
+	}
+	sorted := make([]float64, len(times))
+	copy(sorted, times)
+	sort.Float64s(sorted)
+
+	gaps := []GPSTimeGap{}
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i]-sorted[i-1] > minGap {
+			gaps = append(gaps, GPSTimeGap{Start: sorted[i-1], End: sorted[i]})
+		}
+	}
+	return gaps, nil
+}
+
+// FilterByTime returns the points whose GPS time lies within [minT, maxT].
+// ErrNoGPSTime is returned if the point format does not store GPS time.
+func (las *LasFile) FilterByTime(minT, maxT float64) ([]LasPointer, error) {
+	times, err := las.gpsTimes(nil)
+	if err != nil {
+		return nil, err
+	}
+	if maxT < minT {
+		return nil, errors.New("the end of the time window precedes its start")
+	}
+	points := []LasPointer{}
+	for i, t := range times {
+		if t < minT || t > maxT {
+			continue
+		}
+		p, err := las.LasPoint(i)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// PointTime returns the acquisition time of a point in UTC. The file must store
+// adjusted standard GPS time; GPS week time cannot be converted without knowing
+// the week. GPS time does not include leap seconds, so the number of leap
+// seconds in effect at the time of acquisition (18 since 2017) must be supplied.
+func (las *LasFile) PointTime(pointIndex int, leapSeconds int) (time.Time, error) {
+	times, err := las.gpsTimes(nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if pointIndex < 0 || pointIndex >= len(times) {
+		return time.Time{}, errors.New("Index outside of allowable range")
+	}
+	if err = checkAdjustedGPSTime(las.Header.GlobalEncoding); err != nil {
+		return time.Time{}, err
+	}
+	return adjustedGPSTimeToTime(times[pointIndex], leapSeconds)
+}
+
+// GPSTimeType returns the type of the GPS times of the points, as recorded in
+// bit 0 of the global encoding: GPSAdjustedStandard for adjusted standard GPS
+// time, or GPSWeekTime for seconds into the GPS week.
+func (lf *LazFile) GPSTimeType() GPSTimeType {
+	return lf.Header.GlobalEncoding.GpsTime()
+}
+
+// ReadPointsByTimeWindow returns the points whose GPS time lies within
+// [start, end], e.g. to extract a single pass of a flight line, in file order.
+// Points are usually, but not always, stored in time order, so the whole file
+// is scanned unless WithTimeSorted declares that it is sorted. A file that
+// does not declare its point count is always scanned. ErrNoGPSTime is returned
+// if the point format does not store GPS time.
+func (lf *LazFile) ReadPointsByTimeWindow(start, end float64, opts ...GPSTimeOption) ([]LasPointer, error) {
+	if !hasGPSTime(lf.Header.PointFormatID) {
+		return nil, ErrNoGPSTime
+	}
+	if end < start {
+		return nil, errors.New("the end of the time window precedes its start")
+	}
+	o := gpsTimeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	inWindow := func(lp *LaszipPoint) bool {
+		return lp.GPSTime >= start && lp.GPSTime <= end && !(o.ignoreZero && lp.GPSTime == 0)
+	}
+	if lf.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	// The binary search needs the point count, which a streaming file only
+	// knows once it has been read.
+	if !o.sorted || lf.reader.IsStreaming() {
+		return lf.readPointsWhere(inWindow)
+	}
+	lf.Lock()
+	first, err := lf.firstPointAtTime(start)
+	lf.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	remaining := lf.Header.NumberPoints - first
+	it := PointIterator{lf: lf, buf: make([]LaszipPoint, pointIteratorBatchSize),
+		ranged: true, ranges: []pointRange{{start: first, count: remaining}}, progress: newProgress(lf.progress, remaining)}
+	points := []LasPointer{}
+	for it.Next() {
+		lp := it.laszipPoint()
+		if lp.GPSTime > end {
+			break
+		}
+		if inWindow(lp) {
+			points = append(points, it.Point())
+		}
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return points, nil
+}
+
+// firstPointAtTime returns the index of the first point whose GPS time is at
+// least t, by binary search over the points of a file sorted by GPS time. The
+// caller must hold the lock.
+func (lf *LazFile) firstPointAtTime(t float64) (int, error) {
+	var err error
+	i := sort.Search(lf.Header.NumberPoints, func(i int) bool {
+		if err != nil {
+			return true
+		}
+		lp, readErr := lf.readPoint(i)
+		if readErr != nil {
+			err = readErr
+			return true
+		}
+		return lp.GPSTime >= t
+	})
+	return i, err
+}
+
+// GetGPSTime returns the acquisition time of a point in UTC, as PointTime does
+// for LAS files. The file must store adjusted standard GPS time, which is
+// converted by adding 10^9 seconds and counting from the GPS epoch; GPS week
+// time cannot be converted without knowing the week. GPS time does not include
+// leap seconds, so the number of leap seconds in effect at the time of
+// acquisition (18 since 2017) must be supplied.
+func (lf *LazFile) GetGPSTime(pointIndex int, leapSeconds int) (time.Time, error) {
+	if !hasGPSTime(lf.Header.PointFormatID) {
+		return time.Time{}, ErrNoGPSTime
+	}
+	if err := checkAdjustedGPSTime(lf.Header.GlobalEncoding); err != nil {
+		return time.Time{}, err
+	}
+	lf.Lock()
+	p, err := lf.readPoint(pointIndex)
+	lf.Unlock()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return adjustedGPSTimeToTime(p.GPSTime, leapSeconds)
+}
+
+// checkAdjustedGPSTime returns an error unless the global encoding indicates
+// adjusted standard GPS time.
+func checkAdjustedGPSTime(enc GlobalEncodingField) error {
+	if enc.GpsTime() != SatelliteGpsTime {
+		return errors.New("the file stores GPS week time, which cannot be converted without the GPS week")
+	}
+	return nil
+}
+
+// adjustedGPSTimeToTime converts an adjusted standard GPS time to a time,
+// subtracting the given number of leap seconds.
+func adjustedGPSTimeToTime(gpsTime float64, leapSeconds int) (time.Time, error) {
+	seconds := gpsTime + adjustedGPSTimeOffset - float64(leapSeconds)
+	if math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return time.Time{}, fmt.Errorf("invalid GPS time %v", gpsTime)
+	}
+	whole, frac := math.Modf(seconds)
+	return gpsEpoch.Add(time.Duration(whole) * time.Second).Add(time.Duration(math.Round(frac * 1e9))), nil
+}
@@ -0,0 +1,261 @@
+package lidario
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGPSTimeIgnoreZero(t *testing.T) {
+	times := []float64{0, 100.5, 101.0, 0, 250.0, 251.5}
+	var points []LasPointer
+	for i, gpsTime := range times {
+		points = append(points, &PointRecord1{PointRecord0: &PointRecord0{X: float64(i), Y: float64(i), Z: 1.0}, GPSTime: gpsTime})
+	}
+	lf := createTestLasFile(t, 1, points)
+
+	min, max, err := lf.GPSTimeRange()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min != 0 || max != 251.5 {
+		t.Errorf("expected a range of [0, 251.5], got [%v, %v]", min, max)
+	}
+
+	min, max, err = lf.GPSTimeRange(WithIgnoreZeroGPSTime())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min != 100.5 || max != 251.5 {
+		t.Errorf("expected a range of [100.5, 251.5] when ignoring zero times, got [%v, %v]", min, max)
+	}
+
+	gaps, err := lf.GPSTimeGaps(10.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gaps) != 2 {
+		t.Errorf("expected 2 gaps including the unset times, got %v", len(gaps))
+	}
+
+	gaps, err = lf.GPSTimeGaps(10.0, WithIgnoreZeroGPSTime())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gaps) != 1 || gaps[0].Start != 101.0 || gaps[0].End != 250.0 {
+		t.Errorf("expected a single gap between 101 and 250, got %v", gaps)
+	}
+}
+
+func TestGPSTimeRangeNoGPSTime(t *testing.T) {
+	lf := createTestLasFile(t, 0, []LasPointer{&PointRecord0{X: 1, Y: 1, Z: 1}})
+	if _, _, err := lf.GPSTimeRange(); err != ErrNoGPSTime {
+		t.Errorf("expected ErrNoGPSTime, got %v", err)
+	}
+}
+
+func TestFilterByTime(t *testing.T) {
+	var points []LasPointer
+	for i := 0; i < 100; i++ {
+		points = append(points, &PointRecord1{PointRecord0: &PointRecord0{X: float64(i), Y: 1.0, Z: 1.0}, GPSTime: float64(i) * 0.5})
+	}
+	lf := createTestLasFile(t, 1, points)
+
+	filtered, err := lf.FilterByTime(10.0, 20.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 21 {
+		t.Errorf("expected 21 points within the window, got %v", len(filtered))
+	}
+	for _, p := range filtered {
+		if gpsTime := p.GpsTimeData(); gpsTime < 10.0 || gpsTime > 20.0 {
+			t.Errorf("point with GPS time %v lies outside of the window", gpsTime)
+		}
+	}
+
+	noGps := createTestLasFile(t, 0, []LasPointer{&PointRecord0{X: 1, Y: 1, Z: 1}})
+	if _, err = noGps.FilterByTime(0, 1); err != ErrNoGPSTime {
+		t.Errorf("expected ErrNoGPSTime, got %v", err)
+	}
+}
+
+func TestPointTime(t *testing.T) {
+	// 2020-01-01T00:00:00Z is GPS second 1261872018 (18 leap seconds), or
+	// 261872018 in adjusted standard GPS time.
+	lf := createTestLasFile(t, 1, []LasPointer{
+		&PointRecord1{PointRecord0: &PointRecord0{X: 1, Y: 1, Z: 1}, GPSTime: 261872018.5},
+	})
+	if _, err := lf.PointTime(0, 18); err == nil {
+		t.Error("expected an error for a file storing GPS week time")
+	}
+
+	lf.Header.GlobalEncoding = GlobalEncodingField{Value: 1}
+	got, err := lf.PointTime(0, 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2020, time.January, 1, 0, 0, 0, 500000000, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if _, err = lf.PointTime(1, 18); err == nil {
+		t.Error("expected an error for an out of range point index")
+	}
+}
+
+func TestLazGPSTime(t *testing.T) {
+	// Without leap seconds the time is on the GPS time scale, which ran 18
+	// seconds ahead of UTC in 2020.
+	got, err := adjustedGPSTimeToTime(261872018.5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2020, time.January, 1, 0, 0, 18, 500000000, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// The point format and global encoding are checked before a point is read.
+	lf := &LazFile{Header: LasHeader{PointFormatID: 0, GlobalEncoding: GlobalEncodingField{Value: 1}}}
+	if _, err = lf.GetGPSTime(0, 18); !errors.Is(err, ErrNoGPSTime) {
+		t.Errorf("expected ErrNoGPSTime, got %v", err)
+	}
+	lf.Header.PointFormatID = 6
+	lf.Header.GlobalEncoding.Value = 0
+	if _, err = lf.GetGPSTime(0, 18); err == nil {
+		t.Error("expected an error for a file storing GPS week time")
+	}
+
+	requireSampleLaz(t)
+	lf, err = NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if !hasGPSTime(lf.Header.PointFormatID) || lf.Header.GlobalEncoding.GpsTime() != SatelliteGpsTime {
+		t.Skip("the sample file does not store adjusted standard GPS time")
+	}
+	p, err := lf.LasPoint(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = lf.GetGPSTime(0, 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = gpsEpoch.Add(time.Duration((p.GpsTimeData() + 1e9 - 18) * 1e9))
+	if d := got.Sub(want); d < -time.Microsecond || d > time.Microsecond {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLazGPSTimeType(t *testing.T) {
+	fileName := writeHeaderOnlyLaz(t)
+	lf, err := NewLazFile(fileName, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lf.GPSTimeType(); got != GPSWeekTime {
+		t.Errorf("expected GPS week time, got %v", got)
+	}
+	lf.Close()
+
+	// Set bit 0 of the global encoding for adjusted standard GPS time.
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[6] |= 1
+	if err = os.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	lf, err = NewLazFile(fileName, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if got := lf.GPSTimeType(); got != GPSAdjustedStandard {
+		t.Errorf("expected adjusted standard GPS time, got %v", got)
+	}
+}
+
+func TestReadPointsByTimeWindow(t *testing.T) {
+	lf := &LazFile{fileMode: "r", Header: LasHeader{PointFormatID: 2}}
+	if _, err := lf.ReadPointsByTimeWindow(0, 1); !errors.Is(err, ErrNoGPSTime) {
+		t.Errorf("expected ErrNoGPSTime, got %v", err)
+	}
+	lf.Header.PointFormatID = 1
+	if _, err := lf.ReadPointsByTimeWindow(2, 1); err == nil {
+		t.Error("expected an error for a window ending before its start")
+	}
+	// A streaming file is scanned even if it is sorted, which reads from the
+	// (closed) reader rather than finding no points to search.
+	lf.reader = &LaszipReader{streaming: true}
+	if _, err := lf.ReadPointsByTimeWindow(0, 1, WithTimeSorted()); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("expected a full scan of a streaming file, got %v", err)
+	}
+
+	requireLaszip(t)
+	fileName := filepath.Join(t.TempDir(), "trajectory.laz")
+	lw, err := NewLazWriter(fileName, LasHeader{PointFormatID: 1, XScaleFactor: 0.01, YScaleFactor: 0.01, ZScaleFactor: 0.01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 20000
+	for i := 0; i < n; i++ {
+		// Two passes of a flight line, 100 seconds apart.
+		gpsTime := 1000 + float64(i)*0.01
+		if i >= n/2 {
+			gpsTime += 100
+		}
+		if err = lw.WritePoint(&PointRecord1{PointRecord0: &PointRecord0{X: float64(i % 100), Y: float64(i / 100), Z: 1},
+			GPSTime: gpsTime}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	lf, err = NewLazFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	// The second pass starts at 1200 seconds.
+	for _, opts := range [][]GPSTimeOption{nil, {WithTimeSorted()}} {
+		points, err := lf.ReadPointsByTimeWindow(1150, 1250.005, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(points) != 5001 {
+			t.Errorf("sorted %v: expected 5001 points, got %v", len(opts) > 0, len(points))
+		}
+		for i, p := range points {
+			if want := 1200 + float64(i)*0.01; math.Abs(p.GpsTimeData()-want) > 1e-6 {
+				t.Fatalf("sorted %v: point %v has GPS time %v, expected %v", len(opts) > 0, i, p.GpsTimeData(), want)
+			}
+		}
+	}
+	if points, err := lf.ReadPointsByTimeWindow(0, 999, WithTimeSorted()); err != nil || len(points) != 0 {
+		t.Errorf("expected no points before the first pass, got %v (%v)", len(points), err)
+	}
+}
+
+func TestGPSTimeTypeConstants(t *testing.T) {
+	if GPSWeekTime != GpsWeekTime || GPSAdjustedStandard != SatelliteGpsTime {
+		t.Error("the GPSTimeType constants do not match those of GpsTimeType")
+	}
+	lf := &LazFile{}
+	if got := lf.GPSTimeType(); got != GPSWeekTime {
+		t.Errorf("expected GPS week time for a clear bit 0, got %v", got)
+	}
+	lf.Header.GlobalEncoding.Value = 1
+	if got := lf.GPSTimeType(); got != GPSAdjustedStandard {
+		t.Errorf("expected adjusted standard GPS time for a set bit 0, got %v", got)
+	}
+}
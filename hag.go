@@ -0,0 +1,130 @@
+package lidario
+
+import (
+	"errors"
+	"math"
+)
+
+// groundClass is the ASPRS classification of ground points.
+const groundClass = 2
+
+// defaultGroundCellSize is the cell size, in the units of the coordinates,
+// of the ground model built by HeightAboveGround.
+const defaultGroundCellSize = 1.0
+
+// HeightAboveGround returns the height of every point above the ground, in
+// file order. The ground is modelled as a grid of defaultGroundCellSize
+// cells holding the lowest ground (class 2) point of each cell; see
+// HeightAboveGroundGrid.
+func (lf *LazFile) HeightAboveGround() ([]float64, error) {
+	return lf.HeightAboveGroundGrid(defaultGroundCellSize)
+}
+
+// HeightAboveGroundGrid returns the height of every point above the ground,
+// in file order. The ground is modelled as a grid of cells of the given size
+// holding the lowest ground (class 2) point of each cell; cells without ground
+// points take the ground of the nearest cell that has one. The points are
+// read twice: once to build the ground model and once to compute the heights.
+func (lf *LazFile) HeightAboveGroundGrid(cellSize float64) ([]float64, error) {
+	h := &lf.Header
+	g, err := newGroundGrid(h.MinX, h.MinY, h.MaxX, h.MaxY, cellSize)
+	if err != nil {
+		return nil, err
+	}
+	it, err := lf.Points()
+	if err != nil {
+		return nil, err
+	}
+	for it.Next() {
+		if p := it.laszipPoint(); lf.mapClass(p.Classification) == groundClass {
+			g.add(p.X, p.Y, p.Z)
+		}
+	}
+	if err = it.Err(); err != nil {
+		return nil, err
+	}
+	if !g.fill() {
+		return nil, errors.New("the file does not contain any ground (class 2) points")
+	}
+
+	heights := make([]float64, 0, h.NumberPoints)
+	if it, err = lf.Points(); err != nil {
+		return nil, err
+	}
+	for it.Next() {
+		p := it.laszipPoint()
+		heights = append(heights, g.height(p.X, p.Y, p.Z))
+	}
+	if err = it.Err(); err != nil {
+		return nil, err
+	}
+	return heights, nil
+}
+
+// groundGrid is a grid-minimum ground model: each cell of the raster holds the
+// elevation of the lowest ground point within it, or NoData.
+type groundGrid struct {
+	raster *Raster
+}
+
+func newGroundGrid(minX, minY, maxX, maxY, cellSize float64) (*groundGrid, error) {
+	r, err := newRaster(minX, minY, maxX, maxY, cellSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := range r.Data {
+		r.Data[i] = r.NoData
+	}
+	return &groundGrid{raster: r}, nil
+}
+
+func (g *groundGrid) add(x, y, z float64) {
+	row, column, ok := g.raster.CellOf(x, y)
+	if !ok {
+		return
+	}
+	if v := g.raster.Value(row, column); v == g.raster.NoData || z < v {
+		g.raster.SetValue(row, column, z)
+	}
+}
+
+// fill gives the cells without ground points the elevation of the nearest
+// cell with one, by breadth-first search from the cells with ground points.
+// It returns false if the grid holds no ground points.
+func (g *groundGrid) fill() bool {
+	r := g.raster
+	queue := []int{}
+	for i, v := range r.Data {
+		if v != r.NoData {
+			queue = append(queue, i)
+		}
+	}
+	if len(queue) == 0 {
+		return false
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		row, column := i/r.Columns, i%r.Columns
+		for _, n := range [4][2]int{{row - 1, column}, {row + 1, column}, {row, column - 1}, {row, column + 1}} {
+			if n[0] < 0 || n[0] >= r.Rows || n[1] < 0 || n[1] >= r.Columns {
+				continue
+			}
+			if j := n[0]*r.Columns + n[1]; r.Data[j] == r.NoData {
+				r.Data[j] = r.Data[i]
+				queue = append(queue, j)
+			}
+		}
+	}
+	return true
+}
+
+// height returns the height of a point above the ground of its cell, or NaN
+// if the point lies outside of the grid.
+func (g *groundGrid) height(x, y, z float64) float64 {
+	row, column, ok := g.raster.CellOf(x, y)
+	if !ok {
+		return math.NaN()
+	}
+	return z - g.raster.Value(row, column)
+}
@@ -0,0 +1,71 @@
+package lidario
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGroundGrid(t *testing.T) {
+	g, err := newGroundGrid(0, 0, 10, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.fill() {
+		t.Error("a grid without ground points cannot be filled")
+	}
+
+	// A flat ground at Z = 100 with a gap over [3, 6] x [3, 6]. Some cells
+	// hold a higher ground point too, which the minimum discards.
+	for x := 0.5; x < 10; x++ {
+		for y := 0.5; y < 10; y++ {
+			if x > 3 && x < 6 && y > 3 && y < 6 {
+				continue
+			}
+			g.add(x, y, 100)
+			g.add(x+0.2, y+0.2, 100.4)
+		}
+	}
+	if !g.fill() {
+		t.Fatal("the grid holds ground points")
+	}
+	cases := []struct{ x, y, z, expected float64 }{
+		{0.1, 0.1, 100, 0},
+		{7.5, 2.5, 112.5, 12.5},
+		{4.5, 4.5, 105, 5}, // within the gap
+		{9.9, 9.9, 99, -1},
+	}
+	for _, c := range cases {
+		if hag := g.height(c.x, c.y, c.z); math.Abs(hag-c.expected) > 1e-9 {
+			t.Errorf("point (%v, %v, %v): height %v, expected %v", c.x, c.y, c.z, hag, c.expected)
+		}
+	}
+	if hag := g.height(20, 20, 100); !math.IsNaN(hag) {
+		t.Errorf("expected NaN outside of the grid, got %v", hag)
+	}
+}
+
+func TestHeightAboveGround(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	heights, err := lf.HeightAboveGround()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heights) != lf.Header.NumberPoints {
+		t.Fatalf("%v heights for %v points", len(heights), lf.Header.NumberPoints)
+	}
+	// The lowest ground point of a cell lies on the ground.
+	it, err := lf.Points()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; it.Next(); i++ {
+		if it.laszipPoint().Classification == groundClass && heights[i] < 0 {
+			t.Fatalf("ground point %v lies %v below the ground", i, -heights[i])
+		}
+	}
+}
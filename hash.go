@@ -0,0 +1,52 @@
+package lidario
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+// PointsHash computes a SHA-256 hash of the point records of the file. The
+// hash covers, in file order, the decoded coordinates and the core point
+// attributes (intensity, return bit field, classification, scan angle, user
+// data, point source ID) along with the GPS time and RGB values when the
+// point format stores them. Volatile header metadata such as the file
+// creation date, system identifier and generating software are ignored, so
+// two files holding identical points hash equally.
+func (las *LasFile) PointsHash() ([32]byte, error) {
+	var sum [32]byte
+	if las.fileMode == "rh" {
+		return sum, errHeaderOnly
+	}
+	h := sha256.New()
+	b := make([]byte, 50)
+	hasGps := las.Header.PointFormatID == 1 || las.Header.PointFormatID == 3
+	hasRgb := las.Header.PointFormatID == 2 || las.Header.PointFormatID == 3
+	for i := range las.pointData {
+		p := &las.pointData[i]
+		binary.LittleEndian.PutUint64(b[0:8], math.Float64bits(p.X))
+		binary.LittleEndian.PutUint64(b[8:16], math.Float64bits(p.Y))
+		binary.LittleEndian.PutUint64(b[16:24], math.Float64bits(p.Z))
+		binary.LittleEndian.PutUint16(b[24:26], p.Intensity)
+		b[26] = p.BitField.Value
+		b[27] = p.ClassBitField.Value
+		b[28] = uint8(p.ScanAngle)
+		b[29] = p.UserData
+		binary.LittleEndian.PutUint16(b[30:32], p.PointSourceID)
+		n := 32
+		if hasGps {
+			binary.LittleEndian.PutUint64(b[n:n+8], math.Float64bits(las.gpsData[i]))
+			n += 8
+		}
+		if hasRgb {
+			rgb := las.rgbData[i]
+			binary.LittleEndian.PutUint16(b[n:n+2], rgb.Red)
+			binary.LittleEndian.PutUint16(b[n+2:n+4], rgb.Green)
+			binary.LittleEndian.PutUint16(b[n+4:n+6], rgb.Blue)
+			n += 6
+		}
+		h.Write(b[:n])
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
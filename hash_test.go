@@ -0,0 +1,61 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPointsHash(t *testing.T) {
+	var points []LasPointer
+	for i := 0; i < 100; i++ {
+		points = append(points, &PointRecord1{
+			PointRecord0: &PointRecord0{X: float64(i), Y: float64(2 * i), Z: 10.0, Intensity: uint16(i)},
+			GPSTime:      float64(1000 + i),
+		})
+	}
+	lf := createTestLasFile(t, 1, points)
+
+	// Make a copy of the file with a different creation date.
+	data, err := os.ReadFile(lf.fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.LittleEndian.PutUint16(data[90:92], 1)
+	binary.LittleEndian.PutUint16(data[92:94], 1999)
+	copyName := filepath.Join(t.TempDir(), "copy.las")
+	if err = os.WriteFile(copyName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	copyLf, err := NewLasFile(copyName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer copyLf.Close()
+	if copyLf.Header.FileCreationYear != 1999 || copyLf.Header.FileCreationYear == lf.Header.FileCreationYear {
+		t.Fatalf("expected the copy to have a different creation year, got %v", copyLf.Header.FileCreationYear)
+	}
+
+	h1, err := lf.PointsHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := copyLf.PointsHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 != h2 {
+		t.Error("expected files with identical points to hash equally")
+	}
+
+	// Changing a single point must change the hash.
+	copyLf.pointData[50].Intensity++
+	h3, err := copyLf.PointsHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 == h3 {
+		t.Error("expected a modified point to change the hash")
+	}
+}
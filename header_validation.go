@@ -0,0 +1,142 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// minimumVersion returns the LAS minor version (of 1.x) that introduced a
+// point format.
+func minimumVersion(format uint8) byte {
+	switch {
+	case format >= 6:
+		return 4
+	case format >= 4:
+		return 3
+	case format >= 2:
+		return 2
+	}
+	return 0
+}
+
+// ErrVersionMismatch is returned in strict mode when the point format of a
+// file is not defined in the LAS version declared by its header.
+var ErrVersionMismatch = errors.New("LAS version mismatch")
+
+// pointFormatVersionProblem describes why the point format of the header does
+// not exist in its LAS version, or returns an empty string if it does. Some
+// exporters write e.g. point format 7 in a file that claims to be LAS 1.2.
+func pointFormatVersionProblem(h LasHeader) string {
+	if int(h.PointFormatID) >= len(pointFormatRecordLengths) {
+		return fmt.Sprintf("unknown point format %v", h.PointFormatID)
+	}
+	if minor := minimumVersion(h.PointFormatID); !h.versionAtLeast(1, minor) {
+		return fmt.Sprintf("point format %v requires LAS 1.%v but the file is LAS %v.%v",
+			h.PointFormatID, minor, h.VersionMajor, h.VersionMinor)
+	}
+	return ""
+}
+
+// checkPointFormatVersion returns an error wrapping ErrVersionMismatch if the
+// point format of the header does not exist in its LAS version.
+func checkPointFormatVersion(h LasHeader) error {
+	if problem := pointFormatVersionProblem(h); problem != "" {
+		return fmt.Errorf("%w: %v", ErrVersionMismatch, problem)
+	}
+	return nil
+}
+
+// versionIssues checks that the point format exists in the LAS version of the
+// header.
+func versionIssues(h LasHeader) []ValidationIssue {
+	issues := []ValidationIssue{}
+	if problem := pointFormatVersionProblem(h); problem != "" {
+		issues = append(issues, ValidationIssue{Check: "version", Message: problem})
+	}
+	return issues
+}
+
+// layoutIssues checks the VLRs against the header and the offset to the point
+// data. vlrEnd is the offset of the end of the VLRs that could be read and
+// vlrCount their number.
+func layoutIssues(h LasHeader, headerSize, vlrEnd int64, vlrCount int) []ValidationIssue {
+	issues := []ValidationIssue{}
+	if headerSize != int64(h.HeaderSize) {
+		issues = append(issues, ValidationIssue{Check: "layout",
+			Message: fmt.Sprintf("the header size is %v bytes but LASzip reports %v", headerSize, h.HeaderSize)})
+	}
+	if vlrCount != h.NumberOfVLRs && vlrCount != h.NumberOfVLRs+1 {
+		// LASzip does not count its own VLR, so one extra is expected.
+		issues = append(issues, ValidationIssue{Check: "vlr count",
+			Message: fmt.Sprintf("the header declares %v VLRs but %v fit before the point data", h.NumberOfVLRs, vlrCount)})
+	}
+	if int64(h.OffsetToPoints) < vlrEnd {
+		issues = append(issues, ValidationIssue{Check: "layout",
+			Message: fmt.Sprintf("the point data start at offset %v, inside the VLRs, which end at %v", h.OffsetToPoints, vlrEnd)})
+	}
+	return issues
+}
+
+// extentIssues compares the point count and extent of the header with those
+// observed while reading the points. Differences smaller than half a scale
+// factor step are ignored.
+func extentIssues(h LasHeader, stats *PointStats) []ValidationIssue {
+	issues := []ValidationIssue{}
+	if h.NumberPoints != 0 && uint64(h.NumberPoints) != stats.Count {
+		issues = append(issues, ValidationIssue{Check: "point count",
+			Message: fmt.Sprintf("the header declares %v points but %v could be read", h.NumberPoints, stats.Count)})
+	}
+	if stats.Count == 0 {
+		return issues
+	}
+	axes := []struct {
+		name                 string
+		headerMin, headerMax float64
+		observed             AxisStats
+		scale                float64
+	}{
+		{"X", h.MinX, h.MaxX, stats.X, h.XScaleFactor},
+		{"Y", h.MinY, h.MaxY, stats.Y, h.YScaleFactor},
+		{"Z", h.MinZ, h.MaxZ, stats.Z, h.ZScaleFactor},
+	}
+	for _, a := range axes {
+		tolerance := a.scale / 2
+		if math.Abs(a.observed.Min-a.headerMin) > tolerance || math.Abs(a.observed.Max-a.headerMax) > tolerance {
+			issues = append(issues, ValidationIssue{Check: "bounds",
+				Message: fmt.Sprintf("the header %v range [%v, %v] differs from the observed range [%v, %v]",
+					a.name, a.headerMin, a.headerMax, a.observed.Min, a.observed.Max)})
+		}
+	}
+	return issues
+}
+
+// Validate checks the header of the file against its contents: the point
+// count and extent against the points that can be read, the VLR count and
+// offset to the point data against the VLRs in the file, and the point format
+// against the LAS version. Every point is read. An empty slice is returned for
+// a consistent file.
+func (lf *LazFile) Validate() []ValidationIssue {
+	issues := versionIssues(lf.Header)
+	headerSize, vlrEnd, vlrCount, err := lf.rawVLRLayout()
+	if err != nil {
+		issues = append(issues, ValidationIssue{Check: "layout", Message: err.Error()})
+	} else {
+		issues = append(issues, layoutIssues(lf.Header, headerSize, vlrEnd, vlrCount)...)
+	}
+	stats, err := lf.ComputeStatistics()
+	if err != nil {
+		return append(issues, ValidationIssue{Check: "points", Message: err.Error()})
+	}
+	return append(issues, extentIssues(lf.Header, stats)...)
+}
+
+// rawVLRLayout walks the VLR headers of the file; see rawVLRLayout.
+func (lf *LazFile) rawVLRLayout() (headerSize, end int64, found int, err error) {
+	r, err := lf.openRaw()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer r.Close()
+	return rawVLRLayout(r, int64(lf.Header.OffsetToPoints))
+}
@@ -0,0 +1,110 @@
+package lidario
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtentIssues(t *testing.T) {
+	h := LasHeader{NumberPoints: 3, MinX: 0, MaxX: 10, MinY: 0, MaxY: 10, MinZ: 0, MaxZ: 5,
+		XScaleFactor: 0.01, YScaleFactor: 0.01, ZScaleFactor: 0.01}
+	stats := &PointStats{
+		Count: 3,
+		X:     AxisStats{Min: 0.004, Max: 10},
+		Y:     AxisStats{Min: 0, Max: 10},
+		Z:     AxisStats{Min: 0, Max: 5},
+	}
+	if issues := extentIssues(h, stats); len(issues) != 0 {
+		t.Errorf("expected no issues for a matching extent, got %v", issues)
+	}
+
+	stats.Y.Max = 12.5
+	stats.Count = 4
+	issues := extentIssues(h, stats)
+	if len(issues) != 2 {
+		t.Fatalf("expected point count and bounds issues, got %v", issues)
+	}
+	if issues[0].Check != "point count" || issues[1].Check != "bounds" || !strings.Contains(issues[1].Message, "Y range") {
+		t.Errorf("unexpected issues %v", issues)
+	}
+}
+
+func TestVersionIssues(t *testing.T) {
+	cases := []struct {
+		format, minor byte
+		ok            bool
+	}{
+		{0, 0, true}, {1, 0, true}, {2, 1, false}, {2, 2, true}, {3, 2, true}, {3, 1, false},
+		{4, 2, false}, {4, 3, true}, {5, 3, true}, {6, 3, false}, {7, 2, false}, {7, 4, true},
+		{8, 3, false}, {10, 4, true}, {11, 4, false},
+	}
+	for _, c := range cases {
+		h := LasHeader{PointFormatID: c.format, VersionMajor: 1, VersionMinor: c.minor}
+		if issues := versionIssues(h); (len(issues) == 0) != c.ok {
+			t.Errorf("point format %v in LAS 1.%v: got %v", c.format, c.minor, issues)
+		}
+		err := checkPointFormatVersion(h)
+		if (err == nil) != c.ok || (err != nil && !errors.Is(err, ErrVersionMismatch)) {
+			t.Errorf("point format %v in LAS 1.%v: got error %v", c.format, c.minor, err)
+		}
+	}
+}
+
+func TestStrictVersionMismatch(t *testing.T) {
+	// sample.las is LAS 1.1; point format 2 was introduced by LAS 1.2. Its
+	// records are shorter than those of format 1, so the file stays readable.
+	data, err := os.ReadFile("testdata/sample.las")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[104] = 2
+	fileName := filepath.Join(t.TempDir(), "mismatch.las")
+	if err = os.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err := NewLidarFileWithOptions(fileName, Options{Mode: "rh"})
+	if err != nil {
+		t.Fatalf("a version mismatch should only fail in strict mode: %v", err)
+	}
+	lf.Close()
+	_, err = NewLidarFileWithOptions(fileName, Options{Mode: "rh", Strict: true})
+	if !errors.Is(err, ErrVersionMismatch) || !strings.Contains(err.Error(), "requires LAS 1.2") {
+		t.Errorf("expected ErrVersionMismatch, got %v", err)
+	}
+	lf, err = NewLidarFileWithOptions("testdata/sample.las", Options{Mode: "rh", Strict: true})
+	if err != nil {
+		t.Fatalf("a consistent file should open in strict mode: %v", err)
+	}
+	lf.Close()
+}
+
+func TestLayoutIssues(t *testing.T) {
+	h := LasHeader{HeaderSize: 375, NumberOfVLRs: 2, OffsetToPoints: 700}
+	if issues := layoutIssues(h, 375, 700, 3); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+	if issues := layoutIssues(h, 375, 720, 1); len(issues) != 2 {
+		t.Errorf("expected VLR count and layout issues, got %v", issues)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if issues := lf.Validate(); len(issues) != 0 {
+		t.Errorf("expected no issues for the sample file, got %v", issues)
+	}
+	lf.Header.MaxX += 100
+	issues := lf.Validate()
+	if len(issues) != 1 || issues[0].Check != "bounds" {
+		t.Errorf("expected a bounds issue for a mismatched extent, got %v", issues)
+	}
+}
@@ -0,0 +1,45 @@
+package lidario
+
+import "math"
+
+// IntensityRange returns the smallest and largest intensity of the points.
+// The range is taken from the statistics of the last ComputeStatistics call,
+// which is made if the statistics have not been computed yet, so the range
+// costs at most one pass over the points.
+func (lf *LazFile) IntensityRange() (min, max uint16, err error) {
+	lf.RLock()
+	stats := lf.stats
+	lf.RUnlock()
+	if stats == nil {
+		if stats, err = lf.ComputeStatistics(); err != nil {
+			return 0, 0, err
+		}
+	}
+	return uint16(stats.Intensity.Min), uint16(stats.Intensity.Max), nil
+}
+
+// NormalizedIntensity returns the intensity of a point scaled to [0, 1] by the
+// intensity range of the file, as visualization tools expect. Intensities are
+// sensor dependent, so the raw values of different files are not comparable.
+func (lf *LazFile) NormalizedIntensity(pointIndex int) (float64, error) {
+	min, max, err := lf.IntensityRange()
+	if err != nil {
+		return 0, err
+	}
+	p, err := lf.LasPoint(pointIndex)
+	if err != nil {
+		return 0, err
+	}
+	return normalizeIntensity(p.PointData().Intensity, min, max), nil
+}
+
+// normalizeIntensity scales an intensity to [0, 1] by the range [min, max].
+// Values outside of the range are clamped, and a file in which every point
+// has the same intensity normalizes to 0.
+func normalizeIntensity(v, min, max uint16) float64 {
+	if max <= min {
+		return 0
+	}
+	n := (float64(v) - float64(min)) / float64(max-min)
+	return math.Max(0, math.Min(1, n))
+}
@@ -0,0 +1,56 @@
+package lidario
+
+import "testing"
+
+func TestNormalizeIntensity(t *testing.T) {
+	cases := []struct {
+		v, min, max uint16
+		expected    float64
+	}{
+		{100, 100, 300, 0},
+		{300, 100, 300, 1},
+		{200, 100, 300, 0.5},
+		{50, 100, 300, 0},
+		{400, 100, 300, 1},
+		{7, 7, 7, 0},
+		{0, 0, 65535, 0},
+		{65535, 0, 65535, 1},
+	}
+	for _, c := range cases {
+		if n := normalizeIntensity(c.v, c.min, c.max); n != c.expected {
+			t.Errorf("intensity %v in [%v, %v]: got %v, expected %v", c.v, c.min, c.max, n, c.expected)
+		}
+	}
+}
+
+func TestLazNormalizedIntensity(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	min, max, err := lf.IntensityRange()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min > max {
+		t.Fatalf("the intensity range [%v, %v] is inverted", min, max)
+	}
+	// The range is cached, so no further scan is made.
+	scans := 0
+	lf.SetProgressFunc(func(done, total uint64) { scans++ })
+	for i := 0; i < lf.Header.NumberPoints; i += lf.Header.NumberPoints/100 + 1 {
+		n, err := lf.NormalizedIntensity(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n < 0 || n > 1 {
+			t.Errorf("point %v: the normalized intensity %v lies outside of [0, 1]", i, n)
+		}
+	}
+	if scans != 0 {
+		t.Errorf("the intensity range was computed again")
+	}
+}
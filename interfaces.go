@@ -14,8 +14,12 @@ type LidarFile interface {
 	// GetHeader returns the header information
 	GetHeader() *LasHeader
 	
-	// GetPointCount returns the total number of points
+	// GetPointCount returns the total number of points, truncated to 32 bits
 	GetPointCount() uint32
+
+	// GetPointCount64 returns the total number of points, including the 64-bit
+	// count of a LAS 1.4 file
+	GetPointCount64() uint64
 	
 	// IsCompressed returns true if this is a compressed file
 	IsCompressed() bool
@@ -29,11 +33,18 @@ func (lf *LasFile) GetHeader() *LasHeader {
 	return &lf.Header
 }
 
-// GetPointCount returns the point count for LasFile (implement interface)
+// GetPointCount returns the point count for LasFile (implement interface).
+// The count is truncated for files of more than 2^32-1 points; see
+// GetPointCount64.
 func (lf *LasFile) GetPointCount() uint32 {
 	return uint32(lf.Header.NumberPoints)
 }
 
+// GetPointCount64 returns the point count for LasFile (implement interface)
+func (lf *LasFile) GetPointCount64() uint64 {
+	return lf.Header.pointCount()
+}
+
 // IsCompressed returns false for uncompressed LAS files
 func (lf *LasFile) IsCompressed() bool {
 	return false
@@ -0,0 +1,281 @@
+package lidario
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+)
+
+// lasWriterHeaderSize and las14WriterHeaderSize are the sizes of the LAS 1.2
+// and 1.4 headers written by LasWriter.
+const (
+	lasWriterHeaderSize   = 227
+	las14WriterHeaderSize = 375
+)
+
+// LasWriter writes points to an uncompressed LAS file. It mirrors LazWriter:
+// points are encoded and written as they are added, rather than held in
+// memory like the points of a LasFile opened in 'w' mode.
+type LasWriter struct {
+	fileName       string
+	header         LasHeader
+	f              *os.File
+	w              *bufio.Writer
+	record         []byte
+	vlrs           []VLR
+	intensityScale float64
+	// started is set once the header and VLRs are written
+	started bool
+}
+
+// NewLasWriter creates a LAS file using the point format, scale factors and
+// offsets of header; point formats 0-3 are supported. A LAS 1.4 file, with
+// 64-bit point counts, is written if header is that of a 1.4 file; any other
+// version is written as LAS 1.2. The defaults are those of NewLazWriter. The
+// point counts and bounds are computed from the points that are written. VLRs
+// are supplied with WithVLRs or added with AddVLR until the first point is
+// written. An existing file is not replaced unless WithOverwrite(true) is
+// supplied; IntensityScale and ConvertPointFormat are also honoured.
+func NewLasWriter(fileName string, header LasHeader, opts ...WriterOption) (*LasWriter, error) {
+	o := newWriterOptions(opts)
+	if err := o.check(); err != nil {
+		return nil, err
+	}
+	if o.convertFormat {
+		header.PointFormatID = o.pointFormat
+	}
+	h, err := prepareWriterHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	h.HeaderSize = lasWriterHeaderSize
+	if header.versionAtLeast(1, 4) {
+		h.VersionMinor = 4
+		h.HeaderSize = las14WriterHeaderSize
+	}
+	h.OffsetToPoints = h.HeaderSize
+	h.NumberOfVLRs = 0
+	h.WaveformDataStart, h.StartOfFirstEVLR, h.NumberOfEVLRs, h.ExtendedNumberPoints = 0, 0, 0, 0
+	h.MinX, h.MinY, h.MinZ = math.Inf(1), math.Inf(1), math.Inf(1)
+	h.MaxX, h.MaxY, h.MaxZ = math.Inf(-1), math.Inf(-1), math.Inf(-1)
+	lw := &LasWriter{
+		fileName:       fileName,
+		header:         h,
+		record:         make([]byte, h.PointRecordLength),
+		intensityScale: o.intensityScale,
+	}
+	for _, vlr := range o.vlrs {
+		if err = lw.AddVLR(vlr); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = o.checkOutput(fileName); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(fileName)
+	if err != nil {
+		return nil, err
+	}
+	lw.f, lw.w = f, bufio.NewWriter(f)
+	return lw, nil
+}
+
+// AddVLR appends a VLR to those written after the header, updating the VLR
+// count and point data offset of the header. VLRs can only be added before
+// the first point is written; ErrVLRAfterPoints is returned otherwise.
+func (lw *LasWriter) AddVLR(vlr VLR) error {
+	if lw.started {
+		return ErrVLRAfterPoints
+	}
+	if err := checkVLRSize(vlr); err != nil {
+		return err
+	}
+	lw.vlrs = append(lw.vlrs, vlr)
+	lw.header.NumberOfVLRs++
+	lw.header.OffsetToPoints += 54 + len(vlr.BinaryData)
+	return nil
+}
+
+// start writes a placeholder for the header, which is rewritten with the
+// final counts and bounds on Close, followed by the VLRs.
+func (lw *LasWriter) start() error {
+	if lw.started {
+		return nil
+	}
+	lw.started = true
+	if _, err := lw.w.Write(make([]byte, lw.header.HeaderSize)); err != nil {
+		return err
+	}
+	for _, vlr := range lw.vlrs {
+		if _, err := lw.w.Write(encodeVLR(vlr)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WritePoint encodes and writes a point. ErrCoordinateOutOfRange is returned
+// if a coordinate cannot be stored using the scale factors and offsets of the
+// file.
+func (lw *LasWriter) WritePoint(p LasPointer) error {
+	pd := p.PointData()
+	h := &lw.header
+	if err := checkPointRange(h, pd); err != nil {
+		return err
+	}
+	if err := lw.start(); err != nil {
+		return err
+	}
+	b := lw.record
+	binary.LittleEndian.PutUint32(b[0:4], uint32(int32(math.Round((pd.X-h.XOffset)/h.XScaleFactor))))
+	binary.LittleEndian.PutUint32(b[4:8], uint32(int32(math.Round((pd.Y-h.YOffset)/h.YScaleFactor))))
+	binary.LittleEndian.PutUint32(b[8:12], uint32(int32(math.Round((pd.Z-h.ZOffset)/h.ZScaleFactor))))
+	intensity := pd.Intensity
+	if lw.intensityScale != 1.0 {
+		intensity = scaleIntensity(intensity, lw.intensityScale)
+	}
+	binary.LittleEndian.PutUint16(b[12:14], intensity)
+	b[14] = pd.BitField.Value
+	b[15] = pd.ClassBitField.Value
+	b[16] = uint8(pd.ScanAngle)
+	b[17] = pd.UserData
+	binary.LittleEndian.PutUint16(b[18:20], pd.PointSourceID)
+	offset := 20
+	if h.PointFormatID == 1 || h.PointFormatID == 3 {
+		gpsTime := 0.0
+		if p.Format() == 1 || p.Format() == 3 {
+			gpsTime = p.GpsTimeData()
+		}
+		binary.LittleEndian.PutUint64(b[offset:offset+8], math.Float64bits(gpsTime))
+		offset += 8
+	}
+	if h.PointFormatID == 2 || h.PointFormatID == 3 {
+		rgb := p.RgbData()
+		if rgb == nil {
+			rgb = &RgbData{}
+		}
+		binary.LittleEndian.PutUint16(b[offset:offset+2], rgb.Red)
+		binary.LittleEndian.PutUint16(b[offset+2:offset+4], rgb.Green)
+		binary.LittleEndian.PutUint16(b[offset+4:offset+6], rgb.Blue)
+	}
+	if _, err := lw.w.Write(b); err != nil {
+		return fmt.Errorf("failed to write point: %v", err)
+	}
+
+	h.NumberPoints++
+	// LAS 1.2 counts returns 1-5; LAS 1.4 counts all seven of the 3-bit field.
+	maxReturn := byte(5)
+	if h.versionAtLeast(1, 4) {
+		maxReturn = 7
+	}
+	if r := pd.BitField.ReturnNumber(); r <= maxReturn {
+		h.NumberPointsByReturn[r-1]++
+	}
+	h.MinX, h.MaxX = math.Min(h.MinX, pd.X), math.Max(h.MaxX, pd.X)
+	h.MinY, h.MaxY = math.Min(h.MinY, pd.Y), math.Max(h.MaxY, pd.Y)
+	h.MinZ, h.MaxZ = math.Min(h.MinZ, pd.Z), math.Max(h.MaxZ, pd.Z)
+	return nil
+}
+
+// Close writes the final point counts and bounds to the header and closes the file.
+func (lw *LasWriter) Close() error {
+	if lw.f == nil {
+		return nil
+	}
+	defer func() { lw.f = nil }()
+	if err := lw.start(); err != nil {
+		lw.f.Close()
+		return err
+	}
+	if err := lw.w.Flush(); err != nil {
+		lw.f.Close()
+		return err
+	}
+	h := lw.header
+	if h.NumberPoints == 0 {
+		h.MinX, h.MinY, h.MinZ, h.MaxX, h.MaxY, h.MaxZ = 0, 0, 0, 0, 0, 0
+	}
+	b := encodeLas12Header(&h)
+	if h.versionAtLeast(1, 4) {
+		b = encodeLas14Header(&h)
+	}
+	if _, err := lw.f.WriteAt(b, 0); err != nil {
+		lw.f.Close()
+		return err
+	}
+	return lw.f.Close()
+}
+
+// encodeLas12Header encodes a LAS 1.2 public header block, which is also the
+// start of a LAS 1.4 header.
+func encodeLas12Header(h *LasHeader) []byte {
+	b := make([]byte, lasWriterHeaderSize)
+	le := binary.LittleEndian
+	copy(b[0:4], "LASF")
+	le.PutUint16(b[4:6], uint16(h.FileSourceID))
+	le.PutUint16(b[6:8], h.GlobalEncoding.Value)
+	le.PutUint32(b[8:12], uint32(h.ProjectID1))
+	le.PutUint16(b[12:14], uint16(h.ProjectID2))
+	le.PutUint16(b[14:16], uint16(h.ProjectID3))
+	copy(b[16:24], h.ProjectID4[:])
+	b[24], b[25] = h.VersionMajor, h.VersionMinor
+	copy(b[26:58], h.SystemID)
+	copy(b[58:90], h.GeneratingSoftware)
+	le.PutUint16(b[90:92], uint16(h.FileCreationDay))
+	le.PutUint16(b[92:94], uint16(h.FileCreationYear))
+	le.PutUint16(b[94:96], uint16(h.HeaderSize))
+	le.PutUint32(b[96:100], uint32(h.OffsetToPoints))
+	le.PutUint32(b[100:104], uint32(h.NumberOfVLRs))
+	b[104] = h.PointFormatID
+	le.PutUint16(b[105:107], uint16(h.PointRecordLength))
+	// The legacy counts are zero if the count does not fit, as LAS 1.4
+	// requires; a LAS 1.2 file can never hold that many points.
+	legacy := func(n int) uint32 {
+		if uint64(n) > math.MaxUint32 {
+			return 0
+		}
+		return uint32(n)
+	}
+	le.PutUint32(b[107:111], legacy(h.NumberPoints))
+	for i := 0; i < 5; i++ {
+		le.PutUint32(b[111+4*i:115+4*i], legacy(h.NumberPointsByReturn[i]))
+	}
+	values := []float64{h.XScaleFactor, h.YScaleFactor, h.ZScaleFactor, h.XOffset, h.YOffset, h.ZOffset,
+		h.MaxX, h.MinX, h.MaxY, h.MinY, h.MaxZ, h.MinZ}
+	for i, v := range values {
+		le.PutUint64(b[131+8*i:139+8*i], math.Float64bits(v))
+	}
+	return b
+}
+
+// encodeLas14Header encodes a LAS 1.4 public header block: the LAS 1.2 fields
+// followed by the waveform and EVLR offsets, which LasWriter leaves at zero,
+// and the 64-bit point counts.
+func encodeLas14Header(h *LasHeader) []byte {
+	b := make([]byte, las14WriterHeaderSize)
+	copy(b, encodeLas12Header(h))
+	le := binary.LittleEndian
+	le.PutUint64(b[227:235], h.WaveformDataStart)
+	le.PutUint64(b[235:243], h.StartOfFirstEVLR)
+	le.PutUint32(b[243:247], uint32(h.NumberOfEVLRs))
+	le.PutUint64(b[247:255], uint64(h.NumberPoints))
+	for i := 0; i < 15; i++ {
+		le.PutUint64(b[255+8*i:263+8*i], uint64(h.NumberPointsByReturn[i]))
+	}
+	return b
+}
+
+// encodeVLR encodes a VLR header followed by its payload.
+func encodeVLR(vlr VLR) []byte {
+	b := make([]byte, 54, 54+len(vlr.BinaryData))
+	le := binary.LittleEndian
+	le.PutUint16(b[0:2], uint16(vlr.Reserved))
+	copy(b[2:18], fixedLengthString(vlr.UserID, 16))
+	le.PutUint16(b[18:20], uint16(vlr.RecordID))
+	le.PutUint16(b[20:22], uint16(len(vlr.BinaryData)))
+	copy(b[22:54], fixedLengthString(vlr.Description, 32))
+	return append(b, vlr.BinaryData...)
+}
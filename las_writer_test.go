@@ -0,0 +1,273 @@
+package lidario
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestLasWriterRoundTrip(t *testing.T) {
+	for _, format := range []uint8{0, 1, 2, 3} {
+		fileName := filepath.Join(t.TempDir(), "roundtrip.las")
+		header := LasHeader{PointFormatID: format, XScaleFactor: 0.01, YScaleFactor: 0.01, ZScaleFactor: 0.01,
+			MinX: 500000, MaxX: 501000, MinY: 4400000, MaxY: 4401000, MinZ: 0, MaxZ: 100}
+		lw, err := NewLasWriter(fileName, header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		const n = 1000
+		points := make([]LasPointer, n)
+		for i := range points {
+			p0 := &PointRecord0{
+				X:             500000 + float64(i%100)*10.123,
+				Y:             4400000 + float64(i/100)*10.456,
+				Z:             float64(i%97) * 0.789,
+				Intensity:     uint16(i),
+				BitField:      PointBitField{Value: uint8(1+i%2) | 2<<3},
+				ScanAngle:     int8(i%60 - 30),
+				UserData:      uint8(i),
+				PointSourceID: 7,
+			}
+			p0.ClassBitField.SetClassification(uint8(i % 10))
+			gpsTime, rgb := float64(i)*0.001, &RgbData{Red: uint16(i), Green: uint16(2 * i), Blue: uint16(3 * i)}
+			switch format {
+			case 0:
+				points[i] = p0
+			case 1:
+				points[i] = &PointRecord1{PointRecord0: p0, GPSTime: gpsTime}
+			case 2:
+				points[i] = &PointRecord2{PointRecord0: p0, RGB: rgb}
+			case 3:
+				points[i] = &PointRecord3{PointRecord0: p0, GPSTime: gpsTime, RGB: rgb}
+			}
+			if err = lw.WritePoint(points[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = lw.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		lf, err := NewLidarFile(fileName, "r")
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := lf.GetHeader()
+		if h.VersionMajor != 1 || h.VersionMinor != 2 || h.PointFormatID != format || lf.GetPointCount() != n {
+			t.Fatalf("format %v: unexpected header %+v", format, h)
+		}
+		if h.NumberPointsByReturn[0] != n/2 || h.NumberPointsByReturn[1] != n/2 {
+			t.Errorf("format %v: unexpected return counts %v", format, h.NumberPointsByReturn)
+		}
+		if h.MinX != 500000 || h.MaxZ != 96*0.789 {
+			t.Errorf("format %v: unexpected bounds [%v, %v]", format, h.MinX, h.MaxZ)
+		}
+		for i := 0; i < n; i++ {
+			got, err := lf.LasPoint(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			g, w := got.PointData(), points[i].PointData()
+			// The coordinates are rounded to the 0.01 scale factor.
+			const tolerance = 0.005 + 1e-6
+			if math.Abs(g.X-w.X) > tolerance || math.Abs(g.Y-w.Y) > tolerance || math.Abs(g.Z-w.Z) > tolerance {
+				t.Fatalf("format %v, point %v: (%v, %v, %v), expected (%v, %v, %v)", format, i, g.X, g.Y, g.Z, w.X, w.Y, w.Z)
+			}
+			if g.Intensity != w.Intensity || g.BitField != w.BitField || g.ClassBitField != w.ClassBitField ||
+				g.ScanAngle != w.ScanAngle || g.UserData != w.UserData || g.PointSourceID != w.PointSourceID {
+				t.Fatalf("format %v, point %v: got %+v, expected %+v", format, i, g, w)
+			}
+			if (format == 1 || format == 3) && got.GpsTimeData() != points[i].GpsTimeData() {
+				t.Fatalf("format %v, point %v: GPS time %v, expected %v", format, i, got.GpsTimeData(), points[i].GpsTimeData())
+			}
+			if (format == 2 || format == 3) && *got.RgbData() != *points[i].RgbData() {
+				t.Fatalf("format %v, point %v: colour %v, expected %v", format, i, got.RgbData(), points[i].RgbData())
+			}
+		}
+		lf.Close()
+	}
+}
+
+func TestLasWriterErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewLasWriter(filepath.Join(dir, "f6.las"), LasHeader{PointFormatID: 6}); err == nil {
+		t.Error("expected an error for point format 6")
+	}
+	lw, err := NewLasWriter(filepath.Join(dir, "range.las"), LasHeader{XScaleFactor: 0.001, YScaleFactor: 0.001, ZScaleFactor: 0.001})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lw.Close()
+	if err = lw.WritePoint(&PointRecord0{X: 1e7}); !errors.Is(err, ErrCoordinateOutOfRange) {
+		t.Errorf("expected ErrCoordinateOutOfRange, got %v", err)
+	}
+}
+
+func TestLasWriterVLRs(t *testing.T) {
+	src, err := NewLasFile("testdata/sample.las", "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	fileName := filepath.Join(t.TempDir(), "vlrs.las")
+	lw, err := NewLasWriter(fileName, src.Header, WithVLRs(src.VlrData...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = lw.WritePoint(&PointRecord1{PointRecord0: &PointRecord0{X: src.Header.MinX, Y: src.Header.MinY, Z: src.Header.MinZ}}); err != nil {
+		t.Fatal(err)
+	}
+	if err = lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	las, err := NewLasFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+	if len(las.VlrData) != len(src.VlrData) {
+		t.Fatalf("%v VLRs, expected %v", len(las.VlrData), len(src.VlrData))
+	}
+	for i, vlr := range las.VlrData {
+		want := src.VlrData[i]
+		if vlr.UserID != want.UserID || vlr.RecordID != want.RecordID || !bytes.Equal(vlr.BinaryData, want.BinaryData) {
+			t.Errorf("VLR %v: got %v/%v, expected %v/%v", i, vlr.UserID, vlr.RecordID, want.UserID, want.RecordID)
+		}
+	}
+	want, _ := src.GetCRS()
+	if crs, err := las.GetCRS(); err != nil || crs != want {
+		t.Errorf("CRS %q (%v), expected %q", crs, err, want)
+	}
+	if x, y, z, err := las.GetXYZ(0); err != nil || x != src.Header.MinX || y != src.Header.MinY || z != src.Header.MinZ {
+		t.Errorf("point (%v, %v, %v) (%v), expected the minimum of the source extent", x, y, z, err)
+	}
+}
+
+func TestLasWriterAddVLR(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "addvlr.las")
+	lw, err := NewLasWriter(fileName, LasHeader{XScaleFactor: 0.01, YScaleFactor: 0.01, ZScaleFactor: 0.01},
+		WithVLRs(VLR{UserID: "first", RecordID: 1, BinaryData: []byte{1, 2, 3}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := VLR{UserID: "LASF_Spec", RecordID: 0, Description: "Classification lookup", BinaryData: make([]byte, 16*256)}
+	copy(lookup.BinaryData[16:], "Ground")
+	if err = lw.AddVLR(lookup); err != nil {
+		t.Fatal(err)
+	}
+	if err = lw.AddVLR(VLR{UserID: "big", BinaryData: make([]byte, 65536)}); err == nil {
+		t.Error("expected an error for a payload that is too large")
+	}
+	if err = lw.WritePoint(&PointRecord0{X: 1, Y: 2, Z: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err = lw.AddVLR(VLR{UserID: "late"}); !errors.Is(err, ErrVLRAfterPoints) {
+		t.Errorf("expected ErrVLRAfterPoints, got %v", err)
+	}
+	if err = lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	las, err := NewLasFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+	if las.Header.NumberOfVLRs != 2 || las.Header.OffsetToPoints != lasWriterHeaderSize+54+3+54+len(lookup.BinaryData) {
+		t.Errorf("unexpected VLR count %v and point data offset %v", las.Header.NumberOfVLRs, las.Header.OffsetToPoints)
+	}
+	if len(las.VlrData) != 2 {
+		t.Fatalf("%v VLRs, expected 2", len(las.VlrData))
+	}
+	if vlr := las.VlrData[1]; vlr.UserID != lookup.UserID || vlr.Description != lookup.Description ||
+		!bytes.Equal(vlr.BinaryData, lookup.BinaryData) {
+		t.Errorf("unexpected VLR %v/%v %q", vlr.UserID, vlr.RecordID, vlr.Description)
+	}
+	if x, y, z, err := las.GetXYZ(0); err != nil || x != 1 || y != 2 || z != 3 {
+		t.Errorf("point (%v, %v, %v) (%v), expected (1, 2, 3)", x, y, z, err)
+	}
+}
+
+func TestLasWriterVersion14(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "v14.las")
+	header := LasHeader{VersionMajor: 1, VersionMinor: 4, PointFormatID: 1, XScaleFactor: 0.01, YScaleFactor: 0.01, ZScaleFactor: 0.01}
+	lw, err := NewLasWriter(fileName, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 7; i++ {
+		p := &PointRecord1{PointRecord0: &PointRecord0{X: float64(i), Y: 2, Z: 3, BitField: PointBitField{Value: uint8(i+1) | 7<<3}}, GPSTime: float64(i)}
+		if err = lw.WritePoint(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	las, err := NewLasFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+	h := las.Header
+	if h.VersionMinor != 4 || h.HeaderSize != 375 || h.OffsetToPoints != 375 {
+		t.Fatalf("unexpected header %+v", h)
+	}
+	if h.ExtendedNumberPoints != 7 || las.GetPointCount64() != 7 || h.NumberPoints != 7 {
+		t.Errorf("point counts %v and %v, expected 7", h.ExtendedNumberPoints, h.NumberPoints)
+	}
+	for i := 0; i < 7; i++ {
+		if h.NumberPointsByReturn[i] != 1 {
+			t.Errorf("return %v: count %v, expected 1", i+1, h.NumberPointsByReturn[i])
+		}
+	}
+	p, err := las.LasPoint(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PointData().X != 6 || p.GpsTimeData() != 6 {
+		t.Errorf("unexpected point %+v", p.PointData())
+	}
+}
+
+func TestLasWriterOptions(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "options.las")
+	header := LasHeader{XScaleFactor: 0.01, YScaleFactor: 0.01, ZScaleFactor: 0.01}
+	lw, err := NewLasWriter(fileName, header, IntensityScale(2), ConvertPointFormat(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = lw.WritePoint(&PointRecord0{X: 1, Y: 2, Z: 3, Intensity: 40000}); err != nil {
+		t.Fatal(err)
+	}
+	if err = lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewLasWriter(fileName, header); !errors.Is(err, ErrOutputExists) {
+		t.Errorf("expected ErrOutputExists, got %v", err)
+	}
+
+	las, err := NewLasFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+	p, err := las.LasPoint(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Format() != 1 || p.PointData().Intensity != math.MaxUint16 {
+		t.Errorf("format %v and intensity %v, expected 1 and %v", p.Format(), p.PointData().Intensity, math.MaxUint16)
+	}
+
+	lw, err = NewLasWriter(fileName, header, WithOverwrite(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lw.Close()
+}
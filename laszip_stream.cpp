@@ -0,0 +1,99 @@
+// LASzip only exposes stream input to C++, through a std::istream. This file
+// provides that stream for LaszipReader.OpenReaderStream: a read-only stream
+// buffer whose bytes are read through lidarioStreamRead, which is exported by
+// laszip_stream.go and reads from the io.ReadSeeker of the reader.
+
+#include <laszip/laszip_api.h>
+
+#include <cstdint>
+#include <istream>
+#include <streambuf>
+
+extern "C" {
+// Implemented in laszip_stream.go.
+int lidarioStreamRead(uintptr_t source, char* buf, int n, int64_t offset);
+}
+
+namespace {
+
+// lidarioStreamBuf serves reads from a block of the source. The position is
+// kept here, so seeking only calls into Go when the next read misses the block.
+class lidarioStreamBuf : public std::streambuf {
+public:
+	lidarioStreamBuf(uintptr_t source, int64_t size) : source_(source), size_(size), base_(0) {
+		setg(buf_, buf_, buf_);
+	}
+
+protected:
+	int_type underflow() override {
+		if (gptr() < egptr()) {
+			return traits_type::to_int_type(*gptr());
+		}
+		base_ += egptr() - eback();
+		int n = lidarioStreamRead(source_, buf_, sizeof(buf_), base_);
+		if (n <= 0) {
+			setg(buf_, buf_, buf_);
+			return traits_type::eof();
+		}
+		setg(buf_, buf_, buf_ + n);
+		return traits_type::to_int_type(*gptr());
+	}
+
+	pos_type seekoff(off_type off, std::ios_base::seekdir dir, std::ios_base::openmode which) override {
+		if (!(which & std::ios_base::in)) {
+			return pos_type(off_type(-1));
+		}
+		int64_t pos = off;
+		if (dir == std::ios_base::cur) {
+			pos += base_ + (gptr() - eback());
+		} else if (dir == std::ios_base::end) {
+			pos += size_;
+		}
+		if (pos < 0 || pos > size_) {
+			return pos_type(off_type(-1));
+		}
+		if (pos >= base_ && pos <= base_ + (egptr() - eback())) {
+			setg(eback(), eback() + (pos - base_), egptr());
+		} else {
+			base_ = pos;
+			setg(buf_, buf_, buf_);
+		}
+		return pos_type(pos);
+	}
+
+	pos_type seekpos(pos_type pos, std::ios_base::openmode which) override {
+		return seekoff(off_type(pos), std::ios_base::beg, which);
+	}
+
+private:
+	uintptr_t source_;
+	int64_t size_;
+	// base_ is the position of the first byte of buf_ in the source
+	int64_t base_;
+	char buf_[1 << 16];
+};
+
+struct lidarioStream {
+	lidarioStreamBuf buf;
+	std::istream in;
+
+	lidarioStream(uintptr_t source, int64_t size) : buf(source, size), in(&buf) {}
+};
+
+} // namespace
+
+extern "C" {
+
+void* lidario_new_stream(uintptr_t source, int64_t size) {
+	return new lidarioStream(source, size);
+}
+
+void lidario_delete_stream(void* stream) {
+	delete static_cast<lidarioStream*>(stream);
+}
+
+laszip_I32 lidario_open_reader_stream(laszip_POINTER pointer, void* stream, laszip_BOOL* is_compressed) {
+	return laszip_open_reader_stream(pointer, static_cast<lidarioStream*>(stream)->in, is_compressed);
+}
+
+} // extern "C"
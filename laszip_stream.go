@@ -0,0 +1,121 @@
+package lidario
+
+/*
+#include <laszip/laszip_api.h>
+#include <stdint.h>
+
+// Implemented in laszip_stream.cpp.
+void* lidario_new_stream(uintptr_t source, int64_t size);
+void lidario_delete_stream(void* stream);
+laszip_I32 lidario_open_reader_stream(laszip_POINTER pointer, void* stream, laszip_BOOL* is_compressed);
+*/
+import "C"
+
+import (
+	"io"
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+)
+
+// streamSource is the data of a LAZ stream read by LASzip. It reads the
+// stream at explicit offsets, so the position of the stream is irrelevant and
+// the raw records that LASzip does not expose can be read from the same
+// stream while the points are decompressed.
+type streamSource struct {
+	sync.Mutex
+	r    io.ReadSeeker
+	size int64
+	// err holds the first error of a read made by LASzip, which only sees
+	// the end of the stream
+	err error
+}
+
+func newStreamSource(r io.ReadSeeker) (*streamSource, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	return &streamSource{r: r, size: size}, nil
+}
+
+// ReadAt reads len(b) bytes from offset off of the stream.
+func (s *streamSource) ReadAt(b []byte, off int64) (int, error) {
+	if ra, ok := s.r.(io.ReaderAt); ok {
+		return ra.ReadAt(b, off)
+	}
+	s.Lock()
+	defer s.Unlock()
+	if _, err := s.r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(s.r, b)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Size returns the length of the stream.
+func (s *streamSource) Size() int64 {
+	return s.size
+}
+
+// Close does nothing; the stream belongs to the caller.
+func (s *streamSource) Close() error {
+	return nil
+}
+
+// lidarioStreamRead reads up to n bytes at offset into buf for the stream
+// buffer of laszip_stream.cpp. It returns the number of bytes read, which is
+// zero at the end of the stream, or -1 on error.
+//
+//export lidarioStreamRead
+func lidarioStreamRead(source C.uintptr_t, buf *C.char, n C.int, offset C.int64_t) C.int {
+	s := cgo.Handle(source).Value().(*streamSource)
+	if int64(offset) >= s.size {
+		return 0
+	}
+	b := unsafe.Slice((*byte)(unsafe.Pointer(buf)), int(n))
+	if rest := s.size - int64(offset); int64(len(b)) > rest {
+		b = b[:rest]
+	}
+	m, err := s.ReadAt(b, int64(offset))
+	if err != nil && err != io.EOF {
+		if s.err == nil {
+			s.err = err
+		}
+		return -1
+	}
+	return C.int(m)
+}
+
+// lazStream is a std::istream, created by laszip_stream.cpp, that reads from
+// a streamSource.
+type lazStream struct {
+	source *streamSource
+	handle cgo.Handle
+	stream unsafe.Pointer
+}
+
+func newLazStream(source *streamSource) *lazStream {
+	h := cgo.NewHandle(source)
+	return &lazStream{
+		source: source,
+		handle: h,
+		stream: C.lidario_new_stream(C.uintptr_t(h), C.int64_t(source.size)),
+	}
+}
+
+// open opens the stream with the LASzip reader of pointer.
+func (ls *lazStream) open(pointer C.laszip_POINTER) C.laszip_I32 {
+	var isCompressed C.laszip_BOOL
+	return C.lidario_open_reader_stream(pointer, ls.stream, &isCompressed)
+}
+
+// close releases the stream. It must not be called until LASzip has closed
+// the reader.
+func (ls *lazStream) close() {
+	C.lidario_delete_stream(ls.stream)
+	ls.handle.Delete()
+}
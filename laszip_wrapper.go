@@ -2,18 +2,113 @@ package lidario
 
 /*
 #cgo CFLAGS: -I/opt/homebrew/opt/laszip/include
+#cgo CXXFLAGS: -I/opt/homebrew/opt/laszip/include
 #cgo LDFLAGS: -L/opt/homebrew/opt/laszip/lib -llaszip
 
 #include <laszip/laszip_api.h>
 #include <stdlib.h>
+
+// lidario_point is a flat copy of a point. cgo cannot access the bit fields
+// of laszip_point_struct, so points are copied into this struct in C.
+typedef struct {
+	double x, y, z, gps_time;
+	laszip_I32 raw_x, raw_y, raw_z;
+	laszip_U16 intensity, point_source_ID, rgb[4];
+	laszip_U8 return_number, number_of_returns, scan_direction_flag, edge_of_flight_line;
+	laszip_U8 classification, synthetic, keypoint, withheld, user_data;
+	laszip_I8 scan_angle_rank;
+	double scan_angle;
+	laszip_U8 scanner_channel;
+	laszip_U8 wave_packet[29];
+} lidario_point;
+
+// copy_point copies the current point. Point formats 6 and above store up to
+// 15 returns, 256 classes and the classification flags in the extended fields.
+static void copy_point(laszip_POINTER pointer, laszip_point_struct* p, int extended, lidario_point* out) {
+	laszip_F64 coordinates[3];
+	laszip_get_coordinates(pointer, coordinates);
+	out->x = coordinates[0];
+	out->y = coordinates[1];
+	out->z = coordinates[2];
+	out->raw_x = p->X;
+	out->raw_y = p->Y;
+	out->raw_z = p->Z;
+	out->gps_time = p->gps_time;
+	out->intensity = p->intensity;
+	out->point_source_ID = p->point_source_ID;
+	for (int i = 0; i < 4; i++) {
+		out->rgb[i] = p->rgb[i];
+	}
+	for (int i = 0; i < 29; i++) {
+		out->wave_packet[i] = p->wave_packet[i];
+	}
+	out->scan_direction_flag = p->scan_direction_flag;
+	out->edge_of_flight_line = p->edge_of_flight_line;
+	out->user_data = p->user_data;
+	out->scan_angle_rank = p->scan_angle_rank;
+	out->scan_angle = p->scan_angle_rank;
+	out->scanner_channel = 0;
+	if (extended) {
+		// The scan angle is stored in increments of 0.006 degrees; the legacy
+		// rank is derived from it, rounded and clamped to the int8 range.
+		out->scan_angle = 0.006 * p->extended_scan_angle;
+		double rank = out->scan_angle < 0 ? out->scan_angle - 0.5 : out->scan_angle + 0.5;
+		out->scan_angle_rank = rank < -128 ? -128 : (rank > 127 ? 127 : (laszip_I8)rank);
+		out->scanner_channel = p->extended_scanner_channel;
+		out->return_number = p->extended_return_number;
+		out->number_of_returns = p->extended_number_of_returns;
+		out->classification = p->extended_classification;
+		out->synthetic = (p->extended_classification_flags & 1) != 0;
+		out->keypoint = (p->extended_classification_flags & 2) != 0;
+		out->withheld = (p->extended_classification_flags & 4) != 0;
+	} else {
+		out->return_number = p->return_number;
+		out->number_of_returns = p->number_of_returns;
+		out->classification = p->classification;
+		out->synthetic = p->synthetic_flag;
+		out->keypoint = p->keypoint_flag;
+		out->withheld = p->withheld_flag;
+	}
+}
+
+// read_points reads up to n points into out, stopping at the first failed
+// read, and returns the number of points read. The code returned by the
+// failed read is stored in code.
+static int read_points(laszip_POINTER pointer, laszip_point_struct* p, int extended, lidario_point* out, int n, laszip_I32* code) {
+	for (int i = 0; i < n; i++) {
+		if ((*code = laszip_read_point(pointer)) != 0) {
+			return i;
+		}
+		copy_point(pointer, p, extended, &out[i]);
+	}
+	return n;
+}
 */
 import "C"
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strings"
 	"unsafe"
 )
 
+var (
+	// ErrPointOutOfRange is returned when a point index lies outside of the file.
+	ErrPointOutOfRange = errors.New("point index out of range")
+	// ErrRandomAccessUnsupported is returned when LASzip cannot seek to a
+	// point, for instance because the file has no chunk table.
+	ErrRandomAccessUnsupported = errors.New("random access is not supported by this file")
+	// ErrReaderClosed is returned when reading from a reader that is not open.
+	ErrReaderClosed = errors.New("reader not open")
+	// ErrCorruptFile is returned when a file is truncated or its header or
+	// compressed data are inconsistent.
+	ErrCorruptFile = errors.New("corrupt LAZ file")
+)
+
 // LaszipReader wraps the LASzip C API for reading compressed LAZ files
 type LaszipReader struct {
 	pointer      C.laszip_POINTER
@@ -22,9 +117,29 @@ type LaszipReader struct {
 	isOpen       bool
 	pointCount   uint64
 	currentPoint uint64
+	streaming    bool
+	batch        []C.lidario_point
+	// failed holds the error of a failed read; decompression cannot resume
+	// after a failure, so later reads return it until the reader seeks.
+	failed error
+	// stream is the stream opened by OpenReaderStream
+	stream *lazStream
+	// fileName is reported if the reader is garbage collected without
+	// having been closed
+	fileName string
+	// logger receives the finalizer warning; nil discards it
+	logger *slog.Logger
 }
 
-// NewLaszipReader creates a new LASzip reader
+// warnUnclosed is called by the finalizer of a reader that was not closed.
+var warnUnclosed = func(r *LaszipReader) {
+	loggerOrDiscard(r.logger).Warn("LaszipReader garbage collected without being closed; call Close to release it", "file", r.fileName)
+}
+
+// NewLaszipReader creates a new LASzip reader. The memory allocated by LASzip
+// is only released by Close; as a safeguard, a reader that is garbage
+// collected without being closed is closed by a finalizer, which logs a
+// warning. See DisableFinalizer.
 func NewLaszipReader() (*LaszipReader, error) {
 	reader := &LaszipReader{}
 
@@ -33,9 +148,27 @@ func NewLaszipReader() (*LaszipReader, error) {
 		return nil, err
 	}
 
+	runtime.SetFinalizer(reader, finalizeReader)
 	return reader, nil
 }
 
+// finalizeReader closes a reader that was garbage collected without being closed.
+func finalizeReader(r *LaszipReader) {
+	if r.pointer == nil {
+		return
+	}
+	warnUnclosed(r)
+	r.Close()
+}
+
+// DisableFinalizer removes the finalizer that closes the reader if it is
+// garbage collected without being closed. Callers that manage the lifetime of
+// the reader themselves can use it to avoid the cost of finalization; the
+// LASzip memory then leaks unless Close is called.
+func (r *LaszipReader) DisableFinalizer() {
+	runtime.SetFinalizer(r, nil)
+}
+
 // create initializes the LASzip pointer
 func (r *LaszipReader) create() error {
 	result := C.laszip_create(&r.pointer)
@@ -56,85 +189,266 @@ func (r *LaszipReader) create() error {
 
 // OpenReader opens a LAZ file for reading
 func (r *LaszipReader) OpenReader(filename string) error {
+	if r.pointer == nil {
+		return ErrReaderClosed
+	}
 	if r.isOpen {
 		return errors.New("reader already open")
 	}
 
-	cFilename := C.CString(filename)
+	// Reject inconsistent headers up front rather than leaving LASzip to
+	// fail, or misbehave, while decompressing.
+	f, err := openRawFile(filename)
+	if err != nil {
+		return err
+	}
+	err = validatePointDataHeader(f, f.Size())
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	r.fileName = filename
+	cFilename := C.CString(nativePath(filename))
 	defer C.free(unsafe.Pointer(cFilename))
 
 	// Open the reader
 	var isCompressed C.laszip_BOOL
 	result := C.laszip_open_reader(r.pointer, cFilename, &isCompressed)
 	if result != 0 {
-		return r.getError()
+		return r.getError(result)
 	}
+	return r.opened()
+}
 
+// opened prepares a reader whose file or stream LASzip has opened.
+func (r *LaszipReader) opened() error {
 	// Get header
-	result = C.laszip_get_header_pointer(r.pointer, &r.header)
+	result := C.laszip_get_header_pointer(r.pointer, &r.header)
 	if result != 0 {
-		return r.getError()
+		return r.getError(result)
 	}
 
 	// Get point
 	result = C.laszip_get_point_pointer(r.pointer, &r.point)
 	if result != 0 {
-		return r.getError()
+		return r.getError(result)
 	}
 
+	// The 64-bit count of a LAS 1.4 file supersedes the legacy count.
 	r.pointCount = uint64(r.header.number_of_point_records)
+	if extended := uint64(r.header.extended_number_of_point_records); extended > r.pointCount {
+		r.pointCount = extended
+	}
+	// Files written by streaming writers may declare zero points; the real
+	// count is only discovered by reading until the decompressor runs dry.
+	r.streaming = r.pointCount == 0
 	r.currentPoint = 0
 	r.isOpen = true
 
 	return nil
 }
 
-// ReadPoint reads the next point from the LAZ file
+// OpenReaderStream opens a LAZ stream for reading. LASzip reads the stream
+// directly, through the std::istream of its C++ stream API, so the data are
+// neither copied to a temporary file nor read into memory as a whole. Reads
+// are made at explicit offsets, so the position of the stream does not
+// matter; the stream must not be used elsewhere until the reader is closed.
+func (r *LaszipReader) OpenReaderStream(stream io.ReadSeeker) error {
+	source, err := newStreamSource(stream)
+	if err != nil {
+		return err
+	}
+	return r.openStream(source)
+}
+
+// openStream opens the stream of source. A LazFile reads the raw records of
+// the stream, which LASzip does not expose, from the same source.
+func (r *LaszipReader) openStream(source *streamSource) error {
+	if r.pointer == nil {
+		return ErrReaderClosed
+	}
+	if r.isOpen {
+		return errors.New("reader already open")
+	}
+	if err := validatePointDataHeader(source, source.Size()); err != nil {
+		return err
+	}
+
+	ls := newLazStream(source)
+	if result := ls.open(r.pointer); result != 0 {
+		err := r.getError(result)
+		if source.err != nil {
+			err = fmt.Errorf("reading the stream: %w", source.err)
+		}
+		// LASzip keeps no reference to a stream it failed to open.
+		ls.close()
+		return err
+	}
+	r.stream = ls
+	return r.opened()
+}
+
+// ReadPoint reads the next point from the LAZ file. io.EOF is returned once
+// all points have been read.
 func (r *LaszipReader) ReadPoint() error {
 	if !r.isOpen {
-		return errors.New("reader not open")
+		return ErrReaderClosed
 	}
 
-	if r.currentPoint >= r.pointCount {
-		return errors.New("EOF: no more points")
+	if r.failed != nil {
+		return r.failed
+	}
+
+	if !r.streaming && r.currentPoint >= r.pointCount {
+		return io.EOF
 	}
 
 	result := C.laszip_read_point(r.pointer)
 	if result != 0 {
-		return r.getError()
+		if r.streaming {
+			// The declared count was zero, so a failed read marks the natural
+			// end of the compressed data rather than an error.
+			r.streaming = false
+			r.pointCount = r.currentPoint
+			return io.EOF
+		}
+		return r.readFailed(result)
 	}
 
 	r.currentPoint++
 	return nil
 }
 
+// SeekPoint positions the reader so that the next call to ReadPoint reads the
+// point with the given index. Seeking to the current position is a no-op.
+func (r *LaszipReader) SeekPoint(index uint64) error {
+	if !r.isOpen {
+		return ErrReaderClosed
+	}
+	if index == r.currentPoint {
+		return nil
+	}
+	if !r.streaming && index >= r.pointCount {
+		return fmt.Errorf("%w: %v", ErrPointOutOfRange, index)
+	}
+
+	result := C.laszip_seek_point(r.pointer, C.laszip_I64(index))
+	if result != 0 {
+		return fmt.Errorf("%w: %w", ErrRandomAccessUnsupported, r.getError(result))
+	}
+
+	r.currentPoint = index
+	r.failed = nil
+	return nil
+}
+
+// Reset rewinds the reader to the first point, so that the points can be read
+// again without reopening the file.
+func (r *LaszipReader) Reset() error {
+	if !r.isOpen {
+		return ErrReaderClosed
+	}
+	result := C.laszip_seek_point(r.pointer, 0)
+	if result != 0 {
+		return fmt.Errorf("%w: %w", ErrRandomAccessUnsupported, r.getError(result))
+	}
+	r.currentPoint = 0
+	r.failed = nil
+	return nil
+}
+
+// IsStreaming returns true while reading a file whose header declares zero
+// points; the point count is unknown until the end of the data is reached.
+func (r *LaszipReader) IsStreaming() bool {
+	return r.streaming
+}
+
+// PointsRead returns the number of points read so far.
+func (r *LaszipReader) PointsRead() uint64 {
+	return r.currentPoint
+}
+
 // GetPoint returns the current point data
 func (r *LaszipReader) GetPoint() *LaszipPoint {
 	if !r.isOpen || r.point == nil {
 		return nil
 	}
 
-	// Get the real coordinates using scale and offset
-	var coordinates [3]C.laszip_F64
-	C.laszip_get_coordinates(r.pointer, &coordinates[0])
+	var cp C.lidario_point
+	C.copy_point(r.pointer, r.point, r.extended(), &cp)
+	lp := LaszipPoint{}
+	lp.fromC(&cp)
+	return &lp
+}
 
-	// For now, create a simple point with basic information
-	// TODO: Properly extract bit fields from the C structure
-	return &LaszipPoint{
-		X:                 float64(coordinates[0]),
-		Y:                 float64(coordinates[1]),
-		Z:                 float64(coordinates[2]),
-		Intensity:         uint16(r.point.intensity),
-		ReturnNumber:      1, // Default values for now
-		NumberOfReturns:   1,
-		ScanDirectionFlag: 0,
-		EdgeOfFlightFlag:  0,
-		Classification:    0,
-		ScanAngleRank:     int8(r.point.scan_angle_rank),
-		UserData:          uint8(r.point.user_data),
-		PointSourceID:     uint16(r.point.point_source_ID),
-		GPSTime:           float64(r.point.gps_time),
+// ExtraBytes returns a copy of the extra bytes of the current point, which
+// are described by the Extra Bytes VLR of the file.
+func (r *LaszipReader) ExtraBytes() []byte {
+	if !r.isOpen || r.point == nil || r.point.num_extra_bytes <= 0 {
+		return nil
+	}
+	return C.GoBytes(unsafe.Pointer(r.point.extra_bytes), C.int(r.point.num_extra_bytes))
+}
+
+// extended returns 1 if the point format stores the extended point fields.
+func (r *LaszipReader) extended() C.int {
+	if r.header.point_data_format >= 6 {
+		return 1
+	}
+	return 0
+}
+
+// ReadPointsInto reads the next len(buf) points into buf, reusing its memory,
+// and returns the number of points read. The points are decompressed in a
+// single cgo call, which is much faster than calling ReadPoint and GetPoint
+// for each point. Fewer points are read at the end of the file; once no points
+// remain, 0 and io.EOF are returned.
+func (r *LaszipReader) ReadPointsInto(buf []LaszipPoint) (int, error) {
+	if !r.isOpen {
+		return 0, ErrReaderClosed
+	}
+	if r.failed != nil {
+		return 0, r.failed
 	}
+	n := len(buf)
+	if !r.streaming && uint64(n) > r.pointCount-r.currentPoint {
+		n = int(r.pointCount - r.currentPoint)
+	}
+	if n == 0 {
+		return 0, io.EOF
+	}
+	if cap(r.batch) < n {
+		r.batch = make([]C.lidario_point, n)
+	}
+	cps := r.batch[:n]
+	var code C.laszip_I32
+	read := int(C.read_points(r.pointer, r.point, r.extended(), &cps[0], C.int(n), &code))
+	r.currentPoint += uint64(read)
+	for i := 0; i < read; i++ {
+		buf[i].fromC(&cps[i])
+	}
+	if read < n {
+		if r.streaming {
+			// The declared count was zero, so a failed read marks the natural
+			// end of the compressed data rather than an error.
+			r.streaming = false
+			r.pointCount = r.currentPoint
+			if read == 0 {
+				return 0, io.EOF
+			}
+			return read, nil
+		}
+		return read, r.readFailed(code)
+	}
+	return read, nil
+}
+
+// readFailed records and returns the error of a failed read of the current
+// point, typically caused by truncated or corrupt compressed data.
+func (r *LaszipReader) readFailed(code C.laszip_I32) error {
+	r.failed = fmt.Errorf("%w: reading point %v: %w", ErrCorruptFile, r.currentPoint, r.getError(code))
+	return r.failed
 }
 
 // GetHeader returns the LAZ file header information
@@ -143,58 +457,149 @@ func (r *LaszipReader) GetHeader() *LaszipHeader {
 		return nil
 	}
 
-	return &LaszipHeader{
-		VersionMajor:          uint8(r.header.version_major),
-		VersionMinor:          uint8(r.header.version_minor),
-		HeaderSize:            uint16(r.header.header_size),
-		OffsetToPointData:     uint32(r.header.offset_to_point_data),
-		NumberOfVLRs:          uint32(r.header.number_of_variable_length_records),
-		PointDataFormat:       uint8(r.header.point_data_format),
-		PointDataRecordLength: uint16(r.header.point_data_record_length),
-		NumberOfPointRecords:  uint32(r.header.number_of_point_records),
-		XScaleFactor:          float64(r.header.x_scale_factor),
-		YScaleFactor:          float64(r.header.y_scale_factor),
-		ZScaleFactor:          float64(r.header.z_scale_factor),
-		XOffset:               float64(r.header.x_offset),
-		YOffset:               float64(r.header.y_offset),
-		ZOffset:               float64(r.header.z_offset),
-		MaxX:                  float64(r.header.max_x),
-		MinX:                  float64(r.header.min_x),
-		MaxY:                  float64(r.header.max_y),
-		MinY:                  float64(r.header.min_y),
-		MaxZ:                  float64(r.header.max_z),
-		MinZ:                  float64(r.header.min_z),
-	}
-}
-
-// Close closes the LAZ reader
-func (r *LaszipReader) Close() error {
-	if !r.isOpen {
+	h := &LaszipHeader{
+		FileSourceID:                 uint16(r.header.file_source_ID),
+		GlobalEncoding:               uint16(r.header.global_encoding),
+		ProjectID1:                   uint32(r.header.project_ID_GUID_data_1),
+		ProjectID2:                   uint16(r.header.project_ID_GUID_data_2),
+		ProjectID3:                   uint16(r.header.project_ID_GUID_data_3),
+		VersionMajor:                 uint8(r.header.version_major),
+		VersionMinor:                 uint8(r.header.version_minor),
+		SystemID:                     trimHeaderString(C.GoStringN(&r.header.system_identifier[0], 32)),
+		GeneratingSoftware:           trimHeaderString(C.GoStringN(&r.header.generating_software[0], 32)),
+		FileCreationDay:              uint16(r.header.file_creation_day),
+		FileCreationYear:             uint16(r.header.file_creation_year),
+		HeaderSize:                   uint16(r.header.header_size),
+		OffsetToPointData:            uint32(r.header.offset_to_point_data),
+		NumberOfVLRs:                 uint32(r.header.number_of_variable_length_records),
+		PointDataFormat:              uint8(r.header.point_data_format),
+		PointDataRecordLength:        uint16(r.header.point_data_record_length),
+		NumberOfPointRecords:         uint32(r.header.number_of_point_records),
+		XScaleFactor:                 float64(r.header.x_scale_factor),
+		YScaleFactor:                 float64(r.header.y_scale_factor),
+		ZScaleFactor:                 float64(r.header.z_scale_factor),
+		XOffset:                      float64(r.header.x_offset),
+		YOffset:                      float64(r.header.y_offset),
+		ZOffset:                      float64(r.header.z_offset),
+		MaxX:                         float64(r.header.max_x),
+		MinX:                         float64(r.header.min_x),
+		MaxY:                         float64(r.header.max_y),
+		MinY:                         float64(r.header.min_y),
+		MaxZ:                         float64(r.header.max_z),
+		MinZ:                         float64(r.header.min_z),
+		WaveformDataStart:            uint64(r.header.start_of_waveform_data_packet_record),
+		StartOfFirstEVLR:             uint64(r.header.start_of_first_extended_variable_length_record),
+		NumberOfEVLRs:                uint32(r.header.number_of_extended_variable_length_records),
+		ExtendedNumberOfPointRecords: uint64(r.header.extended_number_of_point_records),
+	}
+	for i := range h.ProjectID4 {
+		h.ProjectID4[i] = byte(r.header.project_ID_GUID_data_4[i])
+	}
+	for i := range h.NumberOfPointsByReturn {
+		h.NumberOfPointsByReturn[i] = uint32(r.header.number_of_points_by_return[i])
+	}
+	for i := range h.ExtendedNumberOfPointsByReturn {
+		h.ExtendedNumberOfPointsByReturn[i] = uint64(r.header.extended_number_of_points_by_return[i])
+	}
+	return h
+}
+
+// GetVLRs returns the variable length records of the file. LASzip removes its
+// own "laszip encoded" record, so it is not included.
+func (r *LaszipReader) GetVLRs() []VLR {
+	if !r.isOpen || r.header == nil {
 		return nil
 	}
+	n := int(r.header.number_of_variable_length_records)
+	if n == 0 || r.header.vlrs == nil {
+		return []VLR{}
+	}
+	records := unsafe.Slice(r.header.vlrs, n)
+	vlrs := make([]VLR, n)
+	for i := range records {
+		v := &records[i]
+		vlrs[i] = VLR{
+			Reserved:                int(v.reserved),
+			UserID:                  trimHeaderString(C.GoStringN(&v.user_id[0], 16)),
+			RecordID:                int(v.record_id),
+			RecordLengthAfterHeader: int(v.record_length_after_header),
+			Description:             trimHeaderString(C.GoStringN(&v.description[0], 32)),
+			BinaryData:              []uint8{},
+		}
+		if v.data != nil && v.record_length_after_header > 0 {
+			vlrs[i].BinaryData = C.GoBytes(unsafe.Pointer(v.data), C.int(v.record_length_after_header))
+		}
+	}
+	return vlrs
+}
 
-	result := C.laszip_close_reader(r.pointer)
-	if result != 0 {
-		return r.getError()
+// trimHeaderString removes the null and space padding of a fixed-length header string.
+func trimHeaderString(s string) string {
+	return strings.Trim(strings.Trim(s, " "), "\x00")
+}
+
+// Close closes the LAZ reader and destroys the LASzip pointer. The pointer is
+// destroyed even if closing the reader fails, in which case that error is
+// returned. Calling Close again has no effect; any other use of a closed
+// reader returns ErrReaderClosed.
+func (r *LaszipReader) Close() error {
+	if r.pointer == nil {
+		return nil
+	}
+	runtime.SetFinalizer(r, nil)
+	if r.stream != nil {
+		// The stream is read until LASzip is destroyed.
+		defer r.stream.close()
+		r.stream = nil
 	}
 
-	result = C.laszip_destroy(r.pointer)
-	if result != 0 {
-		return r.getError()
+	var err error
+	if r.isOpen {
+		if result := C.laszip_close_reader(r.pointer); result != 0 {
+			err = r.getError(result)
+		}
+	}
+	if C.laszip_destroy(r.pointer) != 0 && err == nil {
+		err = errors.New("failed to destroy LASzip pointer")
 	}
 
+	// The header and point are owned by the destroyed pointer.
+	r.pointer, r.header, r.point = nil, nil, nil
 	r.isOpen = false
-	return nil
+	r.batch = nil
+	return err
+}
+
+// getError retrieves the last error from LASzip, which was reported by a
+// call returning code.
+func (r *LaszipReader) getError(code C.laszip_I32) error {
+	return newLaszipError(r.pointer, code)
+}
+
+// LaszipError is an error reported by LASzip. Code is the non-zero value
+// returned by the failing call and Message the description retrieved with
+// laszip_get_error.
+type LaszipError struct {
+	Code    int
+	Message string
 }
 
-// getError retrieves the last error from LASzip
-func (r *LaszipReader) getError() error {
+func (e *LaszipError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unknown LASzip error (code %v)", e.Code)
+	}
+	return e.Message
+}
+
+// newLaszipError returns the last error of a LASzip pointer.
+func newLaszipError(pointer C.laszip_POINTER, code C.laszip_I32) *LaszipError {
+	e := &LaszipError{Code: int(code)}
 	var cError *C.char
-	C.laszip_get_error(r.pointer, &cError)
+	C.laszip_get_error(pointer, &cError)
 	if cError != nil {
-		return errors.New(C.GoString(cError))
+		e.Message = C.GoString(cError)
 	}
-	return errors.New("unknown LASzip error")
+	return e
 }
 
 // LaszipPoint represents a point read from a LAZ file
@@ -202,38 +607,104 @@ type LaszipPoint struct {
 	X                 float64
 	Y                 float64
 	Z                 float64
+	RawX              int32 // the stored integer; X = RawX * XScaleFactor + XOffset
+	RawY              int32
+	RawZ              int32
 	Intensity         uint16
 	ReturnNumber      uint8
 	NumberOfReturns   uint8
 	ScanDirectionFlag uint8
 	EdgeOfFlightFlag  uint8
 	Classification    uint8
+	Synthetic         bool
+	Keypoint          bool
+	Withheld          bool
 	ScanAngleRank     int8
+	ScanAngle         float64 // in degrees, at a 0.006 degree resolution for point formats 6 and above
+	ScannerChannel    uint8   // the scanner head of multi-channel systems (point formats 6 and above)
 	UserData          uint8
 	PointSourceID     uint16
 	GPSTime           float64
+	Red               uint16
+	Green             uint16
+	Blue              uint16
+	NIR               uint16
+	// WavePacket holds the raw wave packet fields of point formats 4, 5, 9
+	// and 10 (see parseWavePacket).
+	WavePacket [29]byte
+}
+
+// fromC copies a point that was copied out of LASzip in C.
+func (lp *LaszipPoint) fromC(cp *C.lidario_point) {
+	*lp = LaszipPoint{
+		X:                 float64(cp.x),
+		Y:                 float64(cp.y),
+		Z:                 float64(cp.z),
+		RawX:              int32(cp.raw_x),
+		RawY:              int32(cp.raw_y),
+		RawZ:              int32(cp.raw_z),
+		Intensity:         uint16(cp.intensity),
+		ReturnNumber:      uint8(cp.return_number),
+		NumberOfReturns:   uint8(cp.number_of_returns),
+		ScanDirectionFlag: uint8(cp.scan_direction_flag),
+		EdgeOfFlightFlag:  uint8(cp.edge_of_flight_line),
+		Classification:    uint8(cp.classification),
+		Synthetic:         cp.synthetic != 0,
+		Keypoint:          cp.keypoint != 0,
+		Withheld:          cp.withheld != 0,
+		ScanAngleRank:     int8(cp.scan_angle_rank),
+		ScanAngle:         float64(cp.scan_angle),
+		ScannerChannel:    uint8(cp.scanner_channel),
+		UserData:          uint8(cp.user_data),
+		PointSourceID:     uint16(cp.point_source_ID),
+		GPSTime:           float64(cp.gps_time),
+		Red:               uint16(cp.rgb[0]),
+		Green:             uint16(cp.rgb[1]),
+		Blue:              uint16(cp.rgb[2]),
+		NIR:               uint16(cp.rgb[3]),
+	}
+	for i := range lp.WavePacket {
+		lp.WavePacket[i] = byte(cp.wave_packet[i])
+	}
 }
 
 // LaszipHeader represents the header of a LAZ file
 type LaszipHeader struct {
-	VersionMajor          uint8
-	VersionMinor          uint8
-	HeaderSize            uint16
-	OffsetToPointData     uint32
-	NumberOfVLRs          uint32
-	PointDataFormat       uint8
-	PointDataRecordLength uint16
-	NumberOfPointRecords  uint32
-	XScaleFactor          float64
-	YScaleFactor          float64
-	ZScaleFactor          float64
-	XOffset               float64
-	YOffset               float64
-	ZOffset               float64
-	MaxX                  float64
-	MinX                  float64
-	MaxY                  float64
-	MinY                  float64
-	MaxZ                  float64
-	MinZ                  float64
+	FileSourceID           uint16
+	GlobalEncoding         uint16
+	ProjectID1             uint32
+	ProjectID2             uint16
+	ProjectID3             uint16
+	ProjectID4             [8]byte
+	VersionMajor           uint8
+	VersionMinor           uint8
+	SystemID               string
+	GeneratingSoftware     string
+	FileCreationDay        uint16
+	FileCreationYear       uint16
+	HeaderSize             uint16
+	OffsetToPointData      uint32
+	NumberOfVLRs           uint32
+	PointDataFormat        uint8
+	PointDataRecordLength  uint16
+	NumberOfPointRecords   uint32
+	NumberOfPointsByReturn [5]uint32
+	XScaleFactor           float64
+	YScaleFactor           float64
+	ZScaleFactor           float64
+	XOffset                float64
+	YOffset                float64
+	ZOffset                float64
+	MaxX                   float64
+	MinX                   float64
+	MaxY                   float64
+	MinY                   float64
+	MaxZ                   float64
+	MinZ                   float64
+	WaveformDataStart      uint64
+	// LAS 1.4 only
+	StartOfFirstEVLR               uint64
+	NumberOfEVLRs                  uint32
+	ExtendedNumberOfPointRecords   uint64
+	ExtendedNumberOfPointsByReturn [15]uint64
 }
@@ -0,0 +1,210 @@
+package lidario
+
+/*
+#include <laszip/laszip_api.h>
+#include <stdlib.h>
+#include <string.h>
+
+// set_point_fields sets the attributes of the point to be written. cgo cannot
+// access C bit fields, so they are set here.
+static void set_point_fields(laszip_point_struct* p, laszip_U16 intensity, laszip_U8 return_number,
+		laszip_U8 number_of_returns, laszip_U8 scan_direction_flag, laszip_U8 edge_of_flight_line,
+		laszip_U8 classification_byte, laszip_I8 scan_angle_rank, laszip_U8 user_data,
+		laszip_U16 point_source_ID, laszip_F64 gps_time, laszip_U16 red, laszip_U16 green, laszip_U16 blue) {
+	p->intensity = intensity;
+	p->return_number = return_number;
+	p->number_of_returns = number_of_returns;
+	p->scan_direction_flag = scan_direction_flag;
+	p->edge_of_flight_line = edge_of_flight_line;
+	p->classification = classification_byte & 31;
+	p->synthetic_flag = (classification_byte >> 5) & 1;
+	p->keypoint_flag = (classification_byte >> 6) & 1;
+	p->withheld_flag = (classification_byte >> 7) & 1;
+	p->scan_angle_rank = scan_angle_rank;
+	p->user_data = user_data;
+	p->point_source_ID = point_source_ID;
+	p->gps_time = gps_time;
+	p->rgb[0] = red;
+	p->rgb[1] = green;
+	p->rgb[2] = blue;
+}
+
+// set_header_string copies a Go string into a fixed-length, null-padded header field.
+static void set_header_string(laszip_CHAR* dst, const char* src, size_t size) {
+	memset(dst, 0, size);
+	strncpy(dst, src, size);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// LaszipWriter wraps the LASzip C API for writing compressed LAZ files
+type LaszipWriter struct {
+	pointer C.laszip_POINTER
+	header  *C.laszip_header_struct
+	point   *C.laszip_point_struct
+	isOpen  bool
+}
+
+// NewLaszipWriter creates a new LASzip writer
+func NewLaszipWriter() (*LaszipWriter, error) {
+	w := &LaszipWriter{}
+	if C.laszip_create(&w.pointer) != 0 {
+		return nil, errors.New("failed to create LASzip pointer")
+	}
+	return w, nil
+}
+
+// OpenWriter sets up the header from h and opens a compressed file for writing.
+// The scale factors, offsets and point format are taken from h; the point
+// counts and bounds are accumulated as points are written. The VLRs are
+// written after the header.
+func (w *LaszipWriter) OpenWriter(filename string, h *LasHeader, vlrs ...VLR) error {
+	if w.isOpen {
+		return errors.New("writer already open")
+	}
+	if result := C.laszip_get_header_pointer(w.pointer, &w.header); result != 0 {
+		return w.getError(result)
+	}
+
+	w.header.file_source_ID = C.laszip_U16(h.FileSourceID)
+	w.header.global_encoding = C.laszip_U16(h.GlobalEncoding.Value)
+	w.header.project_ID_GUID_data_1 = C.laszip_U32(h.ProjectID1)
+	w.header.project_ID_GUID_data_2 = C.laszip_U16(h.ProjectID2)
+	w.header.project_ID_GUID_data_3 = C.laszip_U16(h.ProjectID3)
+	for i, b := range h.ProjectID4 {
+		w.header.project_ID_GUID_data_4[i] = C.laszip_CHAR(b)
+	}
+	w.header.version_major = C.laszip_U8(h.VersionMajor)
+	w.header.version_minor = C.laszip_U8(h.VersionMinor)
+	w.setString(&w.header.system_identifier[0], h.SystemID, 32)
+	w.setString(&w.header.generating_software[0], h.GeneratingSoftware, 32)
+	w.header.file_creation_day = C.laszip_U16(h.FileCreationDay)
+	w.header.file_creation_year = C.laszip_U16(h.FileCreationYear)
+	w.header.point_data_format = C.laszip_U8(h.PointFormatID)
+	w.header.point_data_record_length = C.laszip_U16(h.PointRecordLength)
+	w.header.x_scale_factor = C.laszip_F64(h.XScaleFactor)
+	w.header.y_scale_factor = C.laszip_F64(h.YScaleFactor)
+	w.header.z_scale_factor = C.laszip_F64(h.ZScaleFactor)
+	w.header.x_offset = C.laszip_F64(h.XOffset)
+	w.header.y_offset = C.laszip_F64(h.YOffset)
+	w.header.z_offset = C.laszip_F64(h.ZOffset)
+
+	for _, vlr := range vlrs {
+		if err := w.addVLR(vlr); err != nil {
+			return err
+		}
+	}
+
+	if result := C.laszip_get_point_pointer(w.pointer, &w.point); result != 0 {
+		return w.getError(result)
+	}
+
+	cFilename := C.CString(nativePath(filename))
+	defer C.free(unsafe.Pointer(cFilename))
+	if result := C.laszip_open_writer(w.pointer, cFilename, 1); result != 0 {
+		return w.getError(result)
+	}
+	w.isOpen = true
+	return nil
+}
+
+// addVLR adds a VLR to the header; LASzip copies the payload.
+func (w *LaszipWriter) addVLR(vlr VLR) error {
+	if err := checkVLRSize(vlr); err != nil {
+		return err
+	}
+	userID := C.CString(vlr.UserID)
+	defer C.free(unsafe.Pointer(userID))
+	description := C.CString(vlr.Description)
+	defer C.free(unsafe.Pointer(description))
+	var data *C.laszip_U8
+	if len(vlr.BinaryData) > 0 {
+		data = (*C.laszip_U8)(C.CBytes(vlr.BinaryData))
+		defer C.free(unsafe.Pointer(data))
+	}
+	if result := C.laszip_add_vlr(w.pointer, userID, C.laszip_U16(vlr.RecordID), C.laszip_U16(len(vlr.BinaryData)), description, data); result != 0 {
+		return w.getError(result)
+	}
+	return nil
+}
+
+func (w *LaszipWriter) setString(dst *C.laszip_CHAR, s string, size int) {
+	cs := C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+	C.set_header_string(dst, cs, C.size_t(size))
+}
+
+// WritePoint compresses and writes a point. The point counts and bounds of
+// the header are updated so that they are correct when the writer is closed.
+func (w *LaszipWriter) WritePoint(lp *LaszipPoint) error {
+	if !w.isOpen {
+		return errors.New("writer not open")
+	}
+	coordinates := [3]C.laszip_F64{C.laszip_F64(lp.X), C.laszip_F64(lp.Y), C.laszip_F64(lp.Z)}
+	if result := C.laszip_set_coordinates(w.pointer, &coordinates[0]); result != 0 {
+		return w.getError(result)
+	}
+
+	classificationByte := lp.Classification & 0x1F
+	if lp.Synthetic {
+		classificationByte |= 0x20
+	}
+	if lp.Keypoint {
+		classificationByte |= 0x40
+	}
+	if lp.Withheld {
+		classificationByte |= 0x80
+	}
+	C.set_point_fields(w.point, C.laszip_U16(lp.Intensity), C.laszip_U8(lp.ReturnNumber),
+		C.laszip_U8(lp.NumberOfReturns), C.laszip_U8(lp.ScanDirectionFlag), C.laszip_U8(lp.EdgeOfFlightFlag),
+		C.laszip_U8(classificationByte), C.laszip_I8(lp.ScanAngleRank), C.laszip_U8(lp.UserData),
+		C.laszip_U16(lp.PointSourceID), C.laszip_F64(lp.GPSTime),
+		C.laszip_U16(lp.Red), C.laszip_U16(lp.Green), C.laszip_U16(lp.Blue))
+
+	if result := C.laszip_write_point(w.pointer); result != 0 {
+		return w.getError(result)
+	}
+	if result := C.laszip_update_inventory(w.pointer); result != 0 {
+		return w.getError(result)
+	}
+	return nil
+}
+
+// Close finalizes the header and closes the LAZ writer
+func (w *LaszipWriter) Close() error {
+	if w.pointer == nil {
+		return nil
+	}
+	var err error
+	if w.isOpen {
+		if result := C.laszip_close_writer(w.pointer); result != 0 {
+			err = w.getError(result)
+		}
+	}
+	w.isOpen = false
+	C.laszip_destroy(w.pointer)
+	w.pointer = nil
+	return err
+}
+
+// getError retrieves the last error from LASzip, which was reported by a
+// call returning code.
+func (w *LaszipWriter) getError(code C.laszip_I32) error {
+	return newLaszipError(w.pointer, code)
+}
+
+// LaszipVersion returns the version of the LASzip library.
+func LaszipVersion() (major, minor uint8, revision uint16, err error) {
+	var cMajor, cMinor C.laszip_U8
+	var cRevision C.laszip_U16
+	var cBuild C.laszip_U32
+	if C.laszip_get_version(&cMajor, &cMinor, &cRevision, &cBuild) != 0 {
+		return 0, 0, 0, errors.New("failed to get the LASzip version")
+	}
+	return uint8(cMajor), uint8(cMinor), uint16(cRevision), nil
+}
@@ -0,0 +1,33 @@
+package lidario
+
+// FileLayout describes how the start of a file is laid out: the public
+// header, followed by the VLRs, followed by the point records.
+type FileLayout struct {
+	HeaderSize int
+	// VLRBytes is the length of the VLRs, headers and payloads
+	VLRBytes       int
+	OffsetToPoints int
+	// Padding is the number of bytes between the end of the VLRs and the
+	// point records, which readers that assume the points follow the VLRs
+	// misread. It is negative if the VLRs overrun the offset to the points.
+	Padding int
+}
+
+// fileLayout computes the layout of a file from its header and VLRs.
+func fileLayout(h *LasHeader, vlrs []VLR) FileLayout {
+	l := FileLayout{HeaderSize: h.HeaderSize, OffsetToPoints: h.OffsetToPoints}
+	for _, vlr := range vlrs {
+		// Each VLR has a 54-byte header.
+		l.VLRBytes += 54 + vlr.RecordLengthAfterHeader
+	}
+	l.Padding = l.OffsetToPoints - l.HeaderSize - l.VLRBytes
+	return l
+}
+
+// LayoutInfo returns the layout of the start of the file, so that files with
+// bytes between the VLRs and the point records can be detected. As in the
+// header and VLRs of the file, the LASzip VLR is excluded: the layout is that
+// of the file once decompressed.
+func (lf *LazFile) LayoutInfo() FileLayout {
+	return fileLayout(&lf.Header, lf.VlrData)
+}
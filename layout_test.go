@@ -0,0 +1,74 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+)
+
+// vlrSize returns the length of the VLRs as read: a 54-byte header followed by
+// the payload of each record.
+func vlrSize(vlrs []VLR) int {
+	n := 0
+	for _, vlr := range vlrs {
+		n += 54 + len(vlr.BinaryData)
+	}
+	return n
+}
+
+func TestFileLayout(t *testing.T) {
+	las, err := NewLasFile("testdata/sample.las", "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+	l := fileLayout(&las.Header, las.VlrData)
+	if l.HeaderSize != 227 || l.VLRBytes == 0 || l.VLRBytes != vlrSize(las.VlrData) {
+		t.Errorf("the layout %+v does not match the %v bytes of the VLRs", l, vlrSize(las.VlrData))
+	}
+	if l.Padding != l.OffsetToPoints-227-vlrSize(las.VlrData) {
+		t.Errorf("the layout %+v does not leave the bytes before the points as padding", l)
+	}
+
+	// The LASzip VLR is excluded from the layout of a LAZ file.
+	fileName := writeHeaderOnlyLaz(t)
+	lf, err := NewLazFile(fileName, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lf.LayoutInfo(); got != l {
+		t.Errorf("LAZ layout %+v, expected %+v", got, l)
+	}
+	lf.Close()
+
+	// Bytes between the VLRs and the points are reported as padding.
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.LittleEndian.PutUint32(data[96:100], binary.LittleEndian.Uint32(data[96:100])+10)
+	if err = os.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	lf, err = NewLazFile(fileName, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if got := lf.LayoutInfo(); got.Padding != l.Padding+10 || got.OffsetToPoints != l.OffsetToPoints+10 {
+		t.Errorf("expected 10 more bytes of padding, got %+v", got)
+	}
+}
+
+func TestLazLayoutInfo(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	l := lf.LayoutInfo()
+	if l.VLRBytes != vlrSize(lf.VlrData) || l.Padding < 0 {
+		t.Errorf("the layout %+v does not match the %v bytes of the VLRs", l, vlrSize(lf.VlrData))
+	}
+}
@@ -1,9 +1,32 @@
 package lidario
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
+// sampleLazFile is the LAZ file used by the LAZ tests. It is not distributed
+// with the repository; tests that need it are skipped when it is missing.
+const sampleLazFile = "../PNOA_2020_AND_288-4006_ORT-CLA-IRC.laz"
+
+// requireSampleLaz skips the test if the sample LAZ file is not available.
+func requireSampleLaz(t *testing.T) {
+	t.Helper()
+	if _, err := os.Stat(sampleLazFile); err != nil {
+		t.Skipf("sample LAZ file not available: %v", err)
+	}
+}
+
 func TestLazFileReading(t *testing.T) {
 	// Test with the LAZ file in the parent directory
 	fileName := "../PNOA_2020_AND_288-4006_ORT-CLA-IRC.laz"
@@ -67,7 +90,7 @@ func TestFileTypeDetection(t *testing.T) {
 		expected string
 	}{
 		{"../PNOA_2020_AND_288-4006_ORT-CLA-IRC.laz", "LAZ"},
-		{"../PNOA_2020_AND_288-4006_ORT-CLA-IRC.copc.laz", "LAZ"},
+		{"../PNOA_2020_AND_288-4006_ORT-CLA-IRC.copc.laz", "COPC"},
 		{"testdata/sample.las", "LAS"},
 		{"nonexistent.txt", "UNKNOWN"},
 	}
@@ -78,4 +101,1249 @@ func TestFileTypeDetection(t *testing.T) {
 			t.Errorf("GetFileType(%s) = %s, expected %s", test.filename, result, test.expected)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestLazStreamingZeroPointCount(t *testing.T) {
+	requireSampleLaz(t)
+	data, err := os.ReadFile(sampleLazFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := header.Header.NumberPoints
+	header.Close()
+
+	// Zero the legacy and (for LAS 1.4) the extended point counts, as a
+	// streaming writer does.
+	binary.LittleEndian.PutUint32(data[107:111], 0)
+	if data[25] >= 4 {
+		binary.LittleEndian.PutUint64(data[247:255], 0)
+	}
+	fileName := filepath.Join(t.TempDir(), "streaming.laz")
+	if err = os.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err := NewLazFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if lf.GetPointCount() != 0 {
+		t.Fatalf("expected the header to declare zero points, got %v", lf.GetPointCount())
+	}
+
+	count := 0
+	for {
+		if _, err := lf.LasPoint(count); err != nil {
+			break
+		}
+		count++
+	}
+	if count != expected {
+		t.Errorf("expected to read %v points, read %v", expected, count)
+	}
+}
+
+func TestLazRawHeaderBytes(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	b, err := lf.RawHeaderBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != lf.Header.HeaderSize {
+		t.Errorf("expected %v header bytes, got %v", lf.Header.HeaderSize, len(b))
+	}
+	if string(b[0:4]) != "LASF" {
+		t.Errorf("expected the header to start with LASF, got %q", b[0:4])
+	}
+}
+
+func TestCompressionDetection(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile("testdata/sample.las")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := data[:1024]
+
+	// An uncompressed file with a .laz extension.
+	lasNamedLaz := filepath.Join(dir, "uncompressed.laz")
+	if err = os.WriteFile(lasNamedLaz, header, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if isCompressedFile(lasNamedLaz) {
+		t.Error("an uncompressed file should not be detected as compressed")
+	}
+
+	// A file flagged as compressed with a .las extension.
+	compressed := append([]byte{}, header...)
+	compressed[104] |= 0x80
+	lazNamedLas := filepath.Join(dir, "compressed.las")
+	if err = os.WriteFile(lazNamedLas, compressed, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !isCompressedFile(lazNamedLas) {
+		t.Error("a file with the compression bit set should be detected as compressed")
+	}
+}
+
+func TestNewLidarFileCompressedWithLasExtension(t *testing.T) {
+	requireSampleLaz(t)
+	data, err := os.ReadFile(sampleLazFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(t.TempDir(), "mislabeled.las")
+	if err = os.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	lidarFile, err := NewLidarFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lidarFile.Close()
+	if !lidarFile.IsCompressed() {
+		t.Error("a compressed file named .las should be opened as a LAZ file")
+	}
+}
+
+func TestLazRandomAccess(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if lf.Header.NumberPoints <= 100 {
+		t.Skip("the sample file has too few points")
+	}
+
+	// Read forwards, backwards and then forwards again.
+	var coords [3][3]float64
+	for i, index := range []int{100, 5, 100} {
+		x, y, z, err := lf.GetXYZ(index)
+		if err != nil {
+			t.Fatalf("reading point %v: %v", index, err)
+		}
+		coords[i] = [3]float64{x, y, z}
+	}
+	if coords[0] != coords[2] {
+		t.Errorf("point 100 changed between reads: %v and %v", coords[0], coords[2])
+	}
+	if coords[0] == coords[1] {
+		t.Errorf("points 5 and 100 have identical coordinates %v", coords[0])
+	}
+
+	// Reading the next point in sequence needs no seek.
+	if _, err = lf.LasPoint(101); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = lf.LasPoint(lf.Header.NumberPoints); err == nil {
+		t.Error("expected an error for an out of range point")
+	}
+}
+
+func TestConvertPointRGB(t *testing.T) {
+	lp := &LaszipPoint{X: 1, Y: 2, Z: 3, Red: 100, Green: 200, Blue: 300, GPSTime: 4}
+	lf := &LazFile{Header: LasHeader{PointFormatID: 3}}
+	p := lf.convertPoint(lp)
+	rgb := p.RgbData()
+	if rgb == nil || rgb.Red != 100 || rgb.Green != 200 || rgb.Blue != 300 {
+		t.Errorf("expected RGB (100, 200, 300), got %+v", rgb)
+	}
+	lf.Header.PointFormatID = 2
+	if rgb = lf.convertPoint(lp).RgbData(); rgb == nil || rgb.Blue != 300 {
+		t.Errorf("expected format 2 points to carry RGB data, got %+v", rgb)
+	}
+}
+
+func TestLazRGB(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if lf.Header.PointFormatID != 2 && lf.Header.PointFormatID != 3 {
+		t.Skipf("the sample file uses point format %v, which has no RGB data", lf.Header.PointFormatID)
+	}
+	n := 1000
+	if lf.Header.NumberPoints < n {
+		n = lf.Header.NumberPoints
+	}
+	for i := 0; i < n; i++ {
+		p, err := lf.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rgb := p.RgbData(); rgb.Red != 0 || rgb.Green != 0 || rgb.Blue != 0 {
+			return
+		}
+	}
+	t.Errorf("all of the first %v points are black", n)
+}
+
+func TestConvertPointReturns(t *testing.T) {
+	lp := &LaszipPoint{ReturnNumber: 2, NumberOfReturns: 3, ScanDirectionFlag: 1, EdgeOfFlightFlag: 1}
+	lf := &LazFile{Header: LasHeader{PointFormatID: 1}}
+	p := lf.convertPoint(lp).PointData()
+	if p.BitField.ReturnNumber() != 2 || p.BitField.NumberOfReturns() != 3 {
+		t.Errorf("expected return 2 of 3, got %v of %v", p.BitField.ReturnNumber(), p.BitField.NumberOfReturns())
+	}
+	if !p.BitField.ScanDirectionFlag() || !p.BitField.EdgeOfFlightlineFlag() {
+		t.Error("expected the scan direction and edge of flight line flags to be set")
+	}
+	if !lf.convertPoint(lp).IsIntermediateReturn() {
+		t.Error("expected return 2 of 3 to be an intermediate return")
+	}
+}
+
+func TestLazMultipleReturns(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	n := 100000
+	if lf.Header.NumberPoints < n {
+		n = lf.Header.NumberPoints
+	}
+	for i := 0; i < n; i++ {
+		p, err := lf.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.PointData().BitField.NumberOfReturns() > 1 {
+			return
+		}
+	}
+	t.Errorf("none of the first %v points has more than one return", n)
+}
+
+func TestConvertPointClassification(t *testing.T) {
+	lf := &LazFile{Header: LasHeader{PointFormatID: 0}}
+	p := lf.convertPoint(&LaszipPoint{Classification: 2, Withheld: true}).PointData()
+	if p.ClassBitField.Classification() != 2 {
+		t.Errorf("expected class 2, got %v", p.ClassBitField.Classification())
+	}
+	if !p.ClassBitField.withheld() || p.ClassBitField.Synthetic() || p.ClassBitField.Keypoint() {
+		t.Errorf("expected only the withheld flag to be set, got %08b", p.ClassBitField.Value)
+	}
+}
+
+func TestLazClassification(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	n := 100000
+	if lf.Header.NumberPoints < n {
+		n = lf.Header.NumberPoints
+	}
+	var ground, vegetation int
+	for i := 0; i < n; i++ {
+		p, err := lf.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch p.PointData().ClassBitField.Classification() {
+		case 2:
+			ground++
+		case 3, 4, 5:
+			vegetation++
+		}
+	}
+	if ground == 0 || vegetation == 0 {
+		t.Errorf("expected both ground and vegetation points, got %v ground and %v vegetation", ground, vegetation)
+	}
+}
+
+func TestLazScanAngle(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	buf := make([]LaszipPoint, 10000)
+	n, err := lf.reader.ReadPointsInto(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range buf[:n] {
+		if p.ScanAngle < -90 || p.ScanAngle > 90 {
+			t.Fatalf("scan angle %v lies outside of [-90, 90] degrees", p.ScanAngle)
+		}
+		if math.Abs(p.ScanAngle-float64(p.ScanAngleRank)) > 0.5 {
+			t.Fatalf("scan angle %v does not round to the rank %v", p.ScanAngle, p.ScanAngleRank)
+		}
+		if lf.Header.PointFormatID < 6 && p.ScannerChannel != 0 {
+			t.Fatalf("scanner channel %v for point format %v", p.ScannerChannel, lf.Header.PointFormatID)
+		}
+	}
+}
+
+func TestLazPointFormats(t *testing.T) {
+	lp := &LaszipPoint{X: 1, Red: 100, Green: 200, Blue: 300, GPSTime: 12.5}
+	for _, format := range []uint8{4, 5, 6, 7, 9} {
+		lf := &LazFile{Header: LasHeader{PointFormatID: format}}
+		p := lf.convertPoint(lp)
+		if p.Format() != format || p.PointData().X != 1 {
+			t.Errorf("format %v: unexpected point %T %v", format, p, p)
+		}
+		if p.GpsTimeData() != 12.5 {
+			t.Errorf("format %v: GPS time %v, expected 12.5", format, p.GpsTimeData())
+		}
+		rgb := RgbData{}
+		if hasRGB(format) {
+			rgb = RgbData{100, 200, 300}
+		}
+		if *p.RgbData() != rgb {
+			t.Errorf("format %v: colour %v, expected %v", format, *p.RgbData(), rgb)
+		}
+	}
+}
+
+func TestLazExtendedPointData(t *testing.T) {
+	for _, format := range []uint8{6, 7, 8, 9, 10} {
+		lf := &LazFile{Header: LasHeader{PointFormatID: format}}
+		p := lf.convertPoint(&LaszipPoint{ReturnNumber: 9, NumberOfReturns: 10, Classification: 40, ScannerChannel: 2, ScanAngle: 12.006})
+		var e ExtendedPointData
+		switch p := p.(type) {
+		case *PointRecord6:
+			e = p.Extended
+		case *PointRecord7:
+			e = p.Extended
+		case *PointRecord8:
+			e = p.Extended
+		case *PointRecord9:
+			e = p.Extended
+		case *PointRecord10:
+			e = p.Extended
+		default:
+			t.Fatalf("format %v: unexpected point type %T", format, p)
+		}
+		if e != (ExtendedPointData{ReturnNumber: 9, NumberOfReturns: 10, Classification: 40, ScannerChannel: 2, ScanAngle: 12.006}) {
+			t.Errorf("format %v: extended fields %+v", format, e)
+		}
+		// The legacy fields cannot hold class 40, nor return 9 of 10.
+		pd := p.PointData()
+		if pd.ClassBitField.Classification() != 0 || pd.BitField.ReturnNumber() != 6 || pd.BitField.NumberOfReturns() != 7 {
+			t.Errorf("format %v: legacy class %v and return %v of %v", format, pd.ClassBitField.Classification(),
+				pd.BitField.ReturnNumber(), pd.BitField.NumberOfReturns())
+		}
+		if p.IsLateReturn() || p.IsFirstReturn() || !p.IsIntermediateReturn() {
+			t.Errorf("format %v: return 9 of 10 is not an intermediate return", format)
+		}
+	}
+
+	// The classes of the legacy formats are unchanged.
+	lf := &LazFile{Header: LasHeader{PointFormatID: 1}}
+	if c := lf.convertPoint(&LaszipPoint{Classification: 9}).PointData().ClassBitField.Classification(); c != 9 {
+		t.Errorf("format 1: class %v, expected 9", c)
+	}
+}
+
+func TestLegacyReturns(t *testing.T) {
+	for _, c := range []struct{ rn, nr, legacyRN, legacyNR uint8 }{
+		{1, 1, 1, 1}, {3, 7, 3, 7}, {4, 15, 4, 7}, {12, 15, 4, 7},
+		{13, 15, 5, 7}, {14, 15, 6, 7}, {15, 15, 7, 7}, {9, 5, 7, 5},
+	} {
+		if rn, nr := legacyReturns(c.rn, c.nr); rn != c.legacyRN || nr != c.legacyNR {
+			t.Errorf("return %v of %v: legacy %v of %v, expected %v of %v", c.rn, c.nr, rn, nr, c.legacyRN, c.legacyNR)
+		}
+	}
+}
+
+func TestLazNIR(t *testing.T) {
+	lp := &LaszipPoint{X: 1, Red: 100, Green: 200, Blue: 300, NIR: 40000, GPSTime: 12.5}
+	for _, format := range []uint8{8, 10} {
+		lf := &LazFile{Header: LasHeader{PointFormatID: format}}
+		p := lf.convertPoint(lp)
+		if p.Format() != format || p.GpsTimeData() != 12.5 || *p.RgbData() != (RgbData{100, 200, 300}) {
+			t.Errorf("format %v: unexpected point %v", format, p)
+		}
+		var nir uint16
+		switch p := p.(type) {
+		case *PointRecord8:
+			nir = p.NIR
+		case *PointRecord10:
+			nir = p.NIR
+		}
+		if nir != 40000 {
+			t.Errorf("format %v: NIR %v, expected 40000", format, nir)
+		}
+	}
+
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if !hasNIR(lf.Header.PointFormatID) {
+		t.Skipf("the sample file has point format %v, which has no NIR channel", lf.Header.PointFormatID)
+	}
+	points, err := lf.ReadPoints(0, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var maxNIR uint16
+	for _, p := range points {
+		var nir uint16
+		switch p := p.(type) {
+		case *PointRecord8:
+			nir = p.NIR
+		case *PointRecord10:
+			nir = p.NIR
+		default:
+			t.Fatalf("unexpected point type %T", p)
+		}
+		if nir > maxNIR {
+			maxNIR = nir
+		}
+	}
+	if maxNIR == 0 {
+		t.Error("expected non-zero NIR values")
+	}
+}
+
+func TestLazReadPoints(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	start, count := 50, 10000
+	if lf.Header.NumberPoints < start+count {
+		t.Skip("the sample file has too few points")
+	}
+
+	points, err := lf.ReadPoints(start, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != count {
+		t.Fatalf("expected %v points, got %v", count, len(points))
+	}
+	for _, i := range []int{0, 1, 4095, 4096, count - 1} {
+		p, err := lf.LasPoint(start + i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *p.PointData() != *points[i].PointData() {
+			t.Errorf("point %v differs between ReadPoints and LasPoint", start+i)
+		}
+	}
+
+	// Reading past the end returns the remaining points.
+	points, err = lf.ReadPoints(lf.Header.NumberPoints-10, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 10 {
+		t.Errorf("expected the last 10 points, got %v", len(points))
+	}
+}
+
+// BenchmarkLazPointReading compares reading one million points one at a time
+// with LasPoint and in batches with ReadPoints.
+func BenchmarkLazPointReading(b *testing.B) {
+	if _, err := os.Stat(sampleLazFile); err != nil {
+		b.Skipf("sample LAZ file not available: %v", err)
+	}
+	count := 1000000
+	b.Run("single", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			lf, err := NewLazFile(sampleLazFile, "r")
+			if err != nil {
+				b.Fatal(err)
+			}
+			total := count
+			if lf.Header.NumberPoints < total {
+				total = lf.Header.NumberPoints
+			}
+			for i := 0; i < total; i++ {
+				if _, err := lf.LasPoint(i); err != nil {
+					b.Fatal(err)
+				}
+			}
+			lf.Close()
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			lf, err := NewLazFile(sampleLazFile, "r")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := lf.ReadPoints(0, count); err != nil {
+				b.Fatal(err)
+			}
+			lf.Close()
+		}
+	})
+	b.Run("columnar", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			lf, err := NewLazFile(sampleLazFile, "r")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := lf.ReadColumnar(0, count); err != nil {
+				b.Fatal(err)
+			}
+			lf.Close()
+		}
+	})
+}
+
+func TestLazFastMode(t *testing.T) {
+	// The bounds check is skipped, leaving the reader to refuse the read.
+	lf := &LazFile{fileMode: "r", reader: &LaszipReader{}, Header: LasHeader{NumberPoints: 10}}
+	if _, err := lf.LasPoint(10); !errors.Is(err, ErrPointOutOfRange) {
+		t.Errorf("expected ErrPointOutOfRange, got %v", err)
+	}
+	lf.SetFastMode(true)
+	for _, i := range []int{-1, 10, 1 << 40} {
+		if _, err := lf.LasPoint(i); err == nil || errors.Is(err, ErrPointOutOfRange) {
+			t.Errorf("point %v: expected the error of the closed reader, got %v", i, err)
+		}
+	}
+
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	safe, err := lf.LasPoint(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf.SetFastMode(true)
+	fast, err := lf.LasPoint(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *fast.PointData() != *safe.PointData() {
+		t.Error("fast mode returned a different point")
+	}
+	for _, i := range []int{-1, lf.Header.NumberPoints, lf.Header.NumberPoints + 1000} {
+		if _, err = lf.LasPoint(i); err == nil {
+			t.Errorf("point %v: expected an error from the reader", i)
+		}
+	}
+}
+
+// BenchmarkLazFastMode compares reading one million points one at a time with
+// LasPoint in safe and fast mode.
+func BenchmarkLazFastMode(b *testing.B) {
+	if _, err := os.Stat(sampleLazFile); err != nil {
+		b.Skipf("sample LAZ file not available: %v", err)
+	}
+	for _, fast := range []bool{false, true} {
+		b.Run(fmt.Sprintf("fast=%v", fast), func(b *testing.B) {
+			lf, err := NewLazFile(sampleLazFile, "r")
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer lf.Close()
+			lf.SetFastMode(fast)
+			total := 1000000
+			if lf.Header.NumberPoints < total {
+				total = lf.Header.NumberPoints
+			}
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if err = lf.Rewind(); err != nil {
+					b.Fatal(err)
+				}
+				for i := 0; i < total; i++ {
+					if _, err := lf.LasPoint(i); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLazHeaderFields(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	// The public header block is not compressed, so the LAS reader decodes it
+	// independently of LASzip.
+	raw, err := NewLasFile(sampleLazFile, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+
+	got, want := lf.Header, raw.Header
+	if got.FileSourceID != want.FileSourceID || got.GlobalEncoding != want.GlobalEncoding {
+		t.Errorf("file source ID and global encoding (%v, %v), expected (%v, %v)",
+			got.FileSourceID, got.GlobalEncoding, want.FileSourceID, want.GlobalEncoding)
+	}
+	if got.ProjectID1 != want.ProjectID1 || got.ProjectID4 != want.ProjectID4 {
+		t.Error("the project ID does not match")
+	}
+	if got.SystemID != want.SystemID || got.GeneratingSoftware != want.GeneratingSoftware {
+		t.Errorf("system ID %q and generating software %q, expected %q and %q",
+			got.SystemID, got.GeneratingSoftware, want.SystemID, want.GeneratingSoftware)
+	}
+	if strings.ContainsRune(got.SystemID, 0) || strings.ContainsRune(got.GeneratingSoftware, 0) {
+		t.Error("the header strings were not trimmed of null bytes")
+	}
+	if got.FileCreationDay != want.FileCreationDay || got.FileCreationYear != want.FileCreationYear {
+		t.Errorf("creation date %v/%v, expected %v/%v", got.FileCreationDay, got.FileCreationYear,
+			want.FileCreationDay, want.FileCreationYear)
+	}
+	if got.NumberPointsByReturn != want.NumberPointsByReturn {
+		t.Errorf("points by return %v, expected %v", got.NumberPointsByReturn, want.NumberPointsByReturn)
+	}
+	total := 0
+	for _, n := range got.NumberPointsByReturn {
+		total += n
+	}
+	if total != got.NumberPoints {
+		t.Errorf("the points by return sum to %v, expected %v", total, got.NumberPoints)
+	}
+}
+
+func TestLazVLRs(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	vlrs := lf.GetVLRs()
+	if len(vlrs) != lf.Header.NumberOfVLRs {
+		t.Fatalf("expected %v VLRs, got %v", lf.Header.NumberOfVLRs, len(vlrs))
+	}
+	for _, vlr := range vlrs {
+		if len(vlr.BinaryData) != vlr.RecordLengthAfterHeader {
+			t.Errorf("VLR %v/%v has %v bytes of data, expected %v", vlr.UserID, vlr.RecordID,
+				len(vlr.BinaryData), vlr.RecordLengthAfterHeader)
+		}
+		if strings.ContainsRune(vlr.UserID, 0) {
+			t.Errorf("the user ID %q was not trimmed", vlr.UserID)
+		}
+	}
+}
+
+func TestFileTypeDetectionByContent(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile("testdata/sample.las")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := data[:1024]
+	compressed := append([]byte{}, header...)
+	compressed[104] |= 0x80
+
+	files := []struct {
+		name     string
+		content  []byte
+		expected string
+	}{
+		{"not_a_point_cloud.las", []byte("this is not a LAS file"), "UNKNOWN"},
+		{"not_a_point_cloud.laz", []byte("LAS"), "UNKNOWN"},
+		{"tile", compressed, "LAZ"},
+		{"tile.bin", header, "LAS"},
+		{"mislabeled.las", compressed, "LAZ"},
+		{"mislabeled.laz", header, "LAS"},
+	}
+	for _, f := range files {
+		fileName := filepath.Join(dir, f.name)
+		if err = os.WriteFile(fileName, f.content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if fileType := GetFileType(fileName); fileType != f.expected {
+			t.Errorf("GetFileType(%s) = %s, expected %s", f.name, fileType, f.expected)
+		}
+	}
+}
+
+func TestLazErrors(t *testing.T) {
+	closed := &LaszipReader{}
+	if err := closed.ReadPoint(); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("ReadPoint on a closed reader: expected ErrReaderClosed, got %v", err)
+	}
+	if err := closed.SeekPoint(1); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("SeekPoint on a closed reader: expected ErrReaderClosed, got %v", err)
+	}
+
+	exhausted := &LaszipReader{isOpen: true, pointCount: 10, currentPoint: 10}
+	if err := exhausted.ReadPoint(); err != io.EOF {
+		t.Errorf("ReadPoint past the last point: expected io.EOF, got %v", err)
+	}
+	if err := exhausted.SeekPoint(11); !errors.Is(err, ErrPointOutOfRange) {
+		t.Errorf("SeekPoint past the last point: expected ErrPointOutOfRange, got %v", err)
+	}
+
+	// LASzip refuses to seek on a reader that has no open file.
+	unseekable := &LaszipReader{isOpen: true, pointCount: 10}
+	if err := unseekable.SeekPoint(5); !errors.Is(err, ErrRandomAccessUnsupported) {
+		t.Errorf("failed seek: expected ErrRandomAccessUnsupported, got %v", err)
+	}
+
+	lf := &LazFile{Header: LasHeader{NumberPoints: 10}, reader: closed}
+	if _, err := lf.LasPoint(10); !errors.Is(err, ErrPointOutOfRange) {
+		t.Errorf("LasPoint: expected ErrPointOutOfRange, got %v", err)
+	}
+	if _, err := lf.ReadPoints(-1, 1); !errors.Is(err, ErrPointOutOfRange) {
+		t.Errorf("ReadPoints: expected ErrPointOutOfRange, got %v", err)
+	}
+	if _, err := lf.LasPoint(5); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("LasPoint on a closed file: expected ErrReaderClosed, got %v", err)
+	}
+}
+
+// writeHeaderOnlyLaz writes a file with the header and VLRs of
+// testdata/sample.las, marked as compressed and led by a LASzip VLR. The
+// point data are not compressed, so only the header can be read.
+func writeHeaderOnlyLaz(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile("testdata/sample.las")
+	if err != nil {
+		t.Fatal(err)
+	}
+	headerSize := binary.LittleEndian.Uint16(data[94:96])
+	offset := binary.LittleEndian.Uint32(data[96:100])
+	b := append([]byte{}, data[:headerSize]...)
+	laszipVLR := encodeVLR(VLR{UserID: laszipVLRUserID, RecordID: laszipVLRRecordID, BinaryData: make([]byte, 34)})
+	b = append(b, laszipVLR...)
+	b = append(b, data[headerSize:offset+1000]...)
+	binary.LittleEndian.PutUint32(b[96:100], offset+uint32(len(laszipVLR)))
+	binary.LittleEndian.PutUint32(b[100:104], binary.LittleEndian.Uint32(b[100:104])+1)
+	b[104] |= 0x80
+	fileName := filepath.Join(t.TempDir(), "header_only.laz")
+	if err = os.WriteFile(fileName, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return fileName
+}
+
+func TestLazHeaderOnly(t *testing.T) {
+	las, err := NewLasFile("testdata/sample.las", "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+
+	lf, err := NewLazFile(writeHeaderOnlyLaz(t), "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if lf.reader != nil {
+		t.Error("a header-only open should not create a LASzip reader")
+	}
+	h := lf.GetHeader()
+	if h.PointFormatID != las.Header.PointFormatID || h.NumberOfVLRs != las.Header.NumberOfVLRs ||
+		h.OffsetToPoints != las.Header.OffsetToPoints || lf.GetPointCount() != las.GetPointCount() {
+		t.Errorf("unexpected header %+v", h)
+	}
+	if len(lf.GetVLRs()) != len(las.VlrData) {
+		t.Errorf("%v VLRs, expected %v without the LASzip VLR", len(lf.GetVLRs()), len(las.VlrData))
+	}
+	if crs, err := lf.GetCRS(); err != nil || crs != "EPSG:26918" {
+		t.Errorf("CRS %q (%v), expected EPSG:26918", crs, err)
+	}
+	if _, err = lf.LasPoint(0); err != errHeaderOnly {
+		t.Errorf("LasPoint: expected the header-only error, got %v", err)
+	}
+	if _, err = lf.ReadPoints(0, 10); err != errHeaderOnly {
+		t.Errorf("ReadPoints: expected the header-only error, got %v", err)
+	}
+	if err = lf.Rewind(); err != errHeaderOnly {
+		t.Errorf("Rewind: expected the header-only error, got %v", err)
+	}
+}
+
+func TestLazPointFormatMasked(t *testing.T) {
+	requireSampleLaz(t)
+	for _, mode := range []string{"r", "rh"} {
+		lf, err := NewLazFile(sampleLazFile, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format := lf.GetHeader().PointFormatID; format > 10 {
+			t.Errorf("mode %q: point format %#x, expected the compression bits to be cleared", mode, format)
+		}
+		lf.Close()
+	}
+}
+
+func BenchmarkLazHeaderOnly(b *testing.B) {
+	if _, err := os.Stat(sampleLazFile); err != nil {
+		b.Skipf("sample LAZ file not available: %v", err)
+	}
+	for _, mode := range []string{"r", "rh"} {
+		b.Run(mode, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				lf, err := NewLazFile(sampleLazFile, mode)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if lf.GetPointCount() == 0 {
+					b.Fatal("expected points")
+				}
+				lf.Close()
+			}
+		})
+	}
+}
+
+func TestLaszipReaderClose(t *testing.T) {
+	// A reader that was never opened still owns a LASzip pointer.
+	unopened, err := NewLaszipReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = unopened.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = unopened.Close(); err != nil {
+		t.Errorf("second Close: expected nil, got %v", err)
+	}
+	if err = unopened.OpenReader("testdata/sample.las"); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("OpenReader after Close: expected ErrReaderClosed, got %v", err)
+	}
+
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = lf.LasPoint(0); err != nil {
+		t.Fatal(err)
+	}
+	if err = lf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = lf.Close(); err != nil {
+		t.Errorf("second Close: expected nil, got %v", err)
+	}
+
+	r := lf.reader
+	if err = r.ReadPoint(); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("ReadPoint after Close: expected ErrReaderClosed, got %v", err)
+	}
+	if _, err = r.ReadPointsInto(make([]LaszipPoint, 10)); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("ReadPointsInto after Close: expected ErrReaderClosed, got %v", err)
+	}
+	if err = r.SeekPoint(1); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("SeekPoint after Close: expected ErrReaderClosed, got %v", err)
+	}
+	if err = r.Reset(); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("Reset after Close: expected ErrReaderClosed, got %v", err)
+	}
+	if r.GetPoint() != nil || r.GetHeader() != nil || r.GetVLRs() != nil {
+		t.Error("expected no point, header or VLRs from a closed reader")
+	}
+	if _, err = lf.LasPoint(1); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("LasPoint after Close: expected ErrReaderClosed, got %v", err)
+	}
+}
+
+func TestLazFileFromReader(t *testing.T) {
+	requireSampleLaz(t)
+	data, err := os.ReadFile(sampleLazFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromFile.Close()
+	fromReader, err := NewLazFileFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromReader.Header.NumberPoints != fromFile.Header.NumberPoints {
+		t.Fatalf("expected %v points, got %v", fromFile.Header.NumberPoints, fromReader.Header.NumberPoints)
+	}
+	expected, err := fromFile.ReadPoints(0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fromReader.ReadPoints(0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range expected {
+		if *got[i].PointData() != *expected[i].PointData() {
+			t.Fatalf("point %v: expected %+v, got %+v", i, expected[i].PointData(), got[i].PointData())
+		}
+	}
+
+	if err = fromReader.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLazFileFromBytes(t *testing.T) {
+	if _, err := NewLazFileFromBytes(nil); err == nil {
+		t.Error("expected an error for empty data")
+	}
+
+	requireSampleLaz(t)
+	data, err := os.ReadFile(sampleLazFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromFile.Close()
+	fromBytes, err := NewLazFileFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fromBytes.Close()
+
+	if fromBytes.Header.NumberPoints != fromFile.Header.NumberPoints {
+		t.Fatalf("expected %v points, got %v", fromFile.Header.NumberPoints, fromBytes.Header.NumberPoints)
+	}
+	expected, err := fromFile.ReadPoints(0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fromBytes.ReadPoints(0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range expected {
+		if *got[i].PointData() != *expected[i].PointData() {
+			t.Fatalf("point %v: expected %+v, got %+v", i, expected[i].PointData(), got[i].PointData())
+		}
+	}
+}
+
+func TestLazFileFromReaderInvalid(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	if _, err := NewLazFileFromReader(bytes.NewReader([]byte("not a LAZ file"))); err == nil {
+		t.Fatal("expected an error for invalid LAZ data")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected the spooled file to be removed, found %v entries", len(entries))
+	}
+}
+
+func TestValidatePointDataHeader(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.las")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	check := func(name string, content []byte) error {
+		fileName := filepath.Join(dir, name)
+		if err := os.WriteFile(fileName, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := openRawFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		return validatePointDataHeader(f, f.Size())
+	}
+	if err = check("valid.las", data); err != nil {
+		t.Errorf("a valid file was rejected: %v", err)
+	}
+
+	truncated := data[:binary.LittleEndian.Uint32(data[96:100])-1]
+	if err = check("truncated.las", truncated); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("truncated before the point data: expected ErrCorruptFile, got %v", err)
+	}
+	short := append([]byte{}, data...)
+	binary.LittleEndian.PutUint16(short[105:107], 10)
+	if err = check("short.las", short); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("short record length: expected ErrCorruptFile, got %v", err)
+	}
+	unknown := append([]byte{}, data...)
+	unknown[104] = 0x80 | 11
+	if err = check("unknown.laz", unknown); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("unknown point format: expected ErrCorruptFile, got %v", err)
+	}
+	if err = check("tiny.laz", data[:50]); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("truncated header: expected ErrCorruptFile, got %v", err)
+	}
+
+	// The checks are made before LASzip is involved.
+	if _, err = NewLazFile(filepath.Join(dir, "short.las"), "r"); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("NewLazFile: expected ErrCorruptFile, got %v", err)
+	}
+}
+
+func TestTruncatedLazFile(t *testing.T) {
+	requireSampleLaz(t)
+	data, err := os.ReadFile(sampleLazFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(t.TempDir(), "truncated.laz")
+	if err = os.WriteFile(fileName, data[:len(data)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	lf, err := NewLazFile(fileName, "r")
+	if err != nil {
+		// LASzip may refuse the file outright, e.g. when its chunk table is lost.
+		return
+	}
+	defer lf.Close()
+	it, err := lf.Points()
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err() == nil {
+		t.Fatalf("expected an error reading a truncated file, read %v points without error", count)
+	}
+	if !errors.Is(it.Err(), ErrCorruptFile) {
+		t.Errorf("expected ErrCorruptFile, got %v", it.Err())
+	}
+	if it.Next() {
+		t.Error("Next returned true after a failed read")
+	}
+}
+
+func TestLazRewind(t *testing.T) {
+	lf := &LazFile{fileMode: "r", reader: &LaszipReader{}}
+	if err := lf.Rewind(); !errors.Is(err, ErrReaderClosed) {
+		t.Errorf("expected ErrReaderClosed rewinding a closed file, got %v", err)
+	}
+
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if err = lf.reader.ReadPoint(); err != nil {
+		t.Fatal(err)
+	}
+	first := *lf.reader.GetPoint()
+	for i := 0; i < 100; i++ {
+		if err = lf.reader.ReadPoint(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lf.currentPoint = 101
+
+	if err = lf.Rewind(); err != nil {
+		t.Fatal(err)
+	}
+	if lf.currentPoint != 0 || lf.reader.PointsRead() != 0 {
+		t.Errorf("the position was not reset: %v, %v", lf.currentPoint, lf.reader.PointsRead())
+	}
+	if err = lf.reader.ReadPoint(); err != nil {
+		t.Fatal(err)
+	}
+	if again := *lf.reader.GetPoint(); again != first {
+		t.Errorf("the first point read after rewinding %+v differs from %+v", again, first)
+	}
+}
+
+func TestLazRawXYZ(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	h := lf.Header
+	for _, i := range []int{0, 1, 1000, h.NumberPoints - 1} {
+		rawX, rawY, rawZ, err := lf.GetRawXYZ(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, y, z, err := lf.GetXYZ(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		const tolerance = 1e-6
+		if math.Abs(float64(rawX)*h.XScaleFactor+h.XOffset-x) > tolerance ||
+			math.Abs(float64(rawY)*h.YScaleFactor+h.YOffset-y) > tolerance ||
+			math.Abs(float64(rawZ)*h.ZScaleFactor+h.ZOffset-z) > tolerance {
+			t.Errorf("point %v: raw (%v, %v, %v) does not scale to (%v, %v, %v)", i, rawX, rawY, rawZ, x, y, z)
+		}
+	}
+}
+
+func TestLaszipReaderFinalizer(t *testing.T) {
+	finalized := make(chan string, 1)
+	warn := warnUnclosed
+	warnUnclosed = func(r *LaszipReader) { finalized <- r.fileName }
+	defer func() { warnUnclosed = warn }()
+
+	func() {
+		r, err := NewLaszipReader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.fileName = "dropped.laz"
+	}()
+	deadline := time.After(5 * time.Second)
+	for {
+		runtime.GC()
+		select {
+		case name := <-finalized:
+			if name != "dropped.laz" {
+				t.Errorf("finalizer ran for %q, expected dropped.laz", name)
+			}
+			return
+		case <-deadline:
+			t.Fatal("the finalizer of an unclosed reader did not run")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestLazXYZRange(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	// The range spans several batches and ends at the last point.
+	n := lf.Header.NumberPoints
+	for _, start := range []int{0, 5000, n - 10000} {
+		coords, err := lf.GetXYZRange(start, 10000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(coords) != 10000 {
+			t.Fatalf("expected 10000 points, got %v", len(coords))
+		}
+		for _, i := range []int{0, 1, 4095, 4096, 9999} {
+			x, y, z, err := lf.GetXYZ(start + i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if coords[i] != [3]float64{x, y, z} {
+				t.Errorf("point %v: the range holds %v but GetXYZ returns (%v, %v, %v)", start+i, coords[i], x, y, z)
+			}
+		}
+	}
+}
+
+func TestLazXYZRangeBounds(t *testing.T) {
+	lf := &LazFile{fileMode: "r"}
+	lf.Header.NumberPoints = 10
+	for _, r := range [][2]int{{-1, 2}, {8, 3}, {10, 1}} {
+		if _, err := lf.GetXYZRange(r[0], r[1]); !errors.Is(err, ErrPointOutOfRange) {
+			t.Errorf("range %v: expected ErrPointOutOfRange, got %v", r, err)
+		}
+	}
+	if _, err := lf.GetXYZRange(0, -1); err == nil {
+		t.Error("expected an error for a negative count")
+	}
+	lf.fileMode = "rh"
+	if _, err := lf.GetXYZRange(0, 1); !errors.Is(err, errHeaderOnly) {
+		t.Errorf("expected errHeaderOnly, got %v", err)
+	}
+}
+
+func TestPointCount64(t *testing.T) {
+	const huge = 5000000000 // more than 2^32 points
+	cases := []struct {
+		h        LasHeader
+		expected uint64
+	}{
+		{LasHeader{VersionMajor: 1, VersionMinor: 2, NumberPoints: 1000}, 1000},
+		// The legacy count of a LAS 1.4 file is zero if it overflows.
+		{LasHeader{VersionMajor: 1, VersionMinor: 4, ExtendedNumberPoints: huge}, huge},
+		{LasHeader{VersionMajor: 1, VersionMinor: 4, NumberPoints: 1000, ExtendedNumberPoints: 1000}, 1000},
+		{LasHeader{VersionMajor: 1, VersionMinor: 4, NumberPoints: 1000}, 1000},
+	}
+	for _, c := range cases {
+		for _, lf := range []LidarFile{&LazFile{Header: c.h}, &LasFile{Header: c.h}} {
+			if n := lf.GetPointCount64(); n != c.expected {
+				t.Errorf("%T with header counts %v and %v: got %v points, expected %v",
+					lf, c.h.NumberPoints, c.h.ExtendedNumberPoints, n, c.expected)
+			}
+		}
+	}
+
+	// Indices beyond 2^32 are within the range of the file.
+	lf := &LazFile{fileMode: "r", reader: &LaszipReader{}, Header: cases[1].h}
+	if _, err := lf.readPoint(huge); !errors.Is(err, ErrPointOutOfRange) {
+		t.Errorf("expected ErrPointOutOfRange past the last point, got %v", err)
+	}
+	if _, err := lf.readPoint(huge - 1); errors.Is(err, ErrPointOutOfRange) {
+		t.Errorf("point %v lies within the file: %v", huge-1, err)
+	}
+}
+
+func TestLaszipErrorCode(t *testing.T) {
+	// The LASzip VLR of the file describes compressor 0, which LASzip
+	// rejects when opening the file for decompression.
+	_, err := NewLazFile(writeHeaderOnlyLaz(t), "r")
+	var le *LaszipError
+	if !errors.As(err, &le) {
+		t.Fatalf("expected a LaszipError, got %v", err)
+	}
+	if le.Code == 0 {
+		t.Errorf("expected a non-zero code, got %+v", le)
+	}
+
+	if msg := (&LaszipError{Code: 1}).Error(); msg != "unknown LASzip error (code 1)" {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+// seekOnly hides the ReadAt method of a reader.
+type seekOnly struct{ io.ReadSeeker }
+
+func TestStreamSource(t *testing.T) {
+	data := []byte("0123456789")
+	for _, r := range []io.ReadSeeker{bytes.NewReader(data), seekOnly{bytes.NewReader(data)}} {
+		s, err := newStreamSource(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Size() != 10 {
+			t.Errorf("%T: size %v, expected 10", r, s.Size())
+		}
+		b := make([]byte, 3)
+		if n, err := s.ReadAt(b, 4); n != 3 || err != nil || string(b) != "456" {
+			t.Errorf("%T: read %q (%v, %v), expected \"456\"", r, b[:n], n, err)
+		}
+		if n, err := s.ReadAt(b, 8); n != 2 || err != io.EOF {
+			t.Errorf("%T: read %v bytes (%v) at the end, expected 2 and io.EOF", r, n, err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// ErrVLRAfterPoints is returned when a VLR is added to a writer after the
+// first point has been written, at which point the VLRs are already written.
+var ErrVLRAfterPoints = errors.New("VLRs must be added before the first point is written")
+
+// LazWriter writes points to a compressed LAZ file. Unlike a LasFile opened in
+// 'w' mode, which holds the points in memory until it is closed, points are
+// compressed and written as they are added.
+type LazWriter struct {
+	fileName       string
+	header         LasHeader
+	writer         *LaszipWriter
+	vlrs           []VLR
+	intensityScale float64
+	// started is set once the file is opened by LASzip, which writes the
+	// header and VLRs
+	started bool
+}
+
+// NewLazWriter creates a LAZ file using the point format, scale factors and
+// offsets of header; point formats 0-3 are supported. Zero scale factors
+// default to 0.0001, matching the LAS writer. If an offset is zero and the
+// header carries a valid extent, the offset is set to the minimum of the
+// extent so that large coordinates fit in the stored 32-bit integers. The
+// point counts and bounds are computed from the points that are written. VLRs,
+// such as those describing the coordinate system, are supplied with WithVLRs
+// or added with AddVLR until the first point is written. An existing file is
+// not replaced unless WithOverwrite(true) is supplied; IntensityScale and
+// ConvertPointFormat are also honoured.
+func NewLazWriter(fileName string, header LasHeader, opts ...WriterOption) (*LazWriter, error) {
+	o := newWriterOptions(opts)
+	if err := o.check(); err != nil {
+		return nil, err
+	}
+	if o.convertFormat {
+		header.PointFormatID = o.pointFormat
+	}
+	h, err := prepareWriterHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	for _, vlr := range o.vlrs {
+		if err = checkVLRSize(vlr); err != nil {
+			return nil, err
+		}
+	}
+	if err = o.checkOutput(fileName); err != nil {
+		return nil, err
+	}
+	// LASzip opens the file when the first point is written; create it now
+	// so that an unwritable path is reported here.
+	f, err := os.Create(fileName)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+
+	w, err := NewLaszipWriter()
+	if err != nil {
+		return nil, err
+	}
+	lw := LazWriter{
+		fileName:       fileName,
+		header:         h,
+		writer:         w,
+		vlrs:           o.vlrs,
+		intensityScale: o.intensityScale,
+	}
+	return &lw, nil
+}
+
+// AddVLR appends a VLR, such as one describing the coordinate system or a
+// classification lookup table, to those written after the header. The point
+// data offset and VLR count of the header account for it. VLRs can only be
+// added before the first point is written; ErrVLRAfterPoints is returned
+// otherwise.
+func (lw *LazWriter) AddVLR(vlr VLR) error {
+	if lw.started {
+		return ErrVLRAfterPoints
+	}
+	if err := checkVLRSize(vlr); err != nil {
+		return err
+	}
+	lw.vlrs = append(lw.vlrs, vlr)
+	return nil
+}
+
+// start opens the file for writing, writing the header and VLRs.
+func (lw *LazWriter) start() error {
+	if lw.started {
+		return nil
+	}
+	lw.started = true
+	if err := lw.writer.OpenWriter(lw.fileName, &lw.header, lw.vlrs...); err != nil {
+		return fmt.Errorf("failed to open LAZ file for writing: %v", err)
+	}
+	return nil
+}
+
+// WritePoint compresses and writes a point. ErrCoordinateOutOfRange is
+// returned if a coordinate cannot be stored using the scale factors and
+// offsets of the file.
+func (lw *LazWriter) WritePoint(p LasPointer) error {
+	if err := checkPointRange(&lw.header, p.PointData()); err != nil {
+		return err
+	}
+	if err := lw.start(); err != nil {
+		return err
+	}
+	lp := toLaszipPoint(p)
+	if lw.intensityScale != 1.0 {
+		lp.Intensity = scaleIntensity(lp.Intensity, lw.intensityScale)
+	}
+	if err := lw.writer.WritePoint(&lp); err != nil {
+		return fmt.Errorf("failed to write point: %v", err)
+	}
+	return nil
+}
+
+// Close writes the final point counts and bounds to the header and closes the file.
+func (lw *LazWriter) Close() error {
+	if err := lw.start(); err != nil {
+		lw.writer.Close()
+		return err
+	}
+	return lw.writer.Close()
+}
+
+// checkVLRSize returns an error if the payload of the VLR is too large for
+// the 16-bit length of a VLR header.
+func checkVLRSize(vlr VLR) error {
+	if len(vlr.BinaryData) > 65535 {
+		return fmt.Errorf("the payload of VLR %v/%v is too large (%v bytes)", vlr.UserID, vlr.RecordID, len(vlr.BinaryData))
+	}
+	return nil
+}
+
+// checkPointRange returns ErrCoordinateOutOfRange if a coordinate of the point
+// cannot be stored using the scale factors and offsets of the header.
+func checkPointRange(h *LasHeader, pd *PointRecord0) error {
+	if err := checkCoordinateRange("X", pd.X, h.XOffset, h.XScaleFactor); err != nil {
+		return err
+	}
+	if err := checkCoordinateRange("Y", pd.Y, h.YOffset, h.YScaleFactor); err != nil {
+		return err
+	}
+	return checkCoordinateRange("Z", pd.Z, h.ZOffset, h.ZScaleFactor)
+}
+
+// toLaszipPoint converts a lidario point to a LASzip point.
+func toLaszipPoint(p LasPointer) LaszipPoint {
+	pd := p.PointData()
+	lp := LaszipPoint{
+		X:                 pd.X,
+		Y:                 pd.Y,
+		Z:                 pd.Z,
+		Intensity:         pd.Intensity,
+		ReturnNumber:      pd.BitField.Value & 7,
+		NumberOfReturns:   (pd.BitField.Value >> 3) & 7,
+		ScanDirectionFlag: (pd.BitField.Value >> 6) & 1,
+		EdgeOfFlightFlag:  (pd.BitField.Value >> 7) & 1,
+		Classification:    pd.ClassBitField.Classification(),
+		Synthetic:         pd.ClassBitField.Synthetic(),
+		Keypoint:          pd.ClassBitField.Keypoint(),
+		Withheld:          pd.ClassBitField.withheld(),
+		ScanAngleRank:     pd.ScanAngle,
+		UserData:          pd.UserData,
+		PointSourceID:     pd.PointSourceID,
+	}
+	switch p.Format() {
+	case 1, 3:
+		lp.GPSTime = p.GpsTimeData()
+	}
+	if rgb := p.RgbData(); rgb != nil {
+		lp.Red, lp.Green, lp.Blue = rgb.Red, rgb.Green, rgb.Blue
+	}
+	return lp
+}
+
+// prepareWriterHeader returns the header of a LAS 1.2 file to be written by
+// NewLazWriter or NewLasWriter, filling in the defaults described there.
+func prepareWriterHeader(header LasHeader) (LasHeader, error) {
+	if header.PointFormatID > 3 {
+		return header, fmt.Errorf("point format %v is not supported for writing", header.PointFormatID)
+	}
+	h := header
+	h.FileSignature = "LASF"
+	h.VersionMajor = 1
+	h.VersionMinor = 2
+	h.PointRecordLength = pointFormatRecordLengths[h.PointFormatID]
+	h.NumberPoints = 0
+	h.NumberPointsByReturn = [15]int{}
+	if h.SystemID == "" {
+		h.SystemID = "OTHER"
+	}
+	if h.GeneratingSoftware == "" {
+		h.GeneratingSoftware = "lidario"
+	}
+	if h.FileCreationYear == 0 {
+		now := time.Now()
+		h.FileCreationDay = now.YearDay()
+		h.FileCreationYear = now.Year()
+	}
+	defaultScale := func(scale *float64) {
+		if *scale == 0 {
+			*scale = 0.0001
+		}
+	}
+	defaultScale(&h.XScaleFactor)
+	defaultScale(&h.YScaleFactor)
+	defaultScale(&h.ZScaleFactor)
+	defaultOffset := func(offset *float64, min, max float64) {
+		if *offset == 0 && min <= max && !math.IsInf(min, 0) && !math.IsInf(max, 0) {
+			*offset = math.Floor(min)
+		}
+	}
+	defaultOffset(&h.XOffset, h.MinX, h.MaxX)
+	defaultOffset(&h.YOffset, h.MinY, h.MaxY)
+	defaultOffset(&h.ZOffset, h.MinZ, h.MaxZ)
+	return h, nil
+}
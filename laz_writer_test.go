@@ -0,0 +1,150 @@
+package lidario
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// requireLaszip skips the test if a functional LASzip library is not linked.
+func requireLaszip(t *testing.T) {
+	t.Helper()
+	if _, _, _, err := LaszipVersion(); err != nil {
+		t.Skipf("LASzip not available: %v", err)
+	}
+}
+
+func TestLazWriterRoundTrip(t *testing.T) {
+	requireLaszip(t)
+	fileName := filepath.Join(t.TempDir(), "roundtrip.laz")
+	header := LasHeader{PointFormatID: 3, XScaleFactor: 0.01, YScaleFactor: 0.01, ZScaleFactor: 0.01,
+		MinX: 500000, MaxX: 501000, MinY: 4400000, MaxY: 4401000, MinZ: 0, MaxZ: 100}
+	lw, err := NewLazWriter(fileName, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 10000
+	points := make([]LasPointer, n)
+	for i := range points {
+		p := &PointRecord3{
+			PointRecord0: &PointRecord0{
+				X:         500000 + float64(i%100)*10.123,
+				Y:         4400000 + float64(i/100)*10.456,
+				Z:         float64(i%97) * 0.789,
+				Intensity: uint16(i),
+				BitField:  PointBitField{Value: 1 | 2<<3},
+			},
+			GPSTime: float64(i) * 0.001,
+			RGB:     &RgbData{Red: uint16(i), Green: uint16(2 * i), Blue: uint16(3 * i)},
+		}
+		p.ClassBitField.SetClassification(uint8(i % 10))
+		points[i] = p
+		if err = lw.WritePoint(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err := NewLazFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if lf.Header.NumberPoints != n || lf.Header.NumberPointsByReturn[0] != n {
+		t.Errorf("expected %v first-return points, got %v (%v)", n, lf.Header.NumberPoints, lf.Header.NumberPointsByReturn)
+	}
+	it, err := lf.Points()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := 0
+	for ; it.Next(); i++ {
+		got, want := it.Point(), points[i]
+		g, w := got.PointData(), want.PointData()
+		if math.Abs(g.X-w.X) > 0.005 || math.Abs(g.Y-w.Y) > 0.005 || math.Abs(g.Z-w.Z) > 0.005 {
+			t.Fatalf("point %v: (%v, %v, %v), expected (%v, %v, %v)", i, g.X, g.Y, g.Z, w.X, w.Y, w.Z)
+		}
+		if g.Intensity != w.Intensity || g.ClassBitField != w.ClassBitField || g.BitField != w.BitField ||
+			*got.RgbData() != *want.RgbData() || got.GpsTimeData() != want.GpsTimeData() {
+			t.Fatalf("point %v: attributes do not match", i)
+		}
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if i != n {
+		t.Errorf("read back %v points, expected %v", i, n)
+	}
+}
+
+func TestLazWriterAddVLR(t *testing.T) {
+	requireLaszip(t)
+	fileName := filepath.Join(t.TempDir(), "addvlr.laz")
+	lw, err := NewLazWriter(fileName, LasHeader{XScaleFactor: 0.01, YScaleFactor: 0.01, ZScaleFactor: 0.01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom := VLR{UserID: "lidario", RecordID: 42, Description: "custom record", BinaryData: []byte("metadata")}
+	if err = lw.AddVLR(custom); err != nil {
+		t.Fatal(err)
+	}
+	if err = lw.WritePoint(&PointRecord0{X: 1, Y: 2, Z: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err = lw.AddVLR(VLR{UserID: "late"}); !errors.Is(err, ErrVLRAfterPoints) {
+		t.Errorf("expected ErrVLRAfterPoints, got %v", err)
+	}
+	if err = lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err := NewLazFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	vlrs := lf.GetVLRs()
+	if len(vlrs) != 1 || lf.Header.NumberOfVLRs != 1 {
+		t.Fatalf("%v VLRs (header %v), expected 1", len(vlrs), lf.Header.NumberOfVLRs)
+	}
+	if vlr := vlrs[0]; vlr.UserID != custom.UserID || vlr.RecordID != custom.RecordID ||
+		vlr.Description != custom.Description || !bytes.Equal(vlr.BinaryData, custom.BinaryData) {
+		t.Errorf("unexpected VLR %v/%v %q", vlr.UserID, vlr.RecordID, vlr.Description)
+	}
+	if x, y, z, err := lf.GetXYZ(0); err != nil || x != 1 || y != 2 || z != 3 {
+		t.Errorf("point (%v, %v, %v) (%v), expected (1, 2, 3)", x, y, z, err)
+	}
+}
+
+func TestToLaszipPoint(t *testing.T) {
+	p := &PointRecord3{
+		PointRecord0: &PointRecord0{X: 1, Y: 2, Z: 3, Intensity: 4, BitField: PointBitField{Value: 2 | 3<<3 | 1<<6},
+			ClassBitField: ClassificationBitField{Value: 6 | 0x80}, ScanAngle: -5, UserData: 7, PointSourceID: 8},
+		GPSTime: 9,
+		RGB:     &RgbData{Red: 10, Green: 11, Blue: 12},
+	}
+	lp := toLaszipPoint(p)
+	lf := &LazFile{Header: LasHeader{PointFormatID: 3}}
+	back := lf.convertPoint(&lp)
+	if *back.PointData() != *p.PointData() || back.GpsTimeData() != 9 || *back.RgbData() != *p.RGB {
+		t.Errorf("the point did not survive conversion: %+v", back.PointData())
+	}
+}
+
+func TestLazWriterExistingOutput(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "exists.laz")
+	if err := os.WriteFile(fileName, []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLazWriter(fileName, LasHeader{}); !errors.Is(err, ErrOutputExists) {
+		t.Errorf("expected ErrOutputExists, got %v", err)
+	}
+	if b, _ := os.ReadFile(fileName); string(b) != "keep" {
+		t.Errorf("the existing file was modified: %q", b)
+	}
+}
@@ -1,8 +1,12 @@
 package lidario
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"sync"
 )
 
@@ -11,16 +15,47 @@ type LazFile struct {
 	fileName     string
 	fileMode     string
 	reader       *LaszipReader
+	// source is the stream of a file created by NewLazFileFromReader
+	source       *streamSource
 	Header       LasHeader
 	VlrData      []VLR
+	EVlrData     []EVLR
 	geokeys      GeoKeys
 	isCompressed bool
 	currentPoint int
+	// waveformDescriptors are parsed when waveform data are first requested
+	waveformDescriptors map[int]WaveformDescriptor
+	// batch is reused by ReadPoints
+	batch []LaszipPoint
+	// pointsByReturn caches the counts computed by PointsByReturn
+	pointsByReturn *[15]int
+	// stats caches the result of the last ComputeStatistics call
+	stats *PointStats
+	// transformer is used by ReprojectXYZ and ReprojectAll
+	transformer Transformer
+	// progress is called as points are read; see SetProgressFunc
+	progress ProgressFunc
+	// classMap translates the classes of the points read; see
+	// SetClassificationMap
+	classMap map[uint8]uint8
+	// logger receives the diagnostics of the file; see SetLogger
+	logger *slog.Logger
+	// fastMode skips the bounds check of readPoint; see SetFastMode
+	fastMode bool
+	// fallbackOnce logs the first point of an unsupported format
+	fallbackOnce sync.Once
 	sync.RWMutex
 }
 
 // NewLazFile creates a new LazFile for reading compressed LAZ files
 func NewLazFile(fileName, fileMode string) (*LazFile, error) {
+	return newLazFile(fileName, fileMode, nil, nil)
+}
+
+// newLazFile opens a LAZ file, or the stream of source if it is not nil,
+// whose diagnostics, including those of reading the header, are sent to
+// logger.
+func newLazFile(fileName, fileMode string, source *streamSource, logger *slog.Logger) (*LazFile, error) {
 	if fileMode != "r" && fileMode != "rh" {
 		return nil, errors.New("LAZ files only support read mode")
 	}
@@ -28,31 +63,128 @@ func NewLazFile(fileName, fileMode string) (*LazFile, error) {
 	lazFile := &LazFile{
 		fileName:     fileName,
 		fileMode:     fileMode,
+		source:       source,
 		isCompressed: true,
 		currentPoint: 0,
+		logger:       logger,
 	}
 	
+	// A header-only open skips LASzip, which would also prepare the
+	// decompression of the points.
+	if fileMode == "rh" {
+		if err := lazFile.readHeaderOnly(); err != nil {
+			return nil, err
+		}
+		lazFile.logOpen()
+		return lazFile, nil
+	}
+
 	// Create LASzip reader
 	reader, err := NewLaszipReader()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LASzip reader: %v", err)
 	}
+	reader.logger = logger
 	lazFile.reader = reader
 	
 	// Open the LAZ file
-	if err := reader.OpenReader(fileName); err != nil {
-		return nil, fmt.Errorf("failed to open LAZ file: %v", err)
-	}
-	
-	// Convert LASzip header to LAS header format
-	if err := lazFile.convertHeader(); err != nil {
+	if source != nil {
+		if err := reader.openStream(source); err != nil {
+			reader.Close()
+			return nil, fmt.Errorf("failed to open LAZ stream: %w", err)
+		}
+	} else if err := reader.OpenReader(fileName); err != nil {
 		reader.Close()
-		return nil, fmt.Errorf("failed to convert header: %v", err)
+		return nil, fmt.Errorf("failed to open LAZ file: %w", err)
 	}
 	
+	if err := lazFile.readHeader(); err != nil {
+		return nil, err
+	}
+	lazFile.logOpen()
 	return lazFile, nil
 }
 
+// logOpen logs the opening of the file.
+func (lf *LazFile) logOpen() {
+	lf.log().Debug("opened LAZ file", "file", lf.fileName, "mode", lf.fileMode,
+		"version", fmt.Sprintf("%v.%v", lf.Header.VersionMajor, lf.Header.VersionMinor),
+		"point_format", lf.Header.PointFormatID, "points", lf.Header.NumberPoints, "vlrs", len(lf.VlrData))
+}
+
+// NewLazFileFromReader creates a new LazFile reading the LAZ data of r, such
+// as an object fetched from cloud storage. LASzip reads r through its stream
+// API; see LaszipReader.OpenReaderStream. r must not be used elsewhere until
+// the LazFile is closed. If r has a Name method, as an *os.File does, the name
+// is used as the file name in diagnostics.
+func NewLazFileFromReader(r io.ReadSeeker) (*LazFile, error) {
+	source, err := newStreamSource(r)
+	if err != nil {
+		return nil, err
+	}
+	var fileName string
+	if named, ok := r.(interface{ Name() string }); ok {
+		fileName = named.Name()
+	}
+	return newLazFile(fileName, "r", source, nil)
+}
+
+// NewLazFileFromBytes creates a new LazFile reading the LAZ data held in
+// memory, such as an object already downloaded from cloud storage. LASzip
+// reads data in place through a bytes.Reader; no temporary file is written
+// and data are not copied, so they must not be modified until the LazFile is
+// closed.
+func NewLazFileFromBytes(data []byte) (*LazFile, error) {
+	return NewLazFileFromReader(bytes.NewReader(data))
+}
+
+// readHeader converts the header and VLRs of the opened reader.
+func (lf *LazFile) readHeader() error {
+	// Convert LASzip header to LAS header format
+	if err := lf.convertHeader(); err != nil {
+		lf.reader.Close()
+		return fmt.Errorf("failed to convert header: %v", err)
+	}
+	lf.VlrData = lf.reader.GetVLRs()
+	for _, vlr := range lf.VlrData {
+		lf.addVLR(vlr)
+	}
+	if err := lf.readEVLRs(); err != nil {
+		lf.reader.Close()
+		return fmt.Errorf("failed to read EVLRs: %w", err)
+	}
+	return nil
+}
+
+// readHeaderOnly reads the header and VLRs directly from the file. The header
+// is adjusted as LASzip adjusts it: the compression bits are cleared from the
+// point format and the LASzip VLR is hidden.
+func (lf *LazFile) readHeaderOnly() error {
+	las, err := NewLasFile(lf.fileName, "rh")
+	if err != nil {
+		return fmt.Errorf("failed to read the header: %w", err)
+	}
+	las.Close()
+
+	lf.Header = las.Header
+	lf.Header.PointFormatID &= 0x3F
+	lf.Header.NumberPoints = int(lf.Header.pointCount())
+	lf.VlrData = make([]VLR, 0, len(las.VlrData))
+	for _, vlr := range las.VlrData {
+		if vlr.UserID == laszipVLRUserID && vlr.RecordID == laszipVLRRecordID {
+			lf.Header.NumberOfVLRs--
+			lf.Header.OffsetToPoints -= 54 + vlr.RecordLengthAfterHeader
+			continue
+		}
+		lf.VlrData = append(lf.VlrData, vlr)
+		lf.addVLR(vlr)
+	}
+	if err := lf.readEVLRs(); err != nil {
+		return fmt.Errorf("failed to read EVLRs: %w", err)
+	}
+	return nil
+}
+
 // convertHeader converts LASzip header to lidario LasHeader format
 func (lf *LazFile) convertHeader() error {
 	laszipHeader := lf.reader.GetHeader()
@@ -62,38 +194,55 @@ func (lf *LazFile) convertHeader() error {
 	
 	// Convert header fields
 	lf.Header = LasHeader{
-		FileSignature:        "LASF",
-		FileSourceID:         0, // Will need to read from actual header
-		GlobalEncoding:       GlobalEncodingField{}, // Will need to read from actual header
-		ProjectID1:           0,
-		ProjectID2:           0,
-		ProjectID3:           0,
-		ProjectID4:           [8]byte{},
-		VersionMajor:         byte(laszipHeader.VersionMajor),
-		VersionMinor:         byte(laszipHeader.VersionMinor),
-		SystemID:             "", // 32 characters
-		GeneratingSoftware:   "", // 32 characters
-		FileCreationDay:      0,
-		FileCreationYear:     0,
-		HeaderSize:           int(laszipHeader.HeaderSize),
-		OffsetToPoints:       int(laszipHeader.OffsetToPointData),
-		NumberOfVLRs:         int(laszipHeader.NumberOfVLRs),
-		PointFormatID:        byte(laszipHeader.PointDataFormat),
-		PointRecordLength:    int(laszipHeader.PointDataRecordLength),
-		NumberPoints:         int(laszipHeader.NumberOfPointRecords),
-		NumberPointsByReturn: [5]int{}, // Will need to calculate
-		XScaleFactor:         laszipHeader.XScaleFactor,
-		YScaleFactor:         laszipHeader.YScaleFactor,
-		ZScaleFactor:         laszipHeader.ZScaleFactor,
-		XOffset:              laszipHeader.XOffset,
-		YOffset:              laszipHeader.YOffset,
-		ZOffset:              laszipHeader.ZOffset,
-		MaxX:                 laszipHeader.MaxX,
-		MinX:                 laszipHeader.MinX,
-		MaxY:                 laszipHeader.MaxY,
-		MinY:                 laszipHeader.MinY,
-		MaxZ:                 laszipHeader.MaxZ,
-		MinZ:                 laszipHeader.MinZ,
+		FileSignature:      "LASF",
+		FileSourceID:       int(laszipHeader.FileSourceID),
+		GlobalEncoding:     GlobalEncodingField{Value: laszipHeader.GlobalEncoding},
+		ProjectID1:         int(laszipHeader.ProjectID1),
+		ProjectID2:         int(laszipHeader.ProjectID2),
+		ProjectID3:         int(laszipHeader.ProjectID3),
+		ProjectID4:         laszipHeader.ProjectID4,
+		VersionMajor:       byte(laszipHeader.VersionMajor),
+		VersionMinor:       byte(laszipHeader.VersionMinor),
+		SystemID:           laszipHeader.SystemID,
+		GeneratingSoftware: laszipHeader.GeneratingSoftware,
+		FileCreationDay:    int(laszipHeader.FileCreationDay),
+		FileCreationYear:   int(laszipHeader.FileCreationYear),
+		HeaderSize:         int(laszipHeader.HeaderSize),
+		OffsetToPoints:     int(laszipHeader.OffsetToPointData),
+		NumberOfVLRs:       int(laszipHeader.NumberOfVLRs),
+		PointFormatID:      byte(laszipHeader.PointDataFormat),
+		PointRecordLength:  int(laszipHeader.PointDataRecordLength),
+		NumberPoints:       int(laszipHeader.NumberOfPointRecords),
+		XScaleFactor:       laszipHeader.XScaleFactor,
+		YScaleFactor:       laszipHeader.YScaleFactor,
+		ZScaleFactor:       laszipHeader.ZScaleFactor,
+		XOffset:            laszipHeader.XOffset,
+		YOffset:            laszipHeader.YOffset,
+		ZOffset:            laszipHeader.ZOffset,
+		MaxX:               laszipHeader.MaxX,
+		MinX:               laszipHeader.MinX,
+		MaxY:               laszipHeader.MaxY,
+		MinY:               laszipHeader.MinY,
+		MaxZ:               laszipHeader.MaxZ,
+		MinZ:               laszipHeader.MinZ,
+		WaveformDataStart:  laszipHeader.WaveformDataStart,
+		projectIDUsed:      true,
+	}
+	// Some encoders leave the compression bits (6 and 7) set in the point
+	// format; convertPoint switches on the bare format.
+	lf.Header.PointFormatID &= 0x3F
+	for i, n := range laszipHeader.NumberOfPointsByReturn {
+		lf.Header.NumberPointsByReturn[i] = int(n)
+	}
+	if lf.Header.versionAtLeast(1, 4) {
+		lf.Header.StartOfFirstEVLR = laszipHeader.StartOfFirstEVLR
+		lf.Header.NumberOfEVLRs = int(laszipHeader.NumberOfEVLRs)
+		lf.Header.ExtendedNumberPoints = laszipHeader.ExtendedNumberOfPointRecords
+		lf.Header.NumberPoints = int(lf.Header.pointCount())
+		for i, n := range laszipHeader.ExtendedNumberOfPointsByReturn {
+			// The 64-bit counts supersede the legacy 32-bit counts.
+			lf.Header.NumberPointsByReturn[i] = int(n)
+		}
 	}
 	
 	return nil
@@ -101,22 +250,41 @@ func (lf *LazFile) convertHeader() error {
 
 // LasPoint reads a point and converts it to lidario LasPointer format
 func (lf *LazFile) LasPoint(pointIndex int) (LasPointer, error) {
-	lf.RLock()
-	defer lf.RUnlock()
+	lf.Lock()
+	defer lf.Unlock()
 	
-	if pointIndex < 0 || pointIndex >= int(lf.Header.NumberPoints) {
-		return nil, errors.New("point index out of range")
+	laszipPoint, err := lf.readPoint(pointIndex)
+	if err != nil {
+		return nil, err
 	}
 	
-	// For now, we'll read points sequentially
-	// TODO: Implement seeking to specific point indices
+	// Convert LASzip point to lidario format
+	return lf.convertPoint(laszipPoint), nil
+}
+
+// readPoint reads the point with the given index, seeking if necessary. The
+// caller must hold the lock.
+func (lf *LazFile) readPoint(pointIndex int) (*LaszipPoint, error) {
+	if lf.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	// A streaming-written file declares zero points, in which case points are
+	// read sequentially until LASzip signals the end of the data.
+	if !lf.fastMode && (pointIndex < 0 || (uint64(pointIndex) >= lf.GetPointCount64() && !lf.reader.IsStreaming())) {
+		return nil, fmt.Errorf("%w: %v", ErrPointOutOfRange, pointIndex)
+	}
+	
+	// Points are decompressed sequentially; seek when reading out of order.
 	if pointIndex != lf.currentPoint {
-		return nil, errors.New("random access not yet implemented for LAZ files")
+		if err := lf.reader.SeekPoint(uint64(pointIndex)); err != nil {
+			return nil, fmt.Errorf("failed to seek to point %v: %w", pointIndex, err)
+		}
+		lf.currentPoint = pointIndex
 	}
 	
 	// Read the next point
 	if err := lf.reader.ReadPoint(); err != nil {
-		return nil, fmt.Errorf("failed to read point: %v", err)
+		return nil, fmt.Errorf("failed to read point: %w", err)
 	}
 	
 	laszipPoint := lf.reader.GetPoint()
@@ -125,9 +293,7 @@ func (lf *LazFile) LasPoint(pointIndex int) (LasPointer, error) {
 	}
 	
 	lf.currentPoint++
-	
-	// Convert LASzip point to lidario format
-	return lf.convertPoint(laszipPoint), nil
+	return laszipPoint, nil
 }
 
 // convertPoint converts LASzip point to lidario LasPointer
@@ -137,23 +303,40 @@ func (lf *LazFile) convertPoint(lp *LaszipPoint) LasPointer {
 	y := lp.Y
 	z := lp.Z
 	
+	// Formats 6 and above store up to 15 returns and 256 classes, which are
+	// kept in ExtendedPointData; PointRecord0 holds the legacy values.
+	class := lf.mapClass(lp.Classification)
+	extended := ExtendedPointData{
+		ReturnNumber:    lp.ReturnNumber,
+		NumberOfReturns: lp.NumberOfReturns,
+		Classification:  class,
+		ScannerChannel:  lp.ScannerChannel,
+		ScanAngle:       lp.ScanAngle,
+	}
+	returnNumber, numberOfReturns := lp.ReturnNumber, lp.NumberOfReturns
+	if lf.Header.PointFormatID >= 6 {
+		returnNumber, numberOfReturns = legacyReturns(returnNumber, numberOfReturns)
+		if class > 31 {
+			class = 0
+		}
+	}
+
 	// Create bit field from return information
 	// Pack the return information into a single byte
-	returnByte := lp.ReturnNumber | (lp.NumberOfReturns << 3) | (lp.ScanDirectionFlag << 6) | (lp.EdgeOfFlightFlag << 7)
+	returnByte := returnNumber&7 | (numberOfReturns&7)<<3 | (lp.ScanDirectionFlag&1)<<6 | (lp.EdgeOfFlightFlag&1)<<7
 	bitField := PointBitField{
 		Value: returnByte,
 	}
 	
-	// Create classification bit field
-	// Pack classification and flags into a single byte
-	classificationByte := lp.Classification
-	if lp.Classification&0x20 != 0 { // synthetic flag
+	// Pack the class (the low five bits) and the flags into a single byte
+	classificationByte := class & 0x1F
+	if lp.Synthetic {
 		classificationByte |= 0x20
 	}
-	if lp.Classification&0x40 != 0 { // keypoint flag
+	if lp.Keypoint {
 		classificationByte |= 0x40
 	}
-	if lp.Classification&0x80 != 0 { // withheld flag
+	if lp.Withheld {
 		classificationByte |= 0x80
 	}
 	classBitField := ClassificationBitField{
@@ -180,18 +363,120 @@ func (lf *LazFile) convertPoint(lp *LaszipPoint) LasPointer {
 	case 1:
 		return &PointRecord1{PointRecord0: pointRecord, GPSTime: lp.GPSTime}
 	case 2:
-		// TODO: Extract RGB data if available
-		rgb := &RgbData{Red: 0, Green: 0, Blue: 0}
+		rgb := &RgbData{Red: lp.Red, Green: lp.Green, Blue: lp.Blue}
 		return &PointRecord2{PointRecord0: pointRecord, RGB: rgb}
 	case 3:
-		// TODO: Extract RGB data if available
-		rgb := &RgbData{Red: 0, Green: 0, Blue: 0}
+		rgb := &RgbData{Red: lp.Red, Green: lp.Green, Blue: lp.Blue}
 		return &PointRecord3{PointRecord0: pointRecord, GPSTime: lp.GPSTime, RGB: rgb}
+	case 4:
+		return &PointRecord4{PointRecord1: &PointRecord1{PointRecord0: pointRecord, GPSTime: lp.GPSTime}}
+	case 5:
+		rgb := &RgbData{Red: lp.Red, Green: lp.Green, Blue: lp.Blue}
+		return &PointRecord5{PointRecord3: &PointRecord3{PointRecord0: pointRecord, GPSTime: lp.GPSTime, RGB: rgb}}
+	case 6, 9:
+		p := &PointRecord6{PointRecord0: pointRecord, GPSTime: lp.GPSTime, Extended: extended}
+		if lf.Header.PointFormatID == 9 {
+			return &PointRecord9{PointRecord6: p}
+		}
+		return p
+	case 7:
+		rgb := &RgbData{Red: lp.Red, Green: lp.Green, Blue: lp.Blue}
+		return &PointRecord7{PointRecord0: pointRecord, GPSTime: lp.GPSTime, RGB: rgb, Extended: extended}
+	case 8, 10:
+		// LASzip stores the NIR channel as the fourth colour value.
+		rgb := &RgbData{Red: lp.Red, Green: lp.Green, Blue: lp.Blue}
+		p := &PointRecord8{PointRecord0: pointRecord, GPSTime: lp.GPSTime, RGB: rgb, NIR: lp.NIR, Extended: extended}
+		if lf.Header.PointFormatID == 10 {
+			return &PointRecord10{PointRecord8: p}
+		}
+		return p
 	default:
+		lf.fallbackOnce.Do(func() {
+			lf.log().Warn("unsupported point format, points are returned as PointRecord0",
+				"file", lf.fileName, "point_format", lf.Header.PointFormatID)
+		})
 		return pointRecord
 	}
 }
 
+// ReadPoints reads count consecutive points starting at start. The points are
+// decompressed in batches with a single cgo call each (see
+// LaszipReader.ReadPointsInto), which is typically several times faster than
+// calling LasPoint for each point. Fewer points are returned if the end of the
+// file is reached.
+func (lf *LazFile) ReadPoints(start, count int) ([]LasPointer, error) {
+	return lf.ReadPointsCtx(context.Background(), start, count)
+}
+
+// ReadPointsCtx is like ReadPoints, but stops with the error of ctx, which is
+// checked before each batch of points is decompressed, once ctx is cancelled
+// or its deadline passes. The points read so far are returned with the error.
+func (lf *LazFile) ReadPointsCtx(ctx context.Context, start, count int) ([]LasPointer, error) {
+	if lf.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	lf.Lock()
+	defer lf.Unlock()
+
+	if count < 0 {
+		return nil, errors.New("the point count must not be negative")
+	}
+	if start < 0 || (start >= int(lf.Header.NumberPoints) && !lf.reader.IsStreaming()) {
+		return nil, fmt.Errorf("%w: %v", ErrPointOutOfRange, start)
+	}
+	if start != lf.currentPoint {
+		if err := lf.reader.SeekPoint(uint64(start)); err != nil {
+			return nil, fmt.Errorf("failed to seek to point %v: %w", start, err)
+		}
+		lf.currentPoint = start
+	}
+
+	const batchSize = 4096
+	if len(lf.batch) == 0 {
+		lf.batch = make([]LaszipPoint, batchSize)
+	}
+	points := make([]LasPointer, 0, count)
+	prog := newProgress(lf.progress, count)
+	for len(points) < count {
+		if err := ctx.Err(); err != nil {
+			return points, err
+		}
+		buf := lf.batch
+		if remaining := count - len(points); remaining < len(buf) {
+			buf = buf[:remaining]
+		}
+		n, err := lf.reader.ReadPointsInto(buf)
+		for i := 0; i < n; i++ {
+			points = append(points, lf.convertPoint(&buf[i]))
+		}
+		lf.currentPoint += n
+		prog.add(n)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return points, fmt.Errorf("failed to read points: %w", err)
+		}
+	}
+	prog.finish()
+	return points, nil
+}
+
+// Rewind positions the file at its first point, which is cheaper than closing
+// and reopening it to make another pass over the points.
+func (lf *LazFile) Rewind() error {
+	if lf.fileMode == "rh" {
+		return errHeaderOnly
+	}
+	lf.Lock()
+	defer lf.Unlock()
+	if err := lf.reader.Reset(); err != nil {
+		return fmt.Errorf("failed to rewind: %w", err)
+	}
+	lf.currentPoint = 0
+	return nil
+}
+
 // GetXYZ gets the coordinates of a specific point
 func (lf *LazFile) GetXYZ(pointIndex int) (float64, float64, float64, error) {
 	point, err := lf.LasPoint(pointIndex)
@@ -203,6 +488,68 @@ func (lf *LazFile) GetXYZ(pointIndex int) (float64, float64, float64, error) {
 	return pointData.X, pointData.Y, pointData.Z, nil
 }
 
+// GetXYZRange gets the coordinates of count consecutive points starting at
+// start. The file is positioned at start once and the points are then read
+// sequentially in batches, as ReadPoints does, without building a LasPointer
+// for each point. The range must lie within the file.
+func (lf *LazFile) GetXYZRange(start, count int) ([][3]float64, error) {
+	if lf.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	if count < 0 {
+		return nil, errors.New("the point count must not be negative")
+	}
+	lf.Lock()
+	defer lf.Unlock()
+	if start < 0 || start+count > lf.Header.NumberPoints {
+		return nil, fmt.Errorf("%w: [%v, %v) with %v points", ErrPointOutOfRange, start, start+count, lf.Header.NumberPoints)
+	}
+	if start != lf.currentPoint {
+		if err := lf.reader.SeekPoint(uint64(start)); err != nil {
+			return nil, fmt.Errorf("failed to seek to point %v: %w", start, err)
+		}
+		lf.currentPoint = start
+	}
+
+	const batchSize = 4096
+	if len(lf.batch) == 0 {
+		lf.batch = make([]LaszipPoint, batchSize)
+	}
+	coords := make([][3]float64, 0, count)
+	for len(coords) < count {
+		buf := lf.batch
+		if remaining := count - len(coords); remaining < len(buf) {
+			buf = buf[:remaining]
+		}
+		n, err := lf.reader.ReadPointsInto(buf)
+		for i := 0; i < n; i++ {
+			coords = append(coords, [3]float64{buf[i].X, buf[i].Y, buf[i].Z})
+		}
+		lf.currentPoint += n
+		if err == io.EOF {
+			return coords, fmt.Errorf("%w: the file ended after %v points", ErrCorruptFile, lf.currentPoint)
+		}
+		if err != nil {
+			return coords, fmt.Errorf("failed to read points: %w", err)
+		}
+	}
+	return coords, nil
+}
+
+// GetRawXYZ gets the integer coordinates of a specific point as they are
+// stored in the file, without the rounding of a conversion to floating point.
+// The real-world coordinates are raw * scale factor + offset, e.g.
+// x = rawX * Header.XScaleFactor + Header.XOffset.
+func (lf *LazFile) GetRawXYZ(pointIndex int) (int32, int32, int32, error) {
+	lf.Lock()
+	defer lf.Unlock()
+	p, err := lf.readPoint(pointIndex)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return p.RawX, p.RawY, p.RawZ, nil
+}
+
 // Close closes the LAZ file
 func (lf *LazFile) Close() error {
 	if lf.reader != nil {
@@ -211,20 +558,75 @@ func (lf *LazFile) Close() error {
 	return nil
 }
 
+// SetFastMode enables or disables fast mode, in which LasPoint, GetXYZ and the
+// other methods reading a single point skip the check that the point index
+// lies within the file, for tight loops over trusted indices. The gain is
+// small, as the time to decompress a point dominates; see
+// BenchmarkLazFastMode.
+//
+// Reading a point outside of the file in fast mode is undefined behaviour that
+// callers must not rely on. The reader still refuses to seek or read beyond
+// the compressed data, so the process is not crashed, but which error, if any,
+// is returned is unspecified and may change.
+func (lf *LazFile) SetFastMode(enabled bool) {
+	lf.Lock()
+	defer lf.Unlock()
+	lf.fastMode = enabled
+}
+
+// DisableFinalizer removes the finalizer that closes the file if it is garbage
+// collected without being closed. See LaszipReader.DisableFinalizer.
+func (lf *LazFile) DisableFinalizer() {
+	if lf.reader != nil {
+		lf.reader.DisableFinalizer()
+	}
+}
+
 // GetHeader returns the header for LazFile (implement interface)
 func (lf *LazFile) GetHeader() *LasHeader {
 	return &lf.Header
 }
 
-// GetPointCount returns the point count for LazFile (implement interface)
+// GetPointCount returns the point count for LazFile (implement interface).
+// The count is truncated for files of more than 2^32-1 points; see
+// GetPointCount64.
 func (lf *LazFile) GetPointCount() uint32 {
 	return uint32(lf.Header.NumberPoints)
 }
 
+// GetPointCount64 returns the point count for LazFile (implement interface)
+func (lf *LazFile) GetPointCount64() uint64 {
+	return lf.Header.pointCount()
+}
+
+// GetVLRs returns the variable length records of the file, including their
+// payloads.
+func (lf *LazFile) GetVLRs() []VLR {
+	return lf.VlrData
+}
+
+// GetCRS returns the coordinate reference system of the file, either as WKT
+// or, for files georeferenced with GeoKeys, as an "EPSG:<code>" string. The
+// WKT is preferred when both are present. ErrNoCRS is returned if the file
+// carries neither.
+func (lf *LazFile) GetCRS() (string, error) {
+	return lf.geokeys.crs()
+}
+
 // IsCompressed returns true if this is a compressed LAZ file
 func (lf *LazFile) IsCompressed() bool {
 	return lf.isCompressed
 }
 
+// isCopc returns true if the file carries the COPC info VLR (user ID "copc", record ID 1).
+func (lf *LazFile) isCopc() bool {
+	for _, vlr := range lf.VlrData {
+		if vlr.UserID == "copc" && vlr.RecordID == 1 {
+			return true
+		}
+	}
+	return false
+}
+
 // Ensure LazFile implements LidarFile interface
 var _ LidarFile = (*LazFile)(nil)
\ No newline at end of file
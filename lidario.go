@@ -18,6 +18,13 @@ import (
 // NoData value used when indexing point outside of allowable range.
 var NoData = math.Inf(-1)
 
+// ErrCoordinateOutOfRange is returned when a coordinate cannot be stored as a
+// 32-bit integer using the file's scale factor and offset.
+var ErrCoordinateOutOfRange = errors.New("coordinate out of range for the scale factor and offset")
+
+// errHeaderOnly is returned when point data are requested from a file opened in 'rh' mode.
+var errHeaderOnly = errors.New("The file was opened in 'rh' (read header); data points were therefore not read from the file")
+
 // LasFile is a structure for manipulating LAS files.
 type LasFile struct {
 	fileName               string
@@ -31,6 +38,12 @@ type LasFile struct {
 	rgbData                []RgbData
 	usePointIntensity      bool
 	usePointUserdata       bool
+	intensityScale         float64
+	readBufferSize         int
+	useMmap                bool
+	mapped                 []byte
+	buffered               bool
+	rr                     *recordReader
 	headerIsSet            bool
 	fixedRadiusSearch2DSet bool
 	frs2D                  *fixedRadiusSearch
@@ -39,31 +52,29 @@ type LasFile struct {
 	sync.RWMutex
 }
 
-// NewLidarFile creates a new LidarFile (either LAS or LAZ) based on file type detection
-func NewLidarFile(fileName, fileMode string) (LidarFile, error) {
-	// Detect file type
-	if isLazFile(fileName) {
-		lazFile, err := NewLazFile(fileName, fileMode)
-		if err != nil {
-			return nil, err
-		}
-		return lazFile, nil
-	}
-	
-	// Default to LAS file
-	lasFile, err := NewLasFile(fileName, fileMode)
-	if err != nil {
-		return nil, err
-	}
-	return lasFile, nil
+// NewLidarFile creates a new LidarFile (either LAS or LAZ) based on file type detection.
+// The type is determined from the compression indicators in the file's header rather
+// than from its extension, so a mislabeled file still opens with the right reader.
+// The options configure how LAS files are read; see NewLasFile. It is equivalent to
+// NewLidarFileWithOptions with the given mode and reader options.
+func NewLidarFile(fileName, fileMode string, opts ...ReaderOption) (LidarFile, error) {
+	o := newReaderOptions(opts)
+	return NewLidarFileWithOptions(fileName, Options{Mode: fileMode, ReadBufferSize: o.bufferSize, Mmap: o.mmap,
+		BufferedReads: o.buffered})
 }
 
-// NewLasFile creates a new LasFile structure.
-func NewLasFile(fileName, fileMode string) (*LasFile, error) {
+// NewLasFile creates a new LasFile structure. The options configure how point
+// records are read from the file; see WithReadBufferSize, WithMmap and
+// WithBufferedReads.
+func NewLasFile(fileName, fileMode string, opts ...ReaderOption) (*LasFile, error) {
 	fileMode = strings.ToLower(fileMode)
 	// initialize the VLR array
 	vlrs := []VLR{}
 	las := LasFile{fileName: fileName, fileMode: fileMode, Header: LasHeader{}, VlrData: vlrs}
+	o := newReaderOptions(opts)
+	las.readBufferSize = o.bufferSize
+	las.useMmap = o.mmap
+	las.buffered = o.buffered
 	if las.fileMode == "r" || las.fileMode == "rh" {
 		if err := las.read(); err != nil {
 			return &las, err
@@ -92,12 +103,23 @@ func NewLasFile(fileName, fileMode string) (*LasFile, error) {
 
 // InitializeUsingFile initializes a new LAS file based on another existing file.
 // The function transfers values from the header and the VLRs to the new file.
-func InitializeUsingFile(fileName string, other *LasFile) (*LasFile, error) {
+// An existing file is not replaced unless WithOverwrite(true) is supplied, and
+// the points are written in the format of the other file unless
+// ConvertPointFormat is supplied.
+func InitializeUsingFile(fileName string, other *LasFile, opts ...WriterOption) (*LasFile, error) {
+	o := newWriterOptions(opts)
+	if err := o.check(); err != nil {
+		return nil, err
+	}
+	if err := o.checkOutput(fileName); err != nil {
+		return nil, err
+	}
 	las := LasFile{}
 	las.fileName = fileName
 	las.fileMode = "w"
 	las.usePointIntensity = true
 	las.usePointUserdata = true
+	las.intensityScale = o.intensityScale
 
 	var err error
 	if las.f, err = os.Create(las.fileName); err != nil {
@@ -105,6 +127,9 @@ func InitializeUsingFile(fileName string, other *LasFile) (*LasFile, error) {
 	}
 
 	las.AddHeader(other.Header)
+	if o.convertFormat {
+		las.Header.PointFormatID = o.pointFormat
+	}
 
 	// Copy the VLRs
 	for _, vlr := range other.VlrData {
@@ -113,11 +138,11 @@ func InitializeUsingFile(fileName string, other *LasFile) (*LasFile, error) {
 
 	// initialize the point, gps, and rgb data slices and set the capacity to that of the other file
 	las.pointData = make([]PointRecord0, 0, other.Header.NumberPoints)
-	if other.Header.PointFormatID == 1 || other.Header.PointFormatID == 3 {
+	if las.Header.PointFormatID == 1 || las.Header.PointFormatID == 3 {
 		las.gpsData = make([]float64, 0, other.Header.NumberPoints)
 	}
 
-	if other.Header.PointFormatID == 2 || other.Header.PointFormatID == 3 {
+	if las.Header.PointFormatID == 2 || las.Header.PointFormatID == 3 {
 		las.rgbData = make([]RgbData, 0, other.Header.NumberPoints)
 	}
 
@@ -135,6 +160,7 @@ func (las *LasFile) AddHeader(header LasHeader) error {
 	las.Header = header
 	las.Header.NumberOfVLRs = 0
 	las.Header.NumberPoints = 0
+	las.Header.NumberPointsByReturn = [15]int{}
 	las.Header.VersionMajor = 1
 	las.Header.VersionMinor = 3
 
@@ -189,6 +215,8 @@ func (las *LasFile) AddLasPoint(p LasPointer) error {
 	}
 	las.Lock()
 	// defer las.Unlock()
+	// The GPS times and colours are stored by the format of the file.
+	p = convertPointFormat(p, las.Header.PointFormatID)
 	pd := p.PointData()
 	las.pointData = append(las.pointData, *pd)
 
@@ -256,6 +284,7 @@ func (las *LasFile) AddLasPoints(points []LasPointer) error {
 	var val float64
 	var whichReturn uint8
 	for _, p := range points {
+		p = convertPointFormat(p, las.Header.PointFormatID)
 		pd = *p.PointData()
 		las.pointData = append(las.pointData, pd)
 
@@ -324,8 +353,12 @@ func (las *LasFile) Close() error {
 		return errors.New("the LAS reader is nil")
 	}
 	if las.fileMode == "w" {
-		las.write()
+		if err := las.write(); err != nil {
+			las.f.Close()
+			return err
+		}
 	}
+	las.unmapFile()
 	return las.f.Close()
 }
 
@@ -334,6 +367,15 @@ func (las *LasFile) GetXYZ(index int) (float64, float64, float64, error) {
 	if index < 0 || index >= las.Header.NumberPoints {
 		return NoData, NoData, NoData, errors.New("Index outside of allowable range")
 	}
+	if las.pointData == nil {
+		if las.mapped != nil {
+			return las.mappedXYZ(index)
+		}
+		if las.buffered {
+			return las.bufferedXYZ(index)
+		}
+		return NoData, NoData, NoData, errHeaderOnly
+	}
 	return las.pointData[index].X, las.pointData[index].Y, las.pointData[index].Z, nil
 }
 
@@ -366,6 +408,34 @@ func (las *LasFile) LasPoint(index int) (LasPointer, error) {
 	}
 }
 
+// GetPointsInto reads up to count points, starting at index start, into the
+// caller's slice, filling it up to its capacity. It returns the number of
+// points read. Reusing dst across calls avoids reallocating a result slice for
+// each windowed read.
+func (las *LasFile) GetPointsInto(start, count int, dst []LasPointer) (int, error) {
+	if count < 0 {
+		return 0, errors.New("the point count must not be negative")
+	}
+	if start < 0 || start >= las.Header.NumberPoints {
+		return 0, errors.New("Index outside of allowable range")
+	}
+	if count > cap(dst) {
+		count = cap(dst)
+	}
+	if start+count > las.Header.NumberPoints {
+		count = las.Header.NumberPoints - start
+	}
+	dst = dst[:cap(dst)]
+	for i := 0; i < count; i++ {
+		p, err := las.LasPoint(start + i)
+		if err != nil {
+			return i, err
+		}
+		dst[i] = p
+	}
+	return count, nil
+}
+
 func (las *LasFile) read() error {
 	var err error
 	if las.f, err = os.Open(las.fileName); err != nil {
@@ -377,8 +447,11 @@ func (las *LasFile) read() error {
 	if err := las.readVLRs(); err != nil {
 		return err
 	}
+	las.mapFile()
 	if las.fileMode != "rh" {
 		if err := las.readPoints(); err != nil {
+			// The caller does not close a file that failed to open.
+			las.unmapFile()
 			return err
 		}
 	}
@@ -388,8 +461,8 @@ func (las *LasFile) read() error {
 func (las *LasFile) readHeader() error {
 	las.Lock()
 	defer las.Unlock()
-	b := make([]byte, 243)
-	if _, err := las.f.ReadAt(b[0:243], 0); err != nil && err != io.EOF {
+	b := make([]byte, 375)
+	if _, err := las.f.ReadAt(b[0:375], 0); err != nil && err != io.EOF {
 		return err
 	}
 
@@ -482,9 +555,27 @@ func (las *LasFile) readHeader() error {
 	offset += 8
 	las.Header.MinZ = math.Float64frombits(binary.LittleEndian.Uint64(b[offset : offset+8]))
 	offset += 8
-	if las.Header.VersionMajor == 1 && las.Header.VersionMinor == 3 {
+
+	// The tail of the header depends on the version. Only read the fields that
+	// the declared version and header size say are actually present; otherwise
+	// the bytes belong to the VLRs or point records and would be read as garbage.
+	if las.Header.versionAtLeast(1, 3) && las.Header.HeaderSize >= int(offset)+8 {
 		las.Header.WaveformDataStart = binary.LittleEndian.Uint64(b[offset : offset+8])
 	}
+	offset += 8
+	if las.Header.versionAtLeast(1, 4) && las.Header.HeaderSize >= int(offset)+140 {
+		las.Header.StartOfFirstEVLR = binary.LittleEndian.Uint64(b[offset : offset+8])
+		offset += 8
+		las.Header.NumberOfEVLRs = int(binary.LittleEndian.Uint32(b[offset : offset+4]))
+		offset += 4
+		las.Header.ExtendedNumberPoints = binary.LittleEndian.Uint64(b[offset : offset+8])
+		offset += 8
+		for i := 0; i < 15; i++ {
+			// The 64-bit counts supersede the legacy 32-bit counts read above.
+			las.Header.NumberPointsByReturn[i] = int(binary.LittleEndian.Uint64(b[offset : offset+8]))
+			offset += 8
+		}
+	}
 
 	return nil
 }
@@ -526,16 +617,7 @@ func (las *LasFile) readVLRs() error {
 			vlr.BinaryData[j] = b[offset]
 			offset++
 		}
-		if vlr.RecordID == 34735 {
-			// GeoKey directory
-			las.geokeys.addKeyDirectory(vlr.BinaryData)
-		} else if vlr.RecordID == 34736 {
-			// Double GeoKey parameters
-			las.geokeys.addDoubleParams(vlr.BinaryData)
-		} else if vlr.RecordID == 34737 {
-			// ASCII GeoKey parameters
-			las.geokeys.addASCIIParams(vlr.BinaryData)
-		}
+		las.geokeys.addVLR(vlr)
 		las.VlrData[i] = vlr
 	}
 
@@ -555,28 +637,12 @@ func (las *LasFile) readPoints() error {
 
 	// Estimate how many bytes are used to store the points
 	pointsLength := las.Header.NumberPoints * las.Header.PointRecordLength
-	b := make([]byte, pointsLength)
-	if _, err := las.f.ReadAt(b, int64(las.Header.OffsetToPoints)); err != nil && err != io.EOF {
+	b, err := las.pointBytes(pointsLength)
+	if err != nil {
 		return err
 	}
 
-	// Intensity and userdata are both optional. Figure out if they need to be read.
-	// The only way to do this is to compare the point record length by point format
-	recLengths := [4][4]int{{20, 18, 19, 17}, {28, 26, 27, 25}, {26, 24, 25, 23}, {34, 32, 33, 31}}
-
-	if las.Header.PointRecordLength == recLengths[las.Header.PointFormatID][0] {
-		las.usePointIntensity = true
-		las.usePointUserdata = true
-	} else if las.Header.PointRecordLength == recLengths[las.Header.PointFormatID][1] {
-		las.usePointIntensity = false
-		las.usePointUserdata = true
-	} else if las.Header.PointRecordLength == recLengths[las.Header.PointFormatID][2] {
-		las.usePointIntensity = true
-		las.usePointUserdata = false
-	} else if las.Header.PointRecordLength == recLengths[las.Header.PointFormatID][3] {
-		las.usePointIntensity = false
-		las.usePointUserdata = false
-	}
+	las.usePointIntensity, las.usePointUserdata = las.Header.optionalFields()
 
 	numCPUs := runtime.NumCPU()
 	var wg sync.WaitGroup
@@ -646,6 +712,35 @@ func (las *LasFile) readPoints() error {
 	return nil
 }
 
+// optionalFields reports whether the point records hold the intensity and
+// user data fields, which are both optional. The only way to tell is to
+// compare the point record length with those of the point format.
+func (h *LasHeader) optionalFields() (intensity, userData bool) {
+	if h.PointFormatID > 3 {
+		return false, false
+	}
+	recLengths := [4][4]int{{20, 18, 19, 17}, {28, 26, 27, 25}, {26, 24, 25, 23}, {34, 32, 33, 31}}
+	switch h.PointRecordLength {
+	case recLengths[h.PointFormatID][0]:
+		return true, true
+	case recLengths[h.PointFormatID][1]:
+		return false, true
+	case recLengths[h.PointFormatID][2]:
+		return true, false
+	}
+	return false, false
+}
+
+// checkCoordinateRange returns ErrCoordinateOutOfRange if the value cannot be
+// stored as an int32 using the scale factor and offset.
+func checkCoordinateRange(axis string, value, offset, scale float64) error {
+	v := (value - offset) / scale
+	if v > math.MaxInt32 || v < math.MinInt32 || math.IsNaN(v) {
+		return fmt.Errorf("%w: %v value %v (scale %v, offset %v)", ErrCoordinateOutOfRange, axis, value, scale, offset)
+	}
+	return nil
+}
+
 func (las *LasFile) write() error {
 	las.Lock()
 	defer las.Unlock()
@@ -682,6 +777,24 @@ func (las *LasFile) write() error {
 		las.Header.ZScaleFactor = dec
 	}
 
+	// The extremes are the only values that can overflow the int32 storage.
+	extremes := []struct {
+		axis                 string
+		min, max, off, scale float64
+	}{
+		{"X", las.Header.MinX, las.Header.MaxX, las.Header.XOffset, las.Header.XScaleFactor},
+		{"Y", las.Header.MinY, las.Header.MaxY, las.Header.YOffset, las.Header.YScaleFactor},
+		{"Z", las.Header.MinZ, las.Header.MaxZ, las.Header.ZOffset, las.Header.ZScaleFactor},
+	}
+	for _, e := range extremes {
+		if err := checkCoordinateRange(e.axis, e.min, e.off, e.scale); err != nil {
+			return err
+		}
+		if err := checkCoordinateRange(e.axis, e.max, e.off, e.scale); err != nil {
+			return err
+		}
+	}
+
 	var err error
 
 	if las.f == nil {
@@ -860,6 +973,12 @@ func (las *LasFile) write() error {
 	//////////////////////////////////
 	// Write the points to the file //
 	//////////////////////////////////
+	if las.intensityScale != 0 && las.intensityScale != 1 {
+		for i := range las.pointData {
+			las.pointData[i].Intensity = scaleIntensity(las.pointData[i].Intensity, las.intensityScale)
+		}
+	}
+
 	numCPUs := runtime.NumCPU()
 	var wg sync.WaitGroup
 	blockSize := las.Header.NumberPoints / numCPUs
@@ -1411,6 +1530,14 @@ func (las *LasFile) PrintGeokeys() string {
 	return las.geokeys.interpretGeokeys()
 }
 
+// GetCRS returns the coordinate reference system of the file, either as WKT
+// or, for files georeferenced with GeoKeys, as an "EPSG:<code>" string. The
+// WKT is preferred when both are present. ErrNoCRS is returned if the file
+// carries neither.
+func (las *LasFile) GetCRS() (string, error) {
+	return las.geokeys.crs()
+}
+
 // LasHeader is a LAS file header structure.
 type LasHeader struct {
 	FileSignature        string
@@ -1432,7 +1559,7 @@ type LasHeader struct {
 	PointFormatID        byte
 	PointRecordLength    int
 	NumberPoints         int
-	NumberPointsByReturn [5]int
+	NumberPointsByReturn [15]int // slots 6-15 are only used by LAS 1.4 files
 	XScaleFactor         float64
 	YScaleFactor         float64
 	ZScaleFactor         float64
@@ -1446,9 +1573,28 @@ type LasHeader struct {
 	MaxZ                 float64
 	MinZ                 float64
 	WaveformDataStart    uint64
+	// LAS 1.4 only
+	StartOfFirstEVLR     uint64
+	NumberOfEVLRs        int
+	ExtendedNumberPoints uint64
 	projectIDUsed        bool
 }
 
+// versionAtLeast returns true if the header's LAS version is at least major.minor.
+func (h LasHeader) versionAtLeast(major, minor byte) bool {
+	return h.VersionMajor > major || (h.VersionMajor == major && h.VersionMinor >= minor)
+}
+
+// pointCount returns the number of point records. LAS 1.4 stores a 64-bit
+// count, which supersedes the legacy 32-bit count; the latter is zero in a
+// file of more than 2^32-1 points.
+func (h LasHeader) pointCount() uint64 {
+	if h.versionAtLeast(1, 4) && h.ExtendedNumberPoints > 0 {
+		return h.ExtendedNumberPoints
+	}
+	return uint64(h.NumberPoints)
+}
+
 func (h LasHeader) String() string {
 	var buffer bytes.Buffer
 	// buffer.WriteString("Las File Header:\n")
@@ -1487,9 +1633,13 @@ func (h LasHeader) String() string {
 	buffer.WriteString(s)
 	s = fmt.Sprintf("Number of Points: %v\n", h.NumberPoints)
 	buffer.WriteString(s)
-	s = fmt.Sprintf("Number of Points by Return: [%v, %v, %v, %v, %v]\n", h.NumberPointsByReturn[0],
-		h.NumberPointsByReturn[1], h.NumberPointsByReturn[2], h.NumberPointsByReturn[3],
-		h.NumberPointsByReturn[4])
+	if h.versionAtLeast(1, 4) {
+		s = fmt.Sprintf("Number of Points by Return: %v\n", h.NumberPointsByReturn)
+	} else {
+		s = fmt.Sprintf("Number of Points by Return: [%v, %v, %v, %v, %v]\n", h.NumberPointsByReturn[0],
+			h.NumberPointsByReturn[1], h.NumberPointsByReturn[2], h.NumberPointsByReturn[3],
+			h.NumberPointsByReturn[4])
+	}
 	buffer.WriteString(s)
 	s = fmt.Sprintf("X Scale Factor: %f\n", h.XScaleFactor)
 	buffer.WriteString(s)
@@ -1517,6 +1667,14 @@ func (h LasHeader) String() string {
 	buffer.WriteString(s)
 	s = fmt.Sprintf("Waveform Data Start: %v\n", h.WaveformDataStart)
 	buffer.WriteString(s)
+	if h.versionAtLeast(1, 4) {
+		s = fmt.Sprintf("Start of First EVLR: %v\n", h.StartOfFirstEVLR)
+		buffer.WriteString(s)
+		s = fmt.Sprintf("Number of EVLRs: %v\n", h.NumberOfEVLRs)
+		buffer.WriteString(s)
+		s = fmt.Sprintf("Extended Number of Points: %v\n", h.ExtendedNumberPoints)
+		buffer.WriteString(s)
+	}
 
 	return buffer.String()
 }
@@ -1824,6 +1982,234 @@ func (p *PointRecord3) RgbData() *RgbData {
 	return p.RGB
 }
 
+// PointRecord4 is a LAS point record type 4, which adds wave packets to
+// point record type 1. The wave packets are read with LazFile.Waveform.
+type PointRecord4 struct {
+	*PointRecord1
+}
+
+// Format returns the point format number.
+func (p *PointRecord4) Format() uint8 {
+	return 4
+}
+
+// PointRecord5 is a LAS point record type 5, which adds wave packets to
+// point record type 3. The wave packets are read with LazFile.Waveform.
+type PointRecord5 struct {
+	*PointRecord3
+}
+
+// Format returns the point format number.
+func (p *PointRecord5) Format() uint8 {
+	return 5
+}
+
+// ExtendedPointData holds the fields of point formats 6-10 that do not fit in
+// PointRecord0: up to 15 returns, 256 classes, the scanner channel and the
+// scan angle at a 0.006 degree resolution. The BitField and ClassBitField of
+// the PointRecord0 of these formats hold the legacy values, in which classes
+// above 31 are 0 and return numbers above 7 are reduced as LASzip does.
+type ExtendedPointData struct {
+	ReturnNumber    uint8
+	NumberOfReturns uint8
+	Classification  uint8
+	ScannerChannel  uint8
+	ScanAngle       float64 // in degrees
+}
+
+// returns returns the return number and number of returns, taking a zero
+// for 1 as PointBitField does.
+func (e *ExtendedPointData) returns() (uint8, uint8) {
+	rn, nr := e.ReturnNumber, e.NumberOfReturns
+	if rn == 0 {
+		rn = 1
+	}
+	if nr == 0 {
+		nr = 1
+	}
+	return rn, nr
+}
+
+// IsLateReturn returns true if the point is a last return.
+func (e *ExtendedPointData) IsLateReturn() bool {
+	rn, nr := e.returns()
+	return rn == nr
+}
+
+// IsFirstReturn returns true if the point is a first return.
+func (e *ExtendedPointData) IsFirstReturn() bool {
+	rn, nr := e.returns()
+	return rn == 1 && nr > 1
+}
+
+// IsIntermediateReturn returns true if the point is an intermediate return.
+func (e *ExtendedPointData) IsIntermediateReturn() bool {
+	rn, nr := e.returns()
+	return rn > 1 && rn < nr
+}
+
+// legacyReturns reduces the extended return number and number of returns to
+// the 3-bit legacy fields, as LASzip does: the last returns map to 7, 6 and 5,
+// so that the last return remains last.
+func legacyReturns(returnNumber, numberOfReturns uint8) (uint8, uint8) {
+	if numberOfReturns <= 7 {
+		if returnNumber > 7 {
+			returnNumber = 7
+		}
+		return returnNumber, numberOfReturns
+	}
+	if returnNumber <= 4 {
+		return returnNumber, 7
+	}
+	switch d := int(numberOfReturns) - int(returnNumber); {
+	case d <= 0:
+		return 7, 7
+	case d >= 3:
+		return 4, 7
+	default:
+		return uint8(7 - d), 7
+	}
+}
+
+// PointRecord6 is a LAS point record type 6, the core format of LAS 1.4,
+// which stores the GPS time.
+type PointRecord6 struct {
+	*PointRecord0
+	GPSTime  float64
+	Extended ExtendedPointData
+}
+
+// IsLateReturn returns true if the point is a last return.
+func (p *PointRecord6) IsLateReturn() bool {
+	return p.Extended.IsLateReturn()
+}
+
+// IsFirstReturn returns true if the point is a first return.
+func (p *PointRecord6) IsFirstReturn() bool {
+	return p.Extended.IsFirstReturn()
+}
+
+// IsIntermediateReturn returns true if the point is an intermediate return.
+func (p *PointRecord6) IsIntermediateReturn() bool {
+	return p.Extended.IsIntermediateReturn()
+}
+
+// Format returns the point format number.
+func (p *PointRecord6) Format() uint8 {
+	return 6
+}
+
+// GpsTimeData returns the GPS time data for the LAS point.
+func (p *PointRecord6) GpsTimeData() float64 {
+	return p.GPSTime
+}
+
+// RgbData returns the RGB colour data for the LAS point.
+func (p *PointRecord6) RgbData() *RgbData {
+	return &RgbData{}
+}
+
+// PointRecord7 is a LAS point record type 7, which adds RGB colour to point
+// record type 6.
+type PointRecord7 struct {
+	*PointRecord0
+	GPSTime  float64
+	RGB      *RgbData
+	Extended ExtendedPointData
+}
+
+// IsLateReturn returns true if the point is a last return.
+func (p *PointRecord7) IsLateReturn() bool {
+	return p.Extended.IsLateReturn()
+}
+
+// IsFirstReturn returns true if the point is a first return.
+func (p *PointRecord7) IsFirstReturn() bool {
+	return p.Extended.IsFirstReturn()
+}
+
+// IsIntermediateReturn returns true if the point is an intermediate return.
+func (p *PointRecord7) IsIntermediateReturn() bool {
+	return p.Extended.IsIntermediateReturn()
+}
+
+// Format returns the point format number.
+func (p *PointRecord7) Format() uint8 {
+	return 7
+}
+
+// GpsTimeData returns the GPS time data for the LAS point.
+func (p *PointRecord7) GpsTimeData() float64 {
+	return p.GPSTime
+}
+
+// RgbData returns the RGB colour data for the LAS point.
+func (p *PointRecord7) RgbData() *RgbData {
+	return p.RGB
+}
+
+// PointRecord8 is a LAS point record type 8, which adds a near-infrared
+// channel to point record type 7.
+type PointRecord8 struct {
+	*PointRecord0
+	GPSTime  float64
+	RGB      *RgbData
+	NIR      uint16
+	Extended ExtendedPointData
+}
+
+// IsLateReturn returns true if the point is a last return.
+func (p *PointRecord8) IsLateReturn() bool {
+	return p.Extended.IsLateReturn()
+}
+
+// IsFirstReturn returns true if the point is a first return.
+func (p *PointRecord8) IsFirstReturn() bool {
+	return p.Extended.IsFirstReturn()
+}
+
+// IsIntermediateReturn returns true if the point is an intermediate return.
+func (p *PointRecord8) IsIntermediateReturn() bool {
+	return p.Extended.IsIntermediateReturn()
+}
+
+// Format returns the point format number.
+func (p *PointRecord8) Format() uint8 {
+	return 8
+}
+
+// GpsTimeData returns the GPS time data for the LAS point.
+func (p *PointRecord8) GpsTimeData() float64 {
+	return p.GPSTime
+}
+
+// RgbData returns the RGB colour data for the LAS point.
+func (p *PointRecord8) RgbData() *RgbData {
+	return p.RGB
+}
+
+// PointRecord9 is a LAS point record type 9, which adds wave packets to
+// point record type 6. The wave packets are read with LazFile.Waveform.
+type PointRecord9 struct {
+	*PointRecord6
+}
+
+// Format returns the point format number.
+func (p *PointRecord9) Format() uint8 {
+	return 9
+}
+
+// PointRecord10 is a LAS point record type 10, which adds wave packets to
+// point record type 8. The wave packets are read with LazFile.Waveform.
+type PointRecord10 struct {
+	*PointRecord8
+}
+
+// Format returns the point format number.
+func (p *PointRecord10) Format() uint8 {
+	return 10
+}
+
 // PointBitField is a point record bit field
 type PointBitField struct {
 	Value byte
@@ -1840,7 +2226,7 @@ func (p *PointBitField) ReturnNumber() byte {
 
 // NumberOfReturns returns the number of returns of the point
 func (p *PointBitField) NumberOfReturns() byte {
-	ret := (p.Value & byte(56))
+	ret := (p.Value & byte(56)) >> 3
 	if ret == 0 {
 		ret = 1
 	}
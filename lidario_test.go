@@ -1,8 +1,11 @@
 package lidario
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -111,3 +114,169 @@ func TestWriteLasFile(t *testing.T) {
 	}
 
 }
+
+// createTestLasFile writes the points to a new LAS file in a temporary
+// directory and then re-opens that file for reading.
+func createTestLasFile(t *testing.T, format byte, points []LasPointer) *LasFile {
+	t.Helper()
+	fileName := filepath.Join(t.TempDir(), "synthetic.las")
+	lf, err := NewLasFile(fileName, "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf.usePointIntensity = true
+	lf.usePointUserdata = true
+	if err = lf.AddHeader(LasHeader{PointFormatID: format, projectIDUsed: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err = lf.AddLasPoints(points); err != nil {
+		t.Fatal(err)
+	}
+	if err = lf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err = NewLasFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lf.Close() })
+	return lf
+}
+
+func TestReadHeaderVersionTail(t *testing.T) {
+	lf := createTestLasFile(t, 0, []LasPointer{
+		&PointRecord0{X: 1, Y: 2, Z: 3, Intensity: 65535, UserData: 255, PointSourceID: 65535},
+		&PointRecord0{X: 4, Y: 5, Z: 6, Intensity: 65535, UserData: 255, PointSourceID: 65535},
+	})
+	lf.Close()
+
+	// Relabel the file as LAS 1.2. The bytes following the 1.2 header are
+	// point records and must not be interpreted as the 1.4 header fields.
+	f, err := os.OpenFile(lf.fileName, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.WriteAt([]byte{1, 2}, 24); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	lf, err = NewLasFile(lf.fileName, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if lf.Header.VersionMinor != 2 {
+		t.Fatalf("expected a LAS 1.2 file, got 1.%v", lf.Header.VersionMinor)
+	}
+	if lf.Header.WaveformDataStart != 0 || lf.Header.StartOfFirstEVLR != 0 || lf.Header.NumberOfEVLRs != 0 ||
+		lf.Header.ExtendedNumberPoints != 0 {
+		t.Errorf("expected the 1.3/1.4 header fields to be zero for a LAS 1.2 file:\n%v", lf.Header)
+	}
+	for i := 5; i < 15; i++ {
+		if lf.Header.NumberPointsByReturn[i] != 0 {
+			t.Errorf("expected points by return %v to be zero, got %v", i+1, lf.Header.NumberPointsByReturn[i])
+		}
+	}
+}
+
+func TestNumberPointsByReturnLegacy(t *testing.T) {
+	lf, err := NewLasFile("testdata/sample.las", "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if len(lf.Header.NumberPointsByReturn) != 15 {
+		t.Fatalf("expected 15 return slots, got %v", len(lf.Header.NumberPointsByReturn))
+	}
+	if lf.Header.NumberPointsByReturn[0] == 0 {
+		t.Error("expected the first return count to be read from the legacy header")
+	}
+	for i := 5; i < 15; i++ {
+		if lf.Header.NumberPointsByReturn[i] != 0 {
+			t.Errorf("expected slot %v of a legacy file to be zero, got %v", i+1, lf.Header.NumberPointsByReturn[i])
+		}
+	}
+}
+
+func TestRawHeaderBytes(t *testing.T) {
+	lf, err := NewLasFile("testdata/sample.las", "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	b, err := lf.RawHeaderBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != lf.Header.HeaderSize {
+		t.Errorf("expected %v header bytes, got %v", lf.Header.HeaderSize, len(b))
+	}
+	if string(b[0:4]) != "LASF" {
+		t.Errorf("expected the header to start with LASF, got %q", b[0:4])
+	}
+}
+
+func TestGetPointsInto(t *testing.T) {
+	var points []LasPointer
+	for i := 0; i < 25; i++ {
+		points = append(points, &PointRecord0{X: float64(i), Y: 1, Z: 1})
+	}
+	lf := createTestLasFile(t, 0, points)
+
+	dst := make([]LasPointer, 0, 10)
+	for _, start := range []int{0, 10, 20, 5} {
+		n, err := lf.GetPointsInto(start, 10, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := 10
+		if start == 20 {
+			expected = 5
+		}
+		if n != expected {
+			t.Errorf("start %v: expected %v points, got %v", start, expected, n)
+		}
+		window := dst[:n]
+		for i, p := range window {
+			if x := p.PointData().X; x != float64(start+i) {
+				t.Errorf("start %v: expected point %v to have X %v, got %v", start, i, start+i, x)
+			}
+		}
+	}
+
+	if _, err := lf.GetPointsInto(25, 10, dst); err == nil {
+		t.Error("expected an error for a start beyond the last point")
+	}
+	if _, err := lf.GetPointsInto(0, -1, dst); err == nil {
+		t.Error("expected an error for a negative count")
+	}
+}
+
+func TestCoordinateOutOfRange(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "overflow.las")
+	lf, err := NewLasFile(fileName, "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = lf.AddHeader(LasHeader{PointFormatID: 0, projectIDUsed: true}); err != nil {
+		t.Fatal(err)
+	}
+	// With a scale factor of 0.0001 the X extent spans far more than the
+	// int32 range of stored values.
+	points := []LasPointer{
+		&PointRecord0{X: 0, Y: 0, Z: 0},
+		&PointRecord0{X: 1000000, Y: 1, Z: 1},
+	}
+	if err = lf.AddLasPoints(points); err != nil {
+		t.Fatal(err)
+	}
+	err = lf.Close()
+	if !errors.Is(err, ErrCoordinateOutOfRange) {
+		t.Fatalf("expected ErrCoordinateOutOfRange, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "1e+06") {
+		t.Errorf("the error does not report the offending value: %v", err)
+	}
+}
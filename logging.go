@@ -0,0 +1,60 @@
+package lidario
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// discardLogger is used by files that were not given a logger.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// loggerOrDiscard returns l, or a logger that discards its records if l is nil.
+func loggerOrDiscard(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return discardLogger
+	}
+	return l
+}
+
+// vlrProblem describes why the payload of a GeoKey VLR cannot be parsed, or
+// returns an empty string if it can.
+func vlrProblem(vlr VLR) string {
+	switch vlr.RecordID {
+	case 34735:
+		if len(vlr.BinaryData)%2 != 0 {
+			return fmt.Sprintf("the GeoKey directory of %v bytes is not a whole number of 16-bit values", len(vlr.BinaryData))
+		}
+	case 34736:
+		if len(vlr.BinaryData)%8 != 0 {
+			return fmt.Sprintf("the GeoKey double parameters of %v bytes are not a whole number of 64-bit values", len(vlr.BinaryData))
+		}
+	}
+	return ""
+}
+
+// log returns the logger of the file.
+func (lf *LazFile) log() *slog.Logger {
+	return loggerOrDiscard(lf.logger)
+}
+
+// addVLR adds a VLR to the coordinate system of the file. A GeoKey record
+// that is not a whole number of values is logged, and the partial value at
+// its end is ignored.
+func (lf *LazFile) addVLR(vlr VLR) {
+	if problem := vlrProblem(vlr); problem != "" {
+		lf.log().Warn("malformed VLR", "file", lf.fileName,
+			"user_id", vlr.UserID, "record_id", vlr.RecordID, "problem", problem)
+	}
+	lf.geokeys.addVLR(vlr)
+}
+
+// SetLogger sets the logger that receives the diagnostics of the file: the
+// VLRs that cannot be parsed, the points of unsupported formats that are
+// returned as PointRecord0 and the warning logged if the file is garbage
+// collected without being closed. A nil logger discards the diagnostics.
+func (lf *LazFile) SetLogger(l *slog.Logger) {
+	lf.logger = l
+	if lf.reader != nil {
+		lf.reader.logger = l
+	}
+}
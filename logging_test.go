@@ -0,0 +1,69 @@
+package lidario
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// recordingHandler keeps the records it handles.
+type recordingHandler struct {
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func TestLogPointFormatFallback(t *testing.T) {
+	h := &recordingHandler{}
+	lf := &LazFile{fileName: "points.laz"}
+	// Point formats above 10 are not defined.
+	lf.Header.PointFormatID = 11
+	lf.SetLogger(slog.New(h))
+
+	for i := 0; i < 3; i++ {
+		if _, ok := lf.convertPoint(&LaszipPoint{}).(*PointRecord0); !ok {
+			t.Fatal("expected a point of an unsupported format to be returned as a PointRecord0")
+		}
+	}
+	if len(h.records) != 1 {
+		t.Fatalf("expected the fallback to be logged once, got %v records", len(h.records))
+	}
+	r := h.records[0]
+	if r.Level != slog.LevelWarn {
+		t.Errorf("expected a warning, got %v", r.Level)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "point_format" && a.Value.String() != "11" {
+			t.Errorf("expected point format 11, got %v", a.Value)
+		}
+		return true
+	})
+
+	// Without a logger the fallback is silent.
+	lf = &LazFile{}
+	lf.Header.PointFormatID = 11
+	lf.convertPoint(&LaszipPoint{})
+}
+
+func TestLogMalformedVLR(t *testing.T) {
+	h := &recordingHandler{}
+	lf := &LazFile{logger: slog.New(h)}
+	lf.addVLR(VLR{UserID: "LASF_Projection", RecordID: 34735, BinaryData: make([]byte, 7)})
+	lf.addVLR(VLR{UserID: "LASF_Projection", RecordID: 34735, BinaryData: make([]byte, 8)})
+	if len(h.records) != 1 || h.records[0].Level != slog.LevelWarn {
+		t.Errorf("expected a single warning for the malformed VLR, got %v", h.records)
+	}
+	// The whole values of the malformed directory are still added.
+	if len(lf.geokeys.GeoKeyDirectory) != 7 {
+		t.Errorf("expected the 3 + 4 values of both directories, got %v", lf.geokeys.GeoKeyDirectory)
+	}
+}
@@ -0,0 +1,72 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// WithMmap memory-maps LAS files, so that point records are read in place
+// rather than copied from the file. GetXYZ then also works for files opened in
+// 'rh' mode, decoding the coordinates of the requested record directly from
+// the mapped memory, which gives cheap random access without reading the
+// points up front. Regular I/O is used on platforms without mmap, or if the
+// file cannot be mapped. The option does not apply to LAZ files.
+func WithMmap() ReaderOption {
+	return func(o *readerOptions) {
+		o.mmap = true
+	}
+}
+
+// mapFile maps the open file if the WithMmap option was given. Failures are
+// not errors; the file is then read with regular I/O.
+func (las *LasFile) mapFile() {
+	if !las.useMmap {
+		return
+	}
+	info, err := las.f.Stat()
+	if err != nil || info.Size() == 0 || int64(int(info.Size())) != info.Size() {
+		return
+	}
+	if b, err := mmapFile(las.f, int(info.Size())); err == nil {
+		las.mapped = b
+	}
+}
+
+// unmapFile releases the mapping, if any.
+func (las *LasFile) unmapFile() error {
+	if las.mapped == nil {
+		return nil
+	}
+	b := las.mapped
+	las.mapped = nil
+	return munmap(b)
+}
+
+// pointBytes returns the n bytes of point records that follow the VLRs. The
+// records of a mapped file are returned in place.
+func (las *LasFile) pointBytes(n int) ([]byte, error) {
+	offset := las.Header.OffsetToPoints
+	if las.mapped != nil && offset+n <= len(las.mapped) {
+		return las.mapped[offset : offset+n], nil
+	}
+	b := make([]byte, n)
+	if _, err := las.f.ReadAt(b, int64(offset)); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return b, nil
+}
+
+// mappedXYZ decodes the coordinates of a point record from the mapped file.
+func (las *LasFile) mappedXYZ(index int) (float64, float64, float64, error) {
+	h := &las.Header
+	o := h.OffsetToPoints + index*h.PointRecordLength
+	if h.PointRecordLength < 12 || o+12 > len(las.mapped) {
+		return NoData, NoData, NoData, errors.New("the point record lies beyond the end of the file")
+	}
+	b := las.mapped[o : o+12]
+	x := float64(int32(binary.LittleEndian.Uint32(b[0:4])))*h.XScaleFactor + h.XOffset
+	y := float64(int32(binary.LittleEndian.Uint32(b[4:8])))*h.YScaleFactor + h.YOffset
+	z := float64(int32(binary.LittleEndian.Uint32(b[8:12])))*h.ZScaleFactor + h.ZOffset
+	return x, y, z, nil
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package lidario
+
+import (
+	"errors"
+	"os"
+)
+
+// mmapFile reports that memory mapping is not supported, so that files are
+// read with regular I/O.
+func mmapFile(f *os.File, size int) ([]byte, error) {
+	return nil, errors.New("memory mapping is not supported on this platform")
+}
+
+func munmap(b []byte) error {
+	return nil
+}
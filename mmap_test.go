@@ -0,0 +1,89 @@
+package lidario
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLasMmap(t *testing.T) {
+	las, err := NewLasFile("testdata/sample.las", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+	mapped, err := NewLasFile("testdata/sample.las", "rh", WithMmap())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mapped.Close()
+	mappedRead, err := NewLidarFile("testdata/sample.las", "r", WithMmap())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mappedRead.Close()
+
+	for i := 0; i < las.Header.NumberPoints; i += 9973 {
+		x, y, z, err := las.GetXYZ(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, lf := range []LidarFile{mapped, mappedRead} {
+			mx, my, mz, err := lf.GetXYZ(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mx != x || my != y || mz != z {
+				t.Fatalf("point %v: (%v, %v, %v), expected (%v, %v, %v)", i, mx, my, mz, x, y, z)
+			}
+		}
+	}
+
+	unmapped, err := NewLasFile("testdata/sample.las", "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unmapped.Close()
+	if _, _, _, err = unmapped.GetXYZ(0); err != errHeaderOnly {
+		t.Errorf("expected the header-only error without a mapping, got %v", err)
+	}
+}
+
+func BenchmarkLasRandomAccess(b *testing.B) {
+	const n = 1000000
+	las, err := NewLasFile("testdata/sample.las", "rh", WithMmap())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer las.Close()
+	rng := rand.New(rand.NewSource(1))
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = rng.Intn(las.Header.NumberPoints)
+	}
+
+	b.Run("mmap", func(b *testing.B) {
+		if las.mapped == nil {
+			b.Skip("memory mapping is not supported")
+		}
+		for k := 0; k < b.N; k++ {
+			for _, i := range indices {
+				if _, _, _, err := las.GetXYZ(i); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("buffered", func(b *testing.B) {
+		rr, err := newRecordReader(las)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for k := 0; k < b.N; k++ {
+			for _, i := range indices {
+				if _, err := rr.record(i); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package lidario
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the first size bytes of f read-only into memory.
+func mmapFile(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// munmap releases a mapping created by mmapFile.
+func munmap(b []byte) error {
+	return syscall.Munmap(b)
+}
@@ -0,0 +1,113 @@
+package lidario
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoNIR is returned when near-infrared data are requested from a file whose
+// point format does not store them.
+var ErrNoNIR = errors.New("the point format does not contain near-infrared data")
+
+// hasNIR returns true for the point formats that store a near-infrared channel.
+func hasNIR(format uint8) bool {
+	return format == 8 || format == 10
+}
+
+// ndvi returns the normalized difference vegetation index of a point, or NaN
+// if both the red and near-infrared values are zero.
+func ndvi(red, nir uint16) float64 {
+	if int(nir)+int(red) == 0 {
+		return math.NaN()
+	}
+	return (float64(nir) - float64(red)) / (float64(nir) + float64(red))
+}
+
+// ndviGrid accumulates the NDVI of points into the cells of a raster.
+type ndviGrid struct {
+	raster *Raster
+	sums   []float64
+	counts []int
+	// seen marks cells containing points, including points without an NDVI
+	seen []bool
+}
+
+func newNDVIGrid(minX, minY, maxX, maxY, cellSize float64) (*ndviGrid, error) {
+	r, err := newRaster(minX, minY, maxX, maxY, cellSize)
+	if err != nil {
+		return nil, err
+	}
+	n := len(r.Data)
+	return &ndviGrid{raster: r, sums: make([]float64, n), counts: make([]int, n), seen: make([]bool, n)}, nil
+}
+
+func (g *ndviGrid) add(x, y float64, red, nir uint16) {
+	row, column, ok := g.raster.CellOf(x, y)
+	if !ok {
+		return
+	}
+	i := row*g.raster.Columns + column
+	g.seen[i] = true
+	if v := ndvi(red, nir); !math.IsNaN(v) {
+		g.sums[i] += v
+		g.counts[i]++
+	}
+}
+
+// result returns the raster of mean NDVI values. Cells without points are
+// NoData; cells whose points all have a zero denominator are NaN.
+func (g *ndviGrid) result() *Raster {
+	for i := range g.raster.Data {
+		switch {
+		case g.counts[i] > 0:
+			g.raster.Data[i] = g.sums[i] / float64(g.counts[i])
+		case g.seen[i]:
+			g.raster.Data[i] = math.NaN()
+		default:
+			g.raster.Data[i] = g.raster.NoData
+		}
+	}
+	return g.raster
+}
+
+// RasterizeNDVI returns a raster of the mean NDVI, (NIR-Red)/(NIR+Red), of the
+// points falling within each cell. Points are read sequentially from the start
+// of the file, after which the reader is positioned at the end of the data. Points
+// with zero red and near-infrared values have an undefined NDVI and are not
+// included in the mean; a cell containing only such points is NaN.
+func (lf *LazFile) RasterizeNDVI(cellSize float64) (*Raster, error) {
+	if lf.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	if !hasNIR(lf.Header.PointFormatID) {
+		return nil, ErrNoNIR
+	}
+	lf.Lock()
+	defer lf.Unlock()
+	if lf.currentPoint != 0 {
+		if err := lf.reader.SeekPoint(0); err != nil {
+			return nil, err
+		}
+		lf.currentPoint = 0
+	}
+	g, err := newNDVIGrid(lf.Header.MinX, lf.Header.MinY, lf.Header.MaxX, lf.Header.MaxY, cellSize)
+	if err != nil {
+		return nil, err
+	}
+	for lf.reader.IsStreaming() || lf.currentPoint < lf.Header.NumberPoints {
+		if err := lf.reader.ReadPoint(); err != nil {
+			if lf.Header.NumberPoints == 0 {
+				// A streaming-written file has reached the end of its data.
+				break
+			}
+			return nil, err
+		}
+		p := lf.reader.GetPoint()
+		if p == nil {
+			return nil, errors.New("failed to get point data")
+		}
+		lf.currentPoint++
+		g.add(p.X, p.Y, p.Red, p.NIR)
+	}
+	return g.result(), nil
+}
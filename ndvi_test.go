@@ -0,0 +1,48 @@
+package lidario
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNDVIGrid(t *testing.T) {
+	g, err := newNDVIGrid(0, 0, 3.5, 1.5, 2.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Western cell: NDVI of 0.5 and 0.0, a mean of 0.25.
+	g.add(0.5, 0.5, 100, 300)
+	g.add(1.5, 1.5, 200, 200)
+	// Eastern cell: only a point whose red and NIR are both zero.
+	g.add(2.5, 0.5, 0, 0)
+	r := g.result()
+
+	if r.Rows != 1 || r.Columns != 2 {
+		t.Fatalf("expected a 1x2 raster, got %vx%v", r.Rows, r.Columns)
+	}
+	if v := r.Value(0, 0); math.Abs(v-0.25) > 1e-12 {
+		t.Errorf("expected a mean NDVI of 0.25, got %v", v)
+	}
+	if v := r.Value(0, 1); !math.IsNaN(v) {
+		t.Errorf("expected NaN for a zero denominator, got %v", v)
+	}
+}
+
+func TestNDVI(t *testing.T) {
+	if v := ndvi(0, 1000); v != 1 {
+		t.Errorf("expected an NDVI of 1 for pure NIR, got %v", v)
+	}
+	if v := ndvi(1000, 0); v != -1 {
+		t.Errorf("expected an NDVI of -1 for pure red, got %v", v)
+	}
+	if v := ndvi(0, 0); !math.IsNaN(v) {
+		t.Errorf("expected NaN for a zero denominator, got %v", v)
+	}
+}
+
+func TestRasterizeNDVIRequiresNIR(t *testing.T) {
+	lf := &LazFile{fileMode: "r", Header: LasHeader{PointFormatID: 3}}
+	if _, err := lf.RasterizeNDVI(1.0); err != ErrNoNIR {
+		t.Errorf("expected ErrNoNIR, got %v", err)
+	}
+}
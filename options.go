@@ -0,0 +1,96 @@
+package lidario
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Options configures NewLidarFileWithOptions. The zero value opens a file for
+// reading with the default settings.
+type Options struct {
+	// Mode is the file mode: "r" to read, "rh" to read the header only or
+	// "w" to write. An empty mode reads the file.
+	Mode string
+	// ReadBufferSize is the number of bytes of point records read at a time
+	// from a LAS file; see WithReadBufferSize. Zero uses the default size.
+	ReadBufferSize int
+	// Mmap memory-maps the point records of a LAS file; see WithMmap.
+	Mmap bool
+	// BufferedReads reads the point records of a LAS file opened in "rh"
+	// mode on demand, in blocks of ReadBufferSize bytes; see
+	// WithBufferedReads.
+	BufferedReads bool
+	// DisableFinalizer removes the finalizer that closes a LAZ file that is
+	// garbage collected without being closed; see LazFile.DisableFinalizer.
+	DisableFinalizer bool
+	// Logger receives the diagnostics of the file: its opening and, for a
+	// LAZ file, those described by LazFile.SetLogger. A nil logger discards
+	// them.
+	Logger *slog.Logger
+	// FastMode skips the bounds check on each point read from a LAZ file,
+	// making reads outside of the file undefined; see LazFile.SetFastMode.
+	FastMode bool
+	// Strict fails fast, returning an error wrapping ErrVersionMismatch, if
+	// the point format of the file is not defined in its LAS version. Such
+	// files are otherwise read and reported by Validate.
+	Strict bool
+}
+
+// readerOptions returns the options that apply to LAS files.
+func (o Options) readerOptions() []ReaderOption {
+	opts := []ReaderOption{}
+	if o.ReadBufferSize > 0 {
+		opts = append(opts, WithReadBufferSize(o.ReadBufferSize))
+	}
+	if o.Mmap {
+		opts = append(opts, WithMmap())
+	}
+	if o.BufferedReads {
+		opts = append(opts, WithBufferedReads())
+	}
+	return opts
+}
+
+// NewLidarFileWithOptions creates a new LidarFile (either LAS or LAZ) based
+// on file type detection, as NewLidarFile does, configured by opts. Options
+// that do not apply to the detected file type are ignored.
+func NewLidarFileWithOptions(fileName string, opts Options) (LidarFile, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = "r"
+	}
+	if isCompressedFile(fileName) {
+		lazFile, err := newLazFile(fileName, mode, nil, opts.Logger)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Strict {
+			if err = checkPointFormatVersion(lazFile.Header); err != nil {
+				lazFile.Close()
+				return nil, err
+			}
+		}
+		if opts.DisableFinalizer {
+			lazFile.DisableFinalizer()
+		}
+		if opts.FastMode {
+			lazFile.SetFastMode(true)
+		}
+		return lazFile, nil
+	}
+
+	lasFile, err := NewLasFile(fileName, mode, opts.readerOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Strict && lasFile.fileMode != "w" {
+		if err = checkPointFormatVersion(lasFile.Header); err != nil {
+			lasFile.Close()
+			return nil, err
+		}
+	}
+	loggerOrDiscard(opts.Logger).Debug("opened LAS file", "file", fileName, "mode", lasFile.fileMode,
+		"version", fmt.Sprintf("%v.%v", lasFile.Header.VersionMajor, lasFile.Header.VersionMinor),
+		"point_format", lasFile.Header.PointFormatID, "points", lasFile.Header.NumberPoints)
+	return lasFile, nil
+}
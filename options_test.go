@@ -0,0 +1,69 @@
+package lidario
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewLidarFileWithOptions(t *testing.T) {
+	lf, err := NewLidarFileWithOptions("testdata/sample.las", Options{Mode: "rh", Mmap: true, ReadBufferSize: 4096})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	las, ok := lf.(*LasFile)
+	if !ok {
+		t.Fatalf("expected a *LasFile, got %T", lf)
+	}
+	if las.fileMode != "rh" || !las.useMmap || las.readBufferSize != 4096 {
+		t.Errorf("unexpected mode %q, mmap %v and buffer size %v", las.fileMode, las.useMmap, las.readBufferSize)
+	}
+
+	// The zero value reads the file with the default buffer.
+	lf, err = NewLidarFileWithOptions("testdata/sample.las", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	las = lf.(*LasFile)
+	if las.fileMode != "r" || las.useMmap || las.readBufferSize != defaultReadBufferSize {
+		t.Errorf("unexpected mode %q, mmap %v and buffer size %v", las.fileMode, las.useMmap, las.readBufferSize)
+	}
+
+	// The LAS options are ignored for a LAZ file.
+	var logged bytes.Buffer
+	lf, err = NewLidarFileWithOptions(writeHeaderOnlyLaz(t), Options{Mode: "rh", Mmap: true,
+		DisableFinalizer: true, Logger: slog.New(slog.NewTextHandler(&logged, &slog.HandlerOptions{Level: slog.LevelDebug}))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if laz, ok := lf.(*LazFile); !ok || laz.fileMode != "rh" {
+		t.Errorf("expected a header-only *LazFile, got %T", lf)
+	}
+	if !strings.Contains(logged.String(), "opened LAZ file") {
+		t.Errorf("expected the opening to be logged, got %q", logged.String())
+	}
+}
+
+func TestWarnUnclosedLogger(t *testing.T) {
+	var logged bytes.Buffer
+	warnUnclosed(&LaszipReader{fileName: "points.laz", logger: slog.New(slog.NewTextHandler(&logged, nil))})
+	if !bytes.Contains(logged.Bytes(), []byte("level=WARN")) {
+		t.Errorf("expected the warning to be written to the logger, got %q", logged.String())
+	}
+
+	// Without a logger the warning is discarded rather than written to the
+	// standard logger.
+	logged.Reset()
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+	warnUnclosed(&LaszipReader{fileName: "points.laz"})
+	if logged.Len() != 0 {
+		t.Errorf("expected no output without a logger, got %q", logged.String())
+	}
+}
@@ -0,0 +1,141 @@
+package lidario
+
+import (
+	"errors"
+)
+
+// spatiallySortedThreshold is the fraction of ordered sample pairs above which
+// a file is considered to be spatially sorted.
+const spatiallySortedThreshold = 0.9
+
+// IsSpatiallySorted samples the points of the file and heuristically decides
+// whether they are stored in a spatial order (Morton/Z-order, row-major or
+// column-major) rather than in acquisition (scan) order. Points are sampled at
+// evenly spaced indices; in a spatially sorted file the ordering keys of these
+// samples are almost always monotonic, whereas scan-ordered files jump back and
+// forth across the extent. The confidence is the fraction of consecutive
+// samples that are ordered under the best matching ordering, from 0 to 1; the
+// file is reported as sorted when it is at least 0.9.
+func (las *LasFile) IsSpatiallySorted(sampleSize int) (sorted bool, confidence float64, err error) {
+	if las.fileMode == "rh" {
+		return false, 0, errHeaderOnly
+	}
+	if sampleSize < 2 {
+		return false, 0, errors.New("the sample size must be at least 2")
+	}
+	if sampleSize > len(las.pointData) {
+		sampleSize = len(las.pointData)
+	}
+	if sampleSize < 2 {
+		// Zero or one point is trivially sorted.
+		return true, 1, nil
+	}
+	xs := make([]float64, sampleSize)
+	ys := make([]float64, sampleSize)
+	stride := float64(len(las.pointData)-1) / float64(sampleSize-1)
+	for i := 0; i < sampleSize; i++ {
+		p := las.pointData[int(float64(i)*stride)]
+		xs[i], ys[i] = p.X, p.Y
+	}
+	confidence = spatialOrderConfidence(xs, ys, las.Header.MinX, las.Header.MinY, las.Header.MaxX, las.Header.MaxY)
+	return confidence >= spatiallySortedThreshold, confidence, nil
+}
+
+// IsSpatiallySorted reports whether the points of the LAZ file are stored in a
+// spatial order, with the confidence of the decision. COPC files are organized
+// in an octree and always return true with a confidence of 1; otherwise the
+// points are sampled as for LAS files, seeking to each sample.
+func (lf *LazFile) IsSpatiallySorted(sampleSize int) (sorted bool, confidence float64, err error) {
+	if lf.isCopc() {
+		return true, 1, nil
+	}
+	if lf.fileMode == "rh" {
+		return false, 0, errHeaderOnly
+	}
+	if sampleSize < 2 {
+		return false, 0, errors.New("the sample size must be at least 2")
+	}
+	if sampleSize > lf.Header.NumberPoints {
+		sampleSize = lf.Header.NumberPoints
+	}
+	if sampleSize < 2 {
+		return true, 1, nil
+	}
+	xs := make([]float64, sampleSize)
+	ys := make([]float64, sampleSize)
+	stride := float64(lf.Header.NumberPoints-1) / float64(sampleSize-1)
+	for i := 0; i < sampleSize; i++ {
+		p, err := lf.LasPoint(int(float64(i) * stride))
+		if err != nil {
+			return false, 0, err
+		}
+		xs[i], ys[i] = p.PointData().X, p.PointData().Y
+	}
+	confidence = spatialOrderConfidence(xs, ys, lf.Header.MinX, lf.Header.MinY, lf.Header.MaxX, lf.Header.MaxY)
+	return confidence >= spatiallySortedThreshold, confidence, nil
+}
+
+// spatialOrderConfidence returns the largest fraction of consecutive sample
+// pairs that are ordered (in either direction) by Morton code, by Y or by X.
+func spatialOrderConfidence(xs, ys []float64, minX, minY, maxX, maxY float64) float64 {
+	n := len(xs)
+	if n < 2 {
+		return 1.0
+	}
+	codes := make([]uint64, n)
+	for i := range xs {
+		codes[i] = mortonCode(quantize(xs[i], minX, maxX), quantize(ys[i], minY, maxY))
+	}
+	var mortonUp, mortonDown, yUp, yDown, xUp, xDown int
+	for i := 1; i < n; i++ {
+		if codes[i] >= codes[i-1] {
+			mortonUp++
+		}
+		if codes[i] <= codes[i-1] {
+			mortonDown++
+		}
+		if ys[i] >= ys[i-1] {
+			yUp++
+		}
+		if ys[i] <= ys[i-1] {
+			yDown++
+		}
+		if xs[i] >= xs[i-1] {
+			xUp++
+		}
+		if xs[i] <= xs[i-1] {
+			xDown++
+		}
+	}
+	best := mortonUp
+	for _, v := range []int{mortonDown, yUp, yDown, xUp, xDown} {
+		if v > best {
+			best = v
+		}
+	}
+	return float64(best) / float64(n-1)
+}
+
+// quantize maps a value within [min, max] onto a 16-bit integer range.
+func quantize(value, min, max float64) uint32 {
+	if max <= min {
+		return 0
+	}
+	q := (value - min) / (max - min) * 65535.0
+	if q < 0 {
+		return 0
+	}
+	if q > 65535 {
+		return 65535
+	}
+	return uint32(q)
+}
+
+// mortonCode interleaves the bits of two 16-bit values into a Z-order key.
+func mortonCode(x, y uint32) uint64 {
+	var code uint64
+	for b := uint(0); b < 16; b++ {
+		code |= uint64((x>>b)&1)<<(2*b) | uint64((y>>b)&1)<<(2*b+1)
+	}
+	return code
+}
@@ -0,0 +1,48 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestIsSpatiallySorted(t *testing.T) {
+	// The sample file is stored in acquisition (scan) order.
+	lf, err := NewLasFile("testdata/sample.las", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	sorted, confidence, err := lf.IsSpatiallySorted(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sorted || confidence >= spatiallySortedThreshold {
+		t.Errorf("expected the scan-ordered sample file not to be spatially sorted (confidence %v)", confidence)
+	}
+
+	// A regular grid of points written in row-major order.
+	var points []LasPointer
+	for row := 0; row < 50; row++ {
+		for col := 0; col < 50; col++ {
+			points = append(points, &PointRecord0{X: float64(col), Y: float64(row), Z: 1.0})
+		}
+	}
+	gridLf := createTestLasFile(t, 0, points)
+	sorted, confidence, err = gridLf.IsSpatiallySorted(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sorted || confidence < spatiallySortedThreshold || confidence > 1 {
+		t.Errorf("expected the row-major grid to be spatially sorted (confidence %v)", confidence)
+	}
+}
+
+func TestIsSpatiallySortedCopc(t *testing.T) {
+	lf := &LazFile{VlrData: []VLR{{UserID: "copc", RecordID: 1}}}
+	sorted, confidence, err := lf.IsSpatiallySorted(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sorted || confidence != 1 {
+		t.Errorf("expected a COPC file to be reported as spatially sorted, got %v with confidence %v", sorted, confidence)
+	}
+}
@@ -0,0 +1,42 @@
+package lidario
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// windowsMaxPath is the length from which Windows paths need the \\?\ prefix
+// to lift the MAX_PATH limit.
+const windowsMaxPath = 260
+
+// nativePath prepares a file name to be passed to LASzip. The name is cleaned
+// and, on Windows, made absolute and given the long path prefix if it is too
+// long for MAX_PATH. LASzip opens files with the narrow fopen, which takes the
+// UTF-8 bytes of the name as they are: non-ASCII names open on Unix, but on
+// Windows the bytes are read in the ANSI code page, and the prefix only lifts
+// the limit for the wide file APIs, so neither non-ASCII nor long paths are
+// guaranteed to open there.
+func nativePath(fileName string) string {
+	fileName = filepath.Clean(fileName)
+	if runtime.GOOS != "windows" {
+		return fileName
+	}
+	if abs, err := filepath.Abs(fileName); err == nil {
+		fileName = abs
+	}
+	return windowsLongPath(fileName)
+}
+
+// windowsLongPath adds the \\?\ prefix to an absolute Windows path of at
+// least windowsMaxPath characters. UNC paths (\\server\share\...) take the
+// \\?\UNC\ form of the prefix.
+func windowsLongPath(path string) string {
+	if len(path) < windowsMaxPath || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	if strings.HasPrefix(path, `\\`) {
+		return `\\?\UNC\` + path[2:]
+	}
+	return `\\?\` + path
+}
@@ -0,0 +1,56 @@
+package lidario
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWindowsLongPath(t *testing.T) {
+	long := strings.Repeat(`\directorio`, 30)
+	tests := []struct {
+		path, expected string
+	}{
+		{`C:\Levantamientos\Córdoba\tile.laz`, `C:\Levantamientos\Córdoba\tile.laz`},
+		{`C:` + long, `\\?\C:` + long},
+		{`\\server\share` + long, `\\?\UNC\server\share` + long},
+		{`\\?\C:` + long, `\\?\C:` + long},
+	}
+	for _, test := range tests {
+		if got := windowsLongPath(test.path); got != test.expected {
+			t.Errorf("windowsLongPath(%q) = %q, expected %q", test.path, got, test.expected)
+		}
+	}
+}
+
+func TestUnicodePath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Levantamiento_Córdoba", "Ñuñoa")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := nativePath(dir + "/./tile.laz"); filepath.Base(filepath.Dir(got)) != "Ñuñoa" {
+		t.Errorf("nativePath did not preserve the directory name: %q", got)
+	}
+
+	requireSampleLaz(t)
+	requireLaszip(t)
+	data, err := os.ReadFile(sampleLazFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(dir, "teselación.laz")
+	if err = os.WriteFile(fileName, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{sampleLazFile, fileName} {
+		lf, err := NewLazFile(name, "r")
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		if _, err = lf.LasPoint(0); err != nil {
+			t.Errorf("%v: %v", name, err)
+		}
+		lf.Close()
+	}
+}
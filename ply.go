@@ -0,0 +1,112 @@
+package lidario
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// plyWriter writes PLY vertices. The coordinates are written as doubles to
+// preserve the precision of georeferenced coordinates, and the 16-bit LAS
+// colours are reduced to the 8-bit channels expected by PLY viewers.
+type plyWriter struct {
+	w      *bufio.Writer
+	binary bool
+	color  bool
+	buf    []byte
+}
+
+func newPLYWriter(w io.Writer, binaryFormat, color bool) *plyWriter {
+	return &plyWriter{w: bufio.NewWriter(w), binary: binaryFormat, color: color}
+}
+
+// writeHeader writes the PLY header for count vertices.
+func (pw *plyWriter) writeHeader(count uint64) error {
+	format := "ascii"
+	if pw.binary {
+		format = "binary_little_endian"
+	}
+	fmt.Fprintf(pw.w, "ply\nformat %v 1.0\ncomment generated by lidario\nelement vertex %v\n", format, count)
+	pw.w.WriteString("property double x\nproperty double y\nproperty double z\n")
+	if pw.color {
+		pw.w.WriteString("property uchar red\nproperty uchar green\nproperty uchar blue\n")
+	}
+	_, err := pw.w.WriteString("end_header\n")
+	return err
+}
+
+// writeVertex writes a point as a vertex. The colour is read from the LASzip
+// point, which carries it for every point format.
+func (pw *plyWriter) writeVertex(lp *LaszipPoint) error {
+	b := pw.buf[:0]
+	var rgb [3]uint8
+	if pw.color {
+		rgb = [3]uint8{uint8(lp.Red >> 8), uint8(lp.Green >> 8), uint8(lp.Blue >> 8)}
+	}
+	if pw.binary {
+		for _, v := range []float64{lp.X, lp.Y, lp.Z} {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+		}
+		if pw.color {
+			b = append(b, rgb[:]...)
+		}
+	} else {
+		b = strconv.AppendFloat(b, lp.X, 'f', -1, 64)
+		b = append(b, ' ')
+		b = strconv.AppendFloat(b, lp.Y, 'f', -1, 64)
+		b = append(b, ' ')
+		b = strconv.AppendFloat(b, lp.Z, 'f', -1, 64)
+		if pw.color {
+			for _, c := range rgb {
+				b = append(b, ' ')
+				b = strconv.AppendUint(b, uint64(c), 10)
+			}
+		}
+		b = append(b, '\n')
+	}
+	pw.buf = b
+	_, err := pw.w.Write(b)
+	return err
+}
+
+func (pw *plyWriter) flush() error {
+	return pw.w.Flush()
+}
+
+// ExportPLY writes the points as the vertices of a PLY file, in ASCII or, if
+// binaryFormat is true, little-endian binary. The vertices carry x, y and z
+// properties, plus red, green and blue properties if the point format stores
+// colour. The points are streamed to w. Files whose header does not declare
+// the number of points cannot be exported, since the PLY header needs it.
+func (lf *LazFile) ExportPLY(w io.Writer, binaryFormat bool) error {
+	count := lf.GetPointCount64()
+	it, err := lf.Points()
+	if err != nil {
+		return err
+	}
+	if count == 0 && lf.reader.IsStreaming() {
+		return errors.New("the header does not declare the number of points, which the PLY header requires")
+	}
+	pw := newPLYWriter(w, binaryFormat, hasRGB(lf.Header.PointFormatID))
+	if err = pw.writeHeader(count); err != nil {
+		return err
+	}
+	var written uint64
+	for it.Next() {
+		if err = pw.writeVertex(it.laszipPoint()); err != nil {
+			return err
+		}
+		written++
+	}
+	if it.Err() != nil {
+		return it.Err()
+	}
+	if written != count {
+		return fmt.Errorf("the header declares %v points but %v were read", count, written)
+	}
+	return pw.flush()
+}
@@ -0,0 +1,171 @@
+package lidario
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// plyVertex is a vertex read back by readPLY.
+type plyVertex struct {
+	x, y, z          float64
+	red, green, blue uint8
+}
+
+// readPLY is a minimal PLY parser for the files written by plyWriter.
+func readPLY(t *testing.T, data []byte) (vertices []plyVertex, color bool) {
+	t.Helper()
+	r := bufio.NewReader(bytes.NewReader(data))
+	count, binaryFormat := -1, false
+	var properties []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading the PLY header: %v", err)
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case fields[0] == "end_header":
+			goto body
+		case fields[0] == "format":
+			binaryFormat = fields[1] == "binary_little_endian"
+		case fields[0] == "element" && fields[1] == "vertex":
+			count, _ = strconv.Atoi(fields[2])
+		case fields[0] == "property":
+			properties = append(properties, fields[1]+" "+fields[2])
+		}
+	}
+body:
+	color = len(properties) == 6
+	expected := []string{"double x", "double y", "double z", "uchar red", "uchar green", "uchar blue"}
+	for i, p := range properties {
+		if i >= len(expected) || p != expected[i] {
+			t.Fatalf("unexpected properties %v", properties)
+		}
+	}
+	for i := 0; i < count; i++ {
+		var v plyVertex
+		if binaryFormat {
+			b := make([]byte, 24)
+			if color {
+				b = make([]byte, 27)
+			}
+			if _, err := io.ReadFull(r, b); err != nil {
+				t.Fatalf("vertex %v: %v", i, err)
+			}
+			v.x = math.Float64frombits(binary.LittleEndian.Uint64(b[0:8]))
+			v.y = math.Float64frombits(binary.LittleEndian.Uint64(b[8:16]))
+			v.z = math.Float64frombits(binary.LittleEndian.Uint64(b[16:24]))
+			if color {
+				v.red, v.green, v.blue = b[24], b[25], b[26]
+			}
+		} else {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				t.Fatalf("vertex %v: %v", i, err)
+			}
+			fields := strings.Fields(line)
+			v.x, _ = strconv.ParseFloat(fields[0], 64)
+			v.y, _ = strconv.ParseFloat(fields[1], 64)
+			v.z, _ = strconv.ParseFloat(fields[2], 64)
+			if color {
+				c := make([]uint8, 3)
+				for j := range c {
+					n, _ := strconv.Atoi(fields[3+j])
+					c[j] = uint8(n)
+				}
+				v.red, v.green, v.blue = c[0], c[1], c[2]
+			}
+		}
+		vertices = append(vertices, v)
+	}
+	if rest, _ := io.ReadAll(r); len(rest) != 0 {
+		t.Errorf("%v bytes follow the last vertex", len(rest))
+	}
+	return vertices, color
+}
+
+func TestPLYWriter(t *testing.T) {
+	points := []LaszipPoint{
+		{X: 431000.25, Y: 4582000.5, Z: 12.75, Red: 65535, Green: 32768, Blue: 0},
+		{X: -1.5, Y: 2, Z: 0, Red: 256, Green: 512, Blue: 768},
+	}
+	for _, binaryFormat := range []bool{false, true} {
+		for _, color := range []bool{false, true} {
+			var buf bytes.Buffer
+			pw := newPLYWriter(&buf, binaryFormat, color)
+			if err := pw.writeHeader(uint64(len(points))); err != nil {
+				t.Fatal(err)
+			}
+			for i := range points {
+				if err := pw.writeVertex(&points[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := pw.flush(); err != nil {
+				t.Fatal(err)
+			}
+			vertices, hasColor := readPLY(t, buf.Bytes())
+			if hasColor != color || len(vertices) != len(points) {
+				t.Fatalf("binary %v, color %v: read %v vertices, color %v", binaryFormat, color, len(vertices), hasColor)
+			}
+			for i, v := range vertices {
+				p := points[i]
+				if v.x != p.X || v.y != p.Y || v.z != p.Z {
+					t.Errorf("binary %v, vertex %v: got %+v, expected %+v", binaryFormat, i, v, p)
+				}
+			}
+			if color && (vertices[0].red != 255 || vertices[0].green != 128 || vertices[1].blue != 3) {
+				t.Errorf("binary %v: unexpected colours %+v", binaryFormat, vertices)
+			}
+		}
+	}
+}
+
+func TestExportPLY(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	first, err := lf.LasPoint(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, binaryFormat := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err = lf.ExportPLY(&buf, binaryFormat); err != nil {
+			t.Fatal(err)
+		}
+		vertices, color := readPLY(t, buf.Bytes())
+		if uint64(len(vertices)) != lf.GetPointCount64() {
+			t.Errorf("expected %v vertices, got %v", lf.GetPointCount64(), len(vertices))
+		}
+		if color != hasRGB(lf.Header.PointFormatID) {
+			t.Errorf("colour properties written: %v, expected %v", color, !color)
+		}
+		if pd := first.PointData(); vertices[0].x != pd.X || vertices[0].y != pd.Y || vertices[0].z != pd.Z {
+			t.Errorf("first vertex %+v does not match the first point %+v", vertices[0], pd)
+		}
+	}
+}
+
+func TestExportPLYUndeclaredCount(t *testing.T) {
+	// The count is checked before anything is written.
+	lf := &LazFile{fileMode: "r", reader: &LaszipReader{streaming: true}}
+	var buf bytes.Buffer
+	if err := lf.ExportPLY(&buf, false); err == nil {
+		t.Error("expected an error for a file that does not declare its point count")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
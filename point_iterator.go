@@ -0,0 +1,166 @@
+package lidario
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// pointIteratorBatchSize is the number of points decompressed at a time by a
+// PointIterator.
+const pointIteratorBatchSize = 4096
+
+// PointIterator streams the points of a LAZ file in order. Points are
+// decompressed in batches, so iterating is much cheaper than calling LasPoint
+// for each index, and the caller need not know the point count in advance.
+//
+//	it, err := lf.Points()
+//	for it.Next() {
+//		p := it.Point()
+//	}
+//	if it.Err() != nil { ... }
+type PointIterator struct {
+	lf    *LazFile
+	buf   []LaszipPoint
+	n     int // number of points held in buf
+	pos   int // position of the current point in buf
+	point LasPointer
+	err   error
+	done  bool
+	// ranged is set when only the points of ranges are read, rather than
+	// the points up to the end of the file
+	ranged    bool
+	ranges    []pointRange
+	remaining int // number of points left to read in the current range
+	progress  progress
+	// ctx is checked before each batch is decompressed
+	ctx context.Context
+}
+
+// pointRange is a run of consecutive points.
+type pointRange struct {
+	start int
+	count int
+}
+
+// Points returns an iterator positioned before the first point of the file.
+// The reader is rewound to the start of the file if points have already been
+// read; the points are then read sequentially.
+func (lf *LazFile) Points() (*PointIterator, error) {
+	return lf.PointsCtx(context.Background())
+}
+
+// PointsCtx is like Points, but the iteration stops with the error of ctx,
+// which is checked before each batch of points is decompressed, once ctx is
+// cancelled or its deadline passes.
+func (lf *LazFile) PointsCtx(ctx context.Context) (*PointIterator, error) {
+	if lf.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	lf.Lock()
+	defer lf.Unlock()
+	if lf.currentPoint != 0 {
+		if err := lf.reader.SeekPoint(0); err != nil {
+			return nil, fmt.Errorf("failed to seek to the first point: %w", err)
+		}
+		lf.currentPoint = 0
+	}
+	it := PointIterator{lf: lf, buf: make([]LaszipPoint, pointIteratorBatchSize),
+		progress: newProgress(lf.progress, lf.Header.NumberPoints), ctx: ctx}
+	return &it, nil
+}
+
+// Next advances to the next point, returning false at the end of the points
+// or if an error occurs.
+func (it *PointIterator) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+	it.pos++
+	if it.pos >= it.n {
+		if !it.fill() {
+			if it.done {
+				it.progress.finish()
+			}
+			return false
+		}
+		// The progress is reported outside of the lock held by fill.
+		it.progress.add(it.n)
+	}
+	it.point = it.lf.convertPoint(&it.buf[it.pos])
+	return true
+}
+
+// fill decompresses the next batch of points.
+func (it *PointIterator) fill() bool {
+	if it.ctx != nil {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.lf.Lock()
+	defer it.lf.Unlock()
+	buf := it.buf
+	if it.ranged {
+		if !it.nextRange() {
+			return false
+		}
+		if it.remaining < len(buf) {
+			buf = buf[:it.remaining]
+		}
+	}
+	n, err := it.lf.reader.ReadPointsInto(buf)
+	it.lf.currentPoint += n
+	if it.ranged {
+		it.remaining -= n
+	}
+	it.n, it.pos = n, 0
+	if err == io.EOF || (err == nil && n == 0) {
+		it.done = true
+		return false
+	}
+	if err != nil {
+		it.err = fmt.Errorf("failed to read points: %w", err)
+		return false
+	}
+	return true
+}
+
+// nextRange seeks to the next range of points once the current range has been
+// read. The caller must hold the lock.
+func (it *PointIterator) nextRange() bool {
+	for it.remaining <= 0 {
+		if len(it.ranges) == 0 {
+			it.done = true
+			return false
+		}
+		r := it.ranges[0]
+		it.ranges = it.ranges[1:]
+		if r.start != it.lf.currentPoint {
+			if err := it.lf.reader.SeekPoint(uint64(r.start)); err != nil {
+				it.err = fmt.Errorf("failed to seek to point %v: %w", r.start, err)
+				return false
+			}
+			it.lf.currentPoint = r.start
+		}
+		it.remaining = r.count
+	}
+	return true
+}
+
+// Point returns the current point.
+func (it *PointIterator) Point() LasPointer {
+	return it.point
+}
+
+// laszipPoint returns the current point as read from LASzip, with the
+// extended return and class fields of point formats 6 and above intact.
+func (it *PointIterator) laszipPoint() *LaszipPoint {
+	return &it.buf[it.pos]
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *PointIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,47 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestPointIterator(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	// Read a point first; Points starts from the beginning regardless.
+	first, err := lf.LasPoint(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	it, err := lf.Points()
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for it.Next() {
+		if count == 0 && *it.Point().PointData() != *first.PointData() {
+			t.Error("the iterator did not start at the first point")
+		}
+		count++
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if uint32(count) != lf.GetPointCount() {
+		t.Errorf("iterated over %v points, expected %v", count, lf.GetPointCount())
+	}
+	if it.Next() {
+		t.Error("Next returned true after the end of the points")
+	}
+}
+
+func TestPointIteratorHeaderOnly(t *testing.T) {
+	lf := &LazFile{fileMode: "rh"}
+	if _, err := lf.Points(); err != errHeaderOnly {
+		t.Errorf("expected errHeaderOnly, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package lidario
+
+import (
+	"fmt"
+)
+
+// pointFormatFields lists the fields stored by each LAS point data format.
+var pointFormatFields = [...]string{
+	"XYZ, Intensity",
+	"XYZ, Intensity, GPS Time",
+	"XYZ, Intensity, RGB",
+	"XYZ, Intensity, GPS Time, RGB",
+	"XYZ, Intensity, GPS Time, Wave Packets",
+	"XYZ, Intensity, GPS Time, RGB, Wave Packets",
+	"XYZ, Intensity, GPS Time, Extended Returns",
+	"XYZ, Intensity, GPS Time, Extended Returns, RGB",
+	"XYZ, Intensity, GPS Time, Extended Returns, RGB, NIR",
+	"XYZ, Intensity, GPS Time, Extended Returns, Wave Packets",
+	"XYZ, Intensity, GPS Time, Extended Returns, RGB, NIR, Wave Packets",
+}
+
+// pointFormatRecordLengths holds the minimum record length of each LAS point
+// data format. Records may be longer when they carry extra bytes.
+var pointFormatRecordLengths = [...]int{20, 28, 26, 34, 57, 63, 30, 36, 38, 59, 67}
+
+// PointFormatDescription returns a human-readable description of a point data
+// format, e.g. "Point Format 3 (XYZ, Intensity, GPS Time, RGB)".
+func PointFormatDescription(format uint8) string {
+	if int(format) >= len(pointFormatFields) {
+		return fmt.Sprintf("Point Format %v (unknown)", format)
+	}
+	return fmt.Sprintf("Point Format %v (%v)", format, pointFormatFields[format])
+}
+
+// convertPointFormat returns p as a point of the given format, 0-3, dropping
+// the attributes that the format does not store and zero-filling those that p
+// does not carry. A point already of the format is returned unchanged.
+func convertPointFormat(p LasPointer, format uint8) LasPointer {
+	if p.Format() == format {
+		return p
+	}
+	var gpsTime float64
+	if hasGPSTime(p.Format()) {
+		gpsTime = p.GpsTimeData()
+	}
+	rgb := &RgbData{}
+	if c := p.RgbData(); hasRGB(p.Format()) && c != nil {
+		*rgb = *c
+	}
+	pd := p.PointData()
+	switch format {
+	case 1:
+		return &PointRecord1{PointRecord0: pd, GPSTime: gpsTime}
+	case 2:
+		return &PointRecord2{PointRecord0: pd, RGB: rgb}
+	case 3:
+		return &PointRecord3{PointRecord0: pd, GPSTime: gpsTime, RGB: rgb}
+	}
+	return pd
+}
@@ -0,0 +1,32 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestPointFormatDescription(t *testing.T) {
+	tests := []struct {
+		format   uint8
+		expected string
+	}{
+		{0, "Point Format 0 (XYZ, Intensity)"},
+		{1, "Point Format 1 (XYZ, Intensity, GPS Time)"},
+		{2, "Point Format 2 (XYZ, Intensity, RGB)"},
+		{3, "Point Format 3 (XYZ, Intensity, GPS Time, RGB)"},
+		{4, "Point Format 4 (XYZ, Intensity, GPS Time, Wave Packets)"},
+		{5, "Point Format 5 (XYZ, Intensity, GPS Time, RGB, Wave Packets)"},
+		{6, "Point Format 6 (XYZ, Intensity, GPS Time, Extended Returns)"},
+		{7, "Point Format 7 (XYZ, Intensity, GPS Time, Extended Returns, RGB)"},
+		{8, "Point Format 8 (XYZ, Intensity, GPS Time, Extended Returns, RGB, NIR)"},
+		{9, "Point Format 9 (XYZ, Intensity, GPS Time, Extended Returns, Wave Packets)"},
+		{10, "Point Format 10 (XYZ, Intensity, GPS Time, Extended Returns, RGB, NIR, Wave Packets)"},
+		{11, "Point Format 11 (unknown)"},
+	}
+
+	for _, test := range tests {
+		result := PointFormatDescription(test.format)
+		if result != test.expected {
+			t.Errorf("PointFormatDescription(%v) = %s, expected %s", test.format, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+)
+
+// FileInfo holds the basic metadata of a LAS/LAZ file as returned by Probe.
+type FileInfo struct {
+	Signature     string
+	VersionMajor  byte
+	VersionMinor  byte
+	PointFormatID byte
+	PointCount    uint64
+	Compressed    bool
+	MinX          float64
+	MaxX          float64
+	MinY          float64
+	MaxY          float64
+	MinZ          float64
+	MaxZ          float64
+}
+
+// Probe reads the fixed part of the public header block of a LAS or LAZ file
+// and returns its basic metadata. The header is not compressed, so LAZ files
+// are probed without opening LASzip, which makes Probe much cheaper than
+// NewLidarFile when only the metadata is needed.
+func Probe(fileName string) (*FileInfo, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b := make([]byte, 375)
+	n, err := io.ReadFull(f, b)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	if n < 227 || string(b[0:4]) != "LASF" {
+		return nil, errors.New("the file is not a LAS/LAZ file")
+	}
+	f64 := func(o int) float64 { return math.Float64frombits(binary.LittleEndian.Uint64(b[o : o+8])) }
+	info := FileInfo{
+		Signature:    string(b[0:4]),
+		VersionMajor: b[24],
+		VersionMinor: b[25],
+		// LASzip sets the two high bits of the point format in compressed files.
+		PointFormatID: b[104] & 0x3F,
+		PointCount:    uint64(binary.LittleEndian.Uint32(b[107:111])),
+		MaxX:          f64(179),
+		MinX:          f64(187),
+		MaxY:          f64(195),
+		MinY:          f64(203),
+		MaxZ:          f64(211),
+		MinZ:          f64(219),
+	}
+	headerSize := int(binary.LittleEndian.Uint16(b[94:96]))
+	if info.VersionMajor == 1 && info.VersionMinor >= 4 && headerSize >= 375 && n >= 375 {
+		if extended := binary.LittleEndian.Uint64(b[247:255]); extended != 0 {
+			info.PointCount = extended
+		}
+	}
+	info.Compressed = isCompressedFile(fileName)
+	return &info, nil
+}
@@ -0,0 +1,64 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestProbeLas(t *testing.T) {
+	info, err := Probe("testdata/sample.las")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf, err := NewLasFile("testdata/sample.las", "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	compareProbe(t, info, lf.Header)
+	if info.Compressed {
+		t.Error("the LAS file was reported as compressed")
+	}
+}
+
+func TestProbeLaz(t *testing.T) {
+	requireSampleLaz(t)
+	info, err := Probe(sampleLazFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	compareProbe(t, info, lf.Header)
+	if !info.Compressed {
+		t.Error("the LAZ file was not reported as compressed")
+	}
+}
+
+func TestProbeNotLas(t *testing.T) {
+	if _, err := Probe("probe_test.go"); err == nil {
+		t.Error("expected an error for a file without the LASF signature")
+	}
+}
+
+func compareProbe(t *testing.T, info *FileInfo, h LasHeader) {
+	t.Helper()
+	if info.Signature != "LASF" {
+		t.Errorf("unexpected signature %q", info.Signature)
+	}
+	if info.VersionMajor != h.VersionMajor || info.VersionMinor != h.VersionMinor {
+		t.Errorf("version %v.%v, expected %v.%v", info.VersionMajor, info.VersionMinor, h.VersionMajor, h.VersionMinor)
+	}
+	if info.PointFormatID != h.PointFormatID {
+		t.Errorf("point format %v, expected %v", info.PointFormatID, h.PointFormatID)
+	}
+	if info.PointCount != uint64(h.NumberPoints) {
+		t.Errorf("point count %v, expected %v", info.PointCount, h.NumberPoints)
+	}
+	if info.MinX != h.MinX || info.MaxX != h.MaxX || info.MinY != h.MinY ||
+		info.MaxY != h.MaxY || info.MinZ != h.MinZ || info.MaxZ != h.MaxZ {
+		t.Errorf("bounds %+v do not match the header", info)
+	}
+}
@@ -0,0 +1,56 @@
+package lidario
+
+// ProgressFunc reports the progress of a long read: done points of total have
+// been processed. See LazFile.SetProgressFunc.
+type ProgressFunc func(done, total uint64)
+
+// progressInterval is the number of points between calls to a ProgressFunc,
+// which keeps the cost of reporting negligible.
+const progressInterval = 100000
+
+// progress calls a ProgressFunc as points are processed. A nil function is
+// never called.
+type progress struct {
+	fn       ProgressFunc
+	total    uint64
+	done     uint64
+	reported uint64
+}
+
+func newProgress(fn ProgressFunc, total int) progress {
+	return progress{fn: fn, total: uint64(total)}
+}
+
+// add records that n more points have been processed.
+func (p *progress) add(n int) {
+	p.done += uint64(n)
+	if p.fn != nil && p.done-p.reported >= progressInterval {
+		p.reported = p.done
+		p.fn(p.done, p.total)
+	}
+}
+
+// finish reports the completion of the operation. The total becomes the
+// number of points processed, since fewer points than expected remain at the
+// end of a file, and the number of points of a file written by a streaming
+// writer is not known in advance.
+func (p *progress) finish() {
+	if p.fn == nil {
+		return
+	}
+	p.total = p.done
+	p.reported = p.done
+	p.fn(p.done, p.total)
+}
+
+// SetProgressFunc sets a function that is called as the points are read by
+// ReadPoints, ReadColumnar and the PointIterator returned by Points, and so by
+// the operations built on them such as ComputeStatistics and ExportXYZ. The
+// function is called every 100,000 points and once when the read completes,
+// at which point done equals total. It must not call the methods of the file.
+// A nil function disables progress reporting.
+func (lf *LazFile) SetProgressFunc(fn ProgressFunc) {
+	lf.Lock()
+	defer lf.Unlock()
+	lf.progress = fn
+}
@@ -0,0 +1,66 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	type call struct{ done, total uint64 }
+	calls := []call{}
+	p := newProgress(func(done, total uint64) { calls = append(calls, call{done, total}) }, 250000)
+	for remaining := 250000; remaining > 0; remaining -= 4096 {
+		n := 4096
+		if remaining < n {
+			n = remaining
+		}
+		p.add(n)
+	}
+	p.finish()
+	if len(calls) != 3 {
+		t.Fatalf("expected 2 periodic calls and a final call, got %v", calls)
+	}
+	if calls[0].done < progressInterval || calls[0].total != 250000 {
+		t.Errorf("unexpected first call %v", calls[0])
+	}
+	if last := calls[len(calls)-1]; last.done != 250000 || last.total != 250000 {
+		t.Errorf("the final call %v should report done equal to total", last)
+	}
+
+	// A nil function is never called.
+	p = newProgress(nil, 10)
+	p.add(progressInterval)
+	p.finish()
+}
+
+func TestLazProgress(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	var calls int
+	var done, total uint64
+	lf.SetProgressFunc(func(d, t uint64) {
+		calls++
+		done, total = d, t
+	})
+	if _, err = lf.ComputeStatistics(); err != nil {
+		t.Fatal(err)
+	}
+	if done != total || total != uint64(lf.Header.NumberPoints) {
+		t.Errorf("the final call reported %v of %v points, expected %v", done, total, lf.Header.NumberPoints)
+	}
+	if max := lf.Header.NumberPoints/progressInterval + 1; calls > max {
+		t.Errorf("the callback was called %v times, expected at most %v", calls, max)
+	}
+
+	calls = 0
+	if _, err = lf.ReadPoints(0, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || done != 1000 || total != 1000 {
+		t.Errorf("ReadPoints: %v calls reporting %v of %v points, expected a single call for 1000 points", calls, done, total)
+	}
+}
@@ -0,0 +1,139 @@
+package lidario
+
+import (
+	"errors"
+	"math"
+)
+
+// Grid is the georeference of a raster: its size, cell size and the position
+// of its north-west corner. Row 0 is the northern-most row and column 0 the
+// western-most column, matching the usual GIS convention.
+type Grid struct {
+	Rows     int
+	Columns  int
+	CellSize float64
+	MinX     float64 // western edge of the grid
+	MaxY     float64 // northern edge of the grid
+}
+
+// Raster is a simple georeferenced grid of cell values.
+type Raster struct {
+	Grid
+	NoData float64
+	Data   []float64
+}
+
+// CountRaster is a georeferenced grid of point counts.
+type CountRaster struct {
+	Grid
+	Counts []uint32
+}
+
+// newGrid creates a grid covering the given extent with the specified cell size.
+func newGrid(minX, minY, maxX, maxY, cellSize float64) (Grid, error) {
+	if cellSize <= 0 || math.IsNaN(cellSize) || math.IsInf(cellSize, 0) {
+		return Grid{}, errors.New("cell size must be a positive, finite value")
+	}
+	if maxX < minX || maxY < minY {
+		return Grid{}, errors.New("invalid raster extent")
+	}
+	g := Grid{
+		Rows:     int(math.Floor((maxY-minY)/cellSize)) + 1,
+		Columns:  int(math.Floor((maxX-minX)/cellSize)) + 1,
+		CellSize: cellSize,
+		MinX:     minX,
+		MaxY:     maxY,
+	}
+	return g, nil
+}
+
+// newRaster creates a raster covering the given extent with the specified cell size.
+func newRaster(minX, minY, maxX, maxY, cellSize float64) (*Raster, error) {
+	g, err := newGrid(minX, minY, maxX, maxY, cellSize)
+	if err != nil {
+		return nil, err
+	}
+	r := Raster{
+		Grid:   g,
+		NoData: NoData,
+		Data:   make([]float64, g.Rows*g.Columns),
+	}
+	return &r, nil
+}
+
+// MinY returns the southern edge of the grid.
+func (g *Grid) MinY() float64 {
+	return g.MaxY - float64(g.Rows)*g.CellSize
+}
+
+// MaxX returns the eastern edge of the grid.
+func (g *Grid) MaxX() float64 {
+	return g.MinX + float64(g.Columns)*g.CellSize
+}
+
+// contains returns true if the cell lies within the grid.
+func (g *Grid) contains(row, column int) bool {
+	return row >= 0 && row < g.Rows && column >= 0 && column < g.Columns
+}
+
+// CellOf returns the row and column containing the point (x, y). The
+// returned boolean is false if the point lies outside of the grid.
+func (g *Grid) CellOf(x, y float64) (row, column int, ok bool) {
+	column = int(math.Floor((x - g.MinX) / g.CellSize))
+	row = int(math.Floor((g.MaxY - y) / g.CellSize))
+	return row, column, g.contains(row, column)
+}
+
+// CellCenter returns the coordinates of the centre of a grid cell.
+func (g *Grid) CellCenter(row, column int) (x, y float64) {
+	x = g.MinX + (float64(column)+0.5)*g.CellSize
+	y = g.MaxY - (float64(row)+0.5)*g.CellSize
+	return
+}
+
+// Value returns the value of a grid cell, or NoData if the cell is outside the grid.
+func (r *Raster) Value(row, column int) float64 {
+	if !r.contains(row, column) {
+		return r.NoData
+	}
+	return r.Data[row*r.Columns+column]
+}
+
+// SetValue sets the value of a grid cell. Cells outside of the grid are ignored.
+func (r *Raster) SetValue(row, column int, value float64) {
+	if !r.contains(row, column) {
+		return
+	}
+	r.Data[row*r.Columns+column] = value
+}
+
+// Count returns the number of points in a grid cell, or zero if the cell is
+// outside the grid.
+func (r *CountRaster) Count(row, column int) uint32 {
+	if !r.contains(row, column) {
+		return 0
+	}
+	return r.Counts[row*r.Columns+column]
+}
+
+// CountGrid returns a coverage raster holding the number of points that fall
+// within each XY cell. The grid is anchored on the header's minimum X and
+// maximum Y and the counts are accumulated in a single pass over the points.
+func (las *LasFile) CountGrid(cellSize float64) (*CountRaster, error) {
+	if las.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	g, err := newGrid(las.Header.MinX, las.Header.MinY, las.Header.MaxX, las.Header.MaxY, cellSize)
+	if err != nil {
+		return nil, err
+	}
+	r := CountRaster{Grid: g, Counts: make([]uint32, g.Rows*g.Columns)}
+	for i := range las.pointData {
+		row, column, ok := r.CellOf(las.pointData[i].X, las.pointData[i].Y)
+		if !ok {
+			continue
+		}
+		r.Counts[row*r.Columns+column]++
+	}
+	return &r, nil
+}
@@ -0,0 +1,48 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestCountGrid(t *testing.T) {
+	// Five points fall within the south-west cell and a single point in the
+	// north-east cell of a 2 x 2 grid of 10 m cells.
+	var points []LasPointer
+	for i := 0; i < 5; i++ {
+		points = append(points, &PointRecord0{X: 1.5 + float64(i), Y: 2.5, Z: 1.0})
+	}
+	points = append(points, &PointRecord0{X: 18.5, Y: 17.5, Z: 1.0})
+	lf := createTestLasFile(t, 0, points)
+
+	r, err := lf.CountGrid(10.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Rows != 2 || r.Columns != 2 {
+		t.Fatalf("expected a 2 x 2 grid, got %v x %v", r.Rows, r.Columns)
+	}
+
+	row, column, ok := r.CellOf(3.0, 3.0)
+	if !ok {
+		t.Fatal("expected the point to fall within the grid")
+	}
+	if count := r.Count(row, column); count != 5 {
+		t.Errorf("expected 5 points in the south-west cell, got %v", count)
+	}
+	row, column, _ = r.CellOf(18.0, 17.0)
+	if count := r.Count(row, column); count != 1 {
+		t.Errorf("expected 1 point in the north-east cell, got %v", count)
+	}
+
+	var total uint32
+	for _, v := range r.Counts {
+		total += v
+	}
+	if int(total) != len(points) {
+		t.Errorf("expected the grid to account for %v points, got %v", len(points), total)
+	}
+
+	if _, err = lf.CountGrid(0); err == nil {
+		t.Error("expected an error for a zero cell size")
+	}
+}
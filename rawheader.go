@@ -0,0 +1,178 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// rawReader reads the raw bytes of a file, for the records and metadata that
+// LASzip does not expose.
+type rawReader interface {
+	io.ReaderAt
+	io.Closer
+	Size() int64
+}
+
+// rawFile is a rawReader reading a file on disk.
+type rawFile struct {
+	*os.File
+	size int64
+}
+
+func (f *rawFile) Size() int64 {
+	return f.size
+}
+
+func openRawFile(fileName string) (*rawFile, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &rawFile{File: f, size: info.Size()}, nil
+}
+
+// openRaw opens the raw bytes of the file or, for a LazFile created by
+// NewLazFileFromReader, those of its stream.
+func (lf *LazFile) openRaw() (rawReader, error) {
+	if lf.source != nil {
+		return lf.source, nil
+	}
+	f, err := openRawFile(lf.fileName)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// RawHeaderBytes returns the exact HeaderSize bytes from the start of the
+// file, which is useful for debugging and for validating a header against
+// the specification with external tools.
+func (las *LasFile) RawHeaderBytes() ([]byte, error) {
+	f, err := openRawFile(las.fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readRawHeader(f, las.Header.HeaderSize)
+}
+
+// RawHeaderBytes returns the exact HeaderSize bytes from the start of the
+// file. The bytes are read directly from the file rather than from LASzip.
+func (lf *LazFile) RawHeaderBytes() ([]byte, error) {
+	r, err := lf.openRaw()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return readRawHeader(r, lf.Header.HeaderSize)
+}
+
+// readRawHeader reads the first size bytes of r.
+func readRawHeader(r io.ReaderAt, size int) ([]byte, error) {
+	if size <= 0 {
+		return nil, errors.New("the header size is unknown")
+	}
+	b := make([]byte, size)
+	if _, err := r.ReadAt(b, 0); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// readRawVLR returns the payload of the first VLR of r with the given user ID
+// and record ID.
+func readRawVLR(r io.ReaderAt, userID string, recordID int) ([]byte, error) {
+	header := make([]byte, 104)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	if string(header[0:4]) != "LASF" {
+		return nil, errors.New("the file is not a LAS/LAZ file")
+	}
+	offset := int64(binary.LittleEndian.Uint16(header[94:96]))
+	numberOfVLRs := int(binary.LittleEndian.Uint32(header[100:104]))
+	vlrHeader := make([]byte, 54)
+	for i := 0; i < numberOfVLRs; i++ {
+		if _, err := r.ReadAt(vlrHeader, offset); err != nil {
+			return nil, fmt.Errorf("reading VLR %v: %v", i, err)
+		}
+		length := int64(binary.LittleEndian.Uint16(vlrHeader[20:22]))
+		if strings.TrimRight(string(vlrHeader[2:18]), "\x00 ") == userID &&
+			int(binary.LittleEndian.Uint16(vlrHeader[18:20])) == recordID {
+			b := make([]byte, length)
+			if _, err := r.ReadAt(b, offset+54); err != nil {
+				return nil, fmt.Errorf("reading VLR %v: %v", i, err)
+			}
+			return b, nil
+		}
+		offset += 54 + length
+	}
+	return nil, fmt.Errorf("the file does not contain a %v/%v VLR", userID, recordID)
+}
+
+// validatePointDataHeader checks, before a file is handed to LASzip, that the
+// header describes point data that can exist: the point data must start
+// within the file and the record length must fit the point format.
+func validatePointDataHeader(r io.ReaderAt, size int64) error {
+	header := make([]byte, 107)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return fmt.Errorf("%w: the file is too short to hold a header", ErrCorruptFile)
+	}
+	if string(header[0:4]) != "LASF" {
+		return fmt.Errorf("%w: the file signature is not LASF", ErrCorruptFile)
+	}
+	offset := int64(binary.LittleEndian.Uint32(header[96:100]))
+	if offset > size {
+		return fmt.Errorf("%w: the offset to the point data (%v) lies beyond the end of the file (%v bytes)",
+			ErrCorruptFile, offset, size)
+	}
+	format := header[104] & 0x3F // bits 6 and 7 flag compression
+	if int(format) >= len(pointFormatRecordLengths) {
+		return fmt.Errorf("%w: unknown point format %v", ErrCorruptFile, format)
+	}
+	length := int(binary.LittleEndian.Uint16(header[105:107]))
+	if length < pointFormatRecordLengths[format] {
+		return fmt.Errorf("%w: the point record length (%v) is too short for point format %v, which needs %v bytes",
+			ErrCorruptFile, length, format, pointFormatRecordLengths[format])
+	}
+	return nil
+}
+
+// rawVLRLayout walks the VLR headers of r, including the LASzip VLR that
+// LASzip hides, and returns the header size and the offset of the end of the
+// last VLR. The walk stops early, returning the number of VLRs found, if a VLR
+// would extend beyond limit.
+func rawVLRLayout(r io.ReaderAt, limit int64) (headerSize, end int64, found int, err error) {
+	header := make([]byte, 104)
+	if _, err = r.ReadAt(header, 0); err != nil {
+		return 0, 0, 0, err
+	}
+	headerSize = int64(binary.LittleEndian.Uint16(header[94:96]))
+	numberOfVLRs := int(binary.LittleEndian.Uint32(header[100:104]))
+	end = headerSize
+	vlrHeader := make([]byte, 54)
+	for found < numberOfVLRs {
+		if end+54 > limit {
+			break
+		}
+		if _, err = r.ReadAt(vlrHeader, end); err != nil {
+			break
+		}
+		length := int64(binary.LittleEndian.Uint16(vlrHeader[20:22]))
+		if end+54+length > limit {
+			break
+		}
+		end += 54 + length
+		found++
+	}
+	return headerSize, end, found, nil
+}
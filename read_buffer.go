@@ -0,0 +1,222 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// defaultReadBufferSize is the number of bytes of point records read from the
+// file at a time by the buffered record reader.
+const defaultReadBufferSize = 1 << 20
+
+// ReaderOption configures how a LAS file is read.
+type ReaderOption func(*readerOptions)
+
+type readerOptions struct {
+	bufferSize int
+	mmap       bool
+	buffered   bool
+}
+
+// WithReadBufferSize sets the number of bytes of point records read from the
+// file at a time when scanning the points sequentially, e.g. with a
+// CoordinateIterator. The size is rounded down to a whole number of records
+// and at least one record is always read. Larger buffers trade memory for
+// fewer read system calls.
+func WithReadBufferSize(n int) ReaderOption {
+	return func(o *readerOptions) {
+		o.bufferSize = n
+	}
+}
+
+// WithBufferedReads makes GetXYZ work for LAS files opened in 'rh' mode by
+// reading the point records from the file on demand. Sequential access is
+// served from a buffer of WithReadBufferSize bytes of records, so a full scan
+// issues one read per block rather than one per point; any other access reads
+// the requested record on its own. A memory-mapped file (see WithMmap) is read
+// from the mapping instead. The option does not apply to LAZ files.
+func WithBufferedReads() ReaderOption {
+	return func(o *readerOptions) {
+		o.buffered = true
+	}
+}
+
+func newReaderOptions(opts []ReaderOption) readerOptions {
+	o := readerOptions{bufferSize: defaultReadBufferSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// recordReader reads point records directly from a LAS file. Sequential
+// access is served from a buffer holding a block of consecutive records,
+// which is refilled with a single read when the scan moves past it. Any
+// other access falls back to reading the requested record on its own.
+type recordReader struct {
+	r            io.ReaderAt
+	pointsOffset int64
+	recordLength int
+	numPoints    int
+	buf          []byte
+	start        int // index of the first record held in buf
+	count        int // number of records held in buf
+	single       []byte
+	reads        int // number of reads issued, for diagnostics
+}
+
+func newRecordReader(las *LasFile) (*recordReader, error) {
+	if las.f == nil {
+		return nil, errors.New("the LAS reader is nil")
+	}
+	recLen := las.Header.PointRecordLength
+	if recLen <= 0 {
+		return nil, errors.New("invalid point record length")
+	}
+	size := las.readBufferSize
+	if size == 0 {
+		size = defaultReadBufferSize
+	}
+	records := size / recLen
+	if records < 1 {
+		records = 1
+	}
+	if records > las.Header.NumberPoints && las.Header.NumberPoints > 0 {
+		records = las.Header.NumberPoints
+	}
+	rr := recordReader{
+		r:            las.f,
+		pointsOffset: int64(las.Header.OffsetToPoints),
+		recordLength: recLen,
+		numPoints:    las.Header.NumberPoints,
+		buf:          make([]byte, records*recLen),
+		single:       make([]byte, recLen),
+	}
+	return &rr, nil
+}
+
+// record returns the bytes of point record i. The returned slice is only valid
+// until the next call.
+func (rr *recordReader) record(i int) ([]byte, error) {
+	if i < 0 || i >= rr.numPoints {
+		return nil, errors.New("Index outside of allowable range")
+	}
+	if i >= rr.start && i < rr.start+rr.count {
+		o := (i - rr.start) * rr.recordLength
+		return rr.buf[o : o+rr.recordLength], nil
+	}
+	if i == rr.start+rr.count {
+		// The scan has moved onto the record following the buffer.
+		if err := rr.fill(i); err != nil {
+			return nil, err
+		}
+		return rr.buf[:rr.recordLength], nil
+	}
+	// Random access; read just the requested record.
+	if err := rr.readAt(rr.single, i); err != nil {
+		return nil, err
+	}
+	return rr.single, nil
+}
+
+// fill reads the block of records starting at record i into the buffer.
+func (rr *recordReader) fill(i int) error {
+	n := rr.numPoints - i
+	if max := len(rr.buf) / rr.recordLength; n > max {
+		n = max
+	}
+	if err := rr.readAt(rr.buf[:n*rr.recordLength], i); err != nil {
+		rr.count = 0
+		return err
+	}
+	rr.start = i
+	rr.count = n
+	return nil
+}
+
+func (rr *recordReader) readAt(b []byte, i int) error {
+	rr.reads++
+	pos := rr.pointsOffset + int64(i)*int64(rr.recordLength)
+	if _, err := rr.r.ReadAt(b, pos); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return nil
+}
+
+// bufferedXYZ decodes the coordinates of a point record read through the
+// record reader of the file, which is created on first use.
+func (las *LasFile) bufferedXYZ(index int) (float64, float64, float64, error) {
+	las.Lock()
+	defer las.Unlock()
+	h := &las.Header
+	if h.PointRecordLength < 12 {
+		return NoData, NoData, NoData, errors.New("invalid point record length")
+	}
+	if las.rr == nil {
+		rr, err := newRecordReader(las)
+		if err != nil {
+			return NoData, NoData, NoData, err
+		}
+		las.rr = rr
+	}
+	b, err := las.rr.record(index)
+	if err != nil {
+		return NoData, NoData, NoData, err
+	}
+	x := float64(int32(binary.LittleEndian.Uint32(b[0:4])))*h.XScaleFactor + h.XOffset
+	y := float64(int32(binary.LittleEndian.Uint32(b[4:8])))*h.YScaleFactor + h.YOffset
+	z := float64(int32(binary.LittleEndian.Uint32(b[8:12])))*h.ZScaleFactor + h.ZOffset
+	return x, y, z, nil
+}
+
+// decodeRecord decodes a point record of format 0-3 read through a record
+// reader, as the points of a file opened in 'r' mode are decoded.
+func (las *LasFile) decodeRecord(b []byte) LasPointer {
+	h := &las.Header
+	le := binary.LittleEndian
+	usePointIntensity, usePointUserdata := h.optionalFields()
+	p := PointRecord0{
+		X: float64(int32(le.Uint32(b[0:4])))*h.XScaleFactor + h.XOffset,
+		Y: float64(int32(le.Uint32(b[4:8])))*h.YScaleFactor + h.YOffset,
+		Z: float64(int32(le.Uint32(b[8:12])))*h.ZScaleFactor + h.ZOffset,
+	}
+	offset := 12
+	if usePointIntensity {
+		p.Intensity = le.Uint16(b[offset : offset+2])
+		offset += 2
+	}
+	p.BitField = PointBitField{Value: b[offset]}
+	p.ClassBitField = ClassificationBitField{Value: b[offset+1]}
+	p.ScanAngle = int8(b[offset+2])
+	offset += 3
+	if usePointUserdata {
+		p.UserData = b[offset]
+		offset++
+	}
+	p.PointSourceID = le.Uint16(b[offset : offset+2])
+	offset += 2
+
+	var gpsTime float64
+	if h.PointFormatID == 1 || h.PointFormatID == 3 {
+		gpsTime = math.Float64frombits(le.Uint64(b[offset : offset+8]))
+		offset += 8
+	}
+	var rgb RgbData
+	if h.PointFormatID == 2 || h.PointFormatID == 3 {
+		rgb = RgbData{Red: le.Uint16(b[offset : offset+2]), Green: le.Uint16(b[offset+2 : offset+4]), Blue: le.Uint16(b[offset+4 : offset+6])}
+	}
+	switch h.PointFormatID {
+	case 1:
+		return &PointRecord1{PointRecord0: &p, GPSTime: gpsTime}
+	case 2:
+		return &PointRecord2{PointRecord0: &p, RGB: &rgb}
+	case 3:
+		return &PointRecord3{PointRecord0: &p, GPSTime: gpsTime, RGB: &rgb}
+	}
+	return &p
+}
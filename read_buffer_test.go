@@ -0,0 +1,187 @@
+package lidario
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestRecordReader(t *testing.T) {
+	// The sample file uses 28-byte format 1 records; the size is rounded down.
+	lf, err := NewLasFile("testdata/sample.las", "rh", WithReadBufferSize(1000*28+10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	rr, err := newRecordReader(lf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr.buf) != 1000*lf.Header.PointRecordLength {
+		t.Fatalf("expected a buffer of 1000 records, got %v bytes", len(rr.buf))
+	}
+
+	// A sequential scan of 2500 records needs three buffer fills.
+	for i := 0; i < 2500; i++ {
+		if _, err := rr.record(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rr.reads != 3 {
+		t.Errorf("expected 3 reads for a sequential scan, got %v", rr.reads)
+	}
+
+	// Random access reads the single record and leaves the buffer intact.
+	far, err := rr.record(2000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	farCopy := append([]byte(nil), far...)
+	near, err := rr.record(2400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr.reads != 4 {
+		t.Errorf("expected a single additional read for random access, got %v reads", rr.reads-3)
+	}
+
+	want := make([]byte, lf.Header.PointRecordLength)
+	for i, got := range map[int][]byte{2000000: farCopy, 2400: near} {
+		if _, err := lf.f.ReadAt(want, int64(lf.Header.OffsetToPoints)+int64(i*lf.Header.PointRecordLength)); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("record %v does not match the file contents", i)
+		}
+	}
+
+	if _, err = rr.record(lf.Header.NumberPoints); err == nil {
+		t.Error("expected an error for an out of range record")
+	}
+}
+
+func TestBufferedGetXYZ(t *testing.T) {
+	full, err := NewLasFile("testdata/sample.las", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer full.Close()
+
+	// Without the option a header-only file holds no points.
+	lf, err := NewLasFile("testdata/sample.las", "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err = lf.GetXYZ(0); err != errHeaderOnly {
+		t.Errorf("expected the header-only error, got %v", err)
+	}
+	lf.Close()
+
+	lf, err = NewLasFile("testdata/sample.las", "rh", WithBufferedReads(), WithReadBufferSize(1000*28))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	// A sequential scan followed by random access outside of the buffer,
+	// which holds points 2000-2999 after the scan, and within it.
+	indices := []int{}
+	for i := 0; i < 2500; i++ {
+		indices = append(indices, i)
+	}
+	indices = append(indices, 2000000, 1500, lf.Header.NumberPoints-1, 2999)
+	for _, i := range indices {
+		x, y, z, err := lf.GetXYZ(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wx, wy, wz, _ := full.GetXYZ(i)
+		if x != wx || y != wy || z != wz {
+			t.Fatalf("point %v: (%v, %v, %v), expected (%v, %v, %v)", i, x, y, z, wx, wy, wz)
+		}
+	}
+	if lf.rr.reads != 6 {
+		t.Errorf("expected 3 block reads and 3 single reads, got %v reads", lf.rr.reads)
+	}
+	if _, _, _, err = lf.GetXYZ(lf.Header.NumberPoints); err == nil {
+		t.Error("expected an error for an out of range point")
+	}
+}
+
+// BenchmarkSequentialGetXYZ measures the throughput of a sequential scan of a
+// header-only file with GetXYZ, reading one record at a time and with the
+// default buffer.
+func BenchmarkSequentialGetXYZ(b *testing.B) {
+	for _, size := range []int{1, defaultReadBufferSize} {
+		b.Run(fmt.Sprintf("buffer=%v", size), func(b *testing.B) {
+			lf, err := NewLasFile("testdata/sample.las", "rh", WithBufferedReads(), WithReadBufferSize(size))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer lf.Close()
+			b.SetBytes(int64(lf.Header.NumberPoints * lf.Header.PointRecordLength))
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				for i := 0; i < lf.Header.NumberPoints; i++ {
+					if _, _, _, err := lf.GetXYZ(i); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSequentialScan compares the number of reads issued for a full scan
+// of the points with a single-record buffer and with the default buffer.
+func BenchmarkSequentialScan(b *testing.B) {
+	for _, size := range []int{1, defaultReadBufferSize} {
+		b.Run(fmt.Sprintf("buffer=%v", size), func(b *testing.B) {
+			lf, err := NewLasFile("testdata/sample.las", "rh", WithReadBufferSize(size))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer lf.Close()
+			b.ResetTimer()
+			var reads int
+			for n := 0; n < b.N; n++ {
+				it, err := lf.CoordinateIterator()
+				if err != nil {
+					b.Fatal(err)
+				}
+				for it.Next() {
+				}
+				if it.Err() != nil {
+					b.Fatal(it.Err())
+				}
+				reads += it.rr.reads
+			}
+			b.ReportMetric(float64(reads)/float64(b.N), "reads/op")
+		})
+	}
+}
+
+func TestDecodeRecord(t *testing.T) {
+	las, err := NewLasFile("testdata/sample.las", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+	rr, err := newRecordReader(las)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < las.Header.NumberPoints; i += 101 {
+		b, err := rr.record(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := las.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := las.decodeRecord(b)
+		if got.Format() != want.Format() || *got.PointData() != *want.PointData() || got.GpsTimeData() != want.GpsTimeData() {
+			t.Fatalf("point %v: got %+v, expected %+v", i, got.PointData(), want.PointData())
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoTransformer is returned when coordinates must be reprojected but no
+// Transformer has been set with SetTransformer.
+var ErrNoTransformer = errors.New("no coordinate transformer has been set")
+
+// Transformer transforms coordinates between coordinate reference systems. The
+// library does not include a projection engine; implement Transformer with a
+// binding to one, such as PROJ. The source CRS is given as returned by GetCRS,
+// i.e. either "EPSG:<code>" or an OGC WKT string.
+type Transformer interface {
+	Transform(sourceCRS string, targetEPSG int, x, y, z float64) (float64, float64, float64, error)
+}
+
+// IdentityTransformer returns coordinates unchanged. It is used when the
+// coordinate system of a file is unknown.
+type IdentityTransformer struct{}
+
+// Transform returns x, y and z unchanged.
+func (IdentityTransformer) Transform(sourceCRS string, targetEPSG int, x, y, z float64) (float64, float64, float64, error) {
+	return x, y, z, nil
+}
+
+// SetTransformer sets the Transformer used by ReprojectXYZ and ReprojectAll.
+func (lf *LazFile) SetTransformer(t Transformer) {
+	lf.Lock()
+	defer lf.Unlock()
+	lf.transformer = t
+}
+
+// transformerFor returns the source CRS and the transformer that converts
+// the coordinates of the file to targetEPSG. The identity transformer is
+// returned if the CRS of the file is unknown or is already targetEPSG.
+func (lf *LazFile) transformerFor(targetEPSG int) (string, Transformer, error) {
+	crs, err := lf.GetCRS()
+	if err == ErrNoCRS || crs == fmt.Sprintf("EPSG:%v", targetEPSG) {
+		return crs, IdentityTransformer{}, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	lf.RLock()
+	t := lf.transformer
+	lf.RUnlock()
+	if t == nil {
+		return "", nil, ErrNoTransformer
+	}
+	return crs, t, nil
+}
+
+// ReprojectXYZ returns the coordinates of a point transformed to the
+// coordinate system targetEPSG. See SetTransformer.
+func (lf *LazFile) ReprojectXYZ(pointIndex int, targetEPSG int) (float64, float64, float64, error) {
+	crs, t, err := lf.transformerFor(targetEPSG)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	x, y, z, err := lf.GetXYZ(pointIndex)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return t.Transform(crs, targetEPSG, x, y, z)
+}
+
+// ReprojectAll returns the coordinates of every point transformed to the
+// coordinate system targetEPSG, reading the points sequentially.
+func (lf *LazFile) ReprojectAll(targetEPSG int) ([][3]float64, error) {
+	crs, t, err := lf.transformerFor(targetEPSG)
+	if err != nil {
+		return nil, err
+	}
+	it, err := lf.Points()
+	if err != nil {
+		return nil, err
+	}
+	coords := make([][3]float64, 0, lf.GetPointCount64())
+	for it.Next() {
+		pd := it.Point().PointData()
+		x, y, z, err := t.Transform(crs, targetEPSG, pd.X, pd.Y, pd.Z)
+		if err != nil {
+			return nil, fmt.Errorf("transforming point %v: %w", len(coords), err)
+		}
+		coords = append(coords, [3]float64{x, y, z})
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return coords, nil
+}
@@ -0,0 +1,73 @@
+package lidario
+
+import (
+	"testing"
+)
+
+// shiftTransformer is a stub Transformer that shifts the coordinates and
+// records its calls.
+type shiftTransformer struct {
+	calls     int
+	sourceCRS string
+	target    int
+}
+
+func (st *shiftTransformer) Transform(sourceCRS string, targetEPSG int, x, y, z float64) (float64, float64, float64, error) {
+	st.calls++
+	st.sourceCRS, st.target = sourceCRS, targetEPSG
+	return x + 1000, y + 2000, z + 3, nil
+}
+
+func TestTransformerFor(t *testing.T) {
+	lf := &LazFile{fileMode: "r"}
+	if _, tr, err := lf.transformerFor(4326); err != nil || tr != (IdentityTransformer{}) {
+		t.Errorf("expected the identity transformer for an unknown CRS, got %v, %v", tr, err)
+	}
+
+	lf.geokeys.WKT = `PROJCS["ETRS89 / UTM zone 30N"]`
+	if _, _, err := lf.transformerFor(4326); err != ErrNoTransformer {
+		t.Errorf("expected ErrNoTransformer, got %v", err)
+	}
+	st := &shiftTransformer{}
+	lf.SetTransformer(st)
+	crs, tr, err := lf.transformerFor(4326)
+	if err != nil || tr != st || crs != lf.geokeys.WKT {
+		t.Errorf("expected the stub transformer and the WKT, got %v, %v, %v", crs, tr, err)
+	}
+}
+
+func TestReproject(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	lf.geokeys = GeoKeys{WKT: `PROJCS["ETRS89 / UTM zone 30N"]`}
+	st := &shiftTransformer{}
+	lf.SetTransformer(st)
+
+	x, y, z, err := lf.GetXYZ(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, ty, tz, err := lf.ReprojectXYZ(3, 4326)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx != x+1000 || ty != y+2000 || tz != z+3 || st.calls != 1 || st.target != 4326 || st.sourceCRS != lf.geokeys.WKT {
+		t.Errorf("unexpected transformation (%v, %v, %v) with %+v", tx, ty, tz, st)
+	}
+
+	st.calls = 0
+	coords, err := lf.ReprojectAll(4326)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coords) != int(lf.GetPointCount()) || st.calls != len(coords) {
+		t.Errorf("expected %v transformed points, got %v from %v calls", lf.GetPointCount(), len(coords), st.calls)
+	}
+	if coords[3] != [3]float64{tx, ty, tz} {
+		t.Errorf("point 3: ReprojectAll returned %v, ReprojectXYZ (%v, %v, %v)", coords[3], tx, ty, tz)
+	}
+}
@@ -0,0 +1,45 @@
+package lidario
+
+// ReadFirstReturns returns the first returns of the file, the points whose
+// return number is 1, reading the file sequentially. Single returns are both
+// first and last returns.
+func (lf *LazFile) ReadFirstReturns() ([]LasPointer, error) {
+	return lf.readPointsWhere(isFirstReturn)
+}
+
+// ReadLastReturns returns the last returns of the file, the points whose
+// return number equals their number of returns, reading the file
+// sequentially. Single returns are both first and last returns.
+func (lf *LazFile) ReadLastReturns() ([]LasPointer, error) {
+	return lf.readPointsWhere(isLastReturn)
+}
+
+// isFirstReturn and isLastReturn test the return numbers as decoded by
+// LASzip, which hold the extended return numbers of point formats 6-10. A
+// point with a zero return number is neither.
+func isFirstReturn(lp *LaszipPoint) bool {
+	return lp.ReturnNumber == 1
+}
+
+func isLastReturn(lp *LaszipPoint) bool {
+	return lp.ReturnNumber >= 1 && lp.ReturnNumber == lp.NumberOfReturns
+}
+
+// readPointsWhere returns the points for which keep returns true, reading the
+// file sequentially.
+func (lf *LazFile) readPointsWhere(keep func(*LaszipPoint) bool) ([]LasPointer, error) {
+	it, err := lf.Points()
+	if err != nil {
+		return nil, err
+	}
+	points := []LasPointer{}
+	for it.Next() {
+		if keep(it.laszipPoint()) {
+			points = append(points, it.Point())
+		}
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return points, nil
+}
@@ -0,0 +1,59 @@
+package lidario
+
+import "testing"
+
+func TestReturnPredicates(t *testing.T) {
+	for _, c := range []struct {
+		returnNumber, numberOfReturns uint8
+		first, last                   bool
+	}{
+		{1, 1, true, true},
+		{1, 3, true, false},
+		{2, 3, false, false},
+		{3, 3, false, true},
+		// Extended return numbers of point formats 6-10.
+		{15, 15, false, true},
+		// A zero return number is invalid, even with zero returns.
+		{0, 0, false, false},
+	} {
+		lp := &LaszipPoint{ReturnNumber: c.returnNumber, NumberOfReturns: c.numberOfReturns}
+		if isFirstReturn(lp) != c.first || isLastReturn(lp) != c.last {
+			t.Errorf("return %v of %v: first %v and last %v, expected %v and %v", c.returnNumber, c.numberOfReturns,
+				isFirstReturn(lp), isLastReturn(lp), c.first, c.last)
+		}
+	}
+}
+
+func TestReadFirstAndLastReturns(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	first, err := lf.ReadFirstReturns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range first {
+		if bf := p.PointData().BitField; bf.ReturnNumber() != 1 {
+			t.Fatalf("point %v is return %v of %v", i, bf.ReturnNumber(), bf.NumberOfReturns())
+		}
+	}
+	last, err := lf.ReadLastReturns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range last {
+		if bf := p.PointData().BitField; bf.ReturnNumber() != bf.NumberOfReturns() {
+			t.Fatalf("point %v is return %v of %v", i, bf.ReturnNumber(), bf.NumberOfReturns())
+		}
+	}
+	if len(first) == 0 || len(last) == 0 || len(first) > int(lf.GetPointCount()) || len(last) > int(lf.GetPointCount()) {
+		t.Errorf("%v first and %v last returns of %v points", len(first), len(last), lf.GetPointCount())
+	}
+	if byReturn := lf.Header.NumberPointsByReturn[0]; byReturn != 0 && byReturn != len(first) {
+		t.Errorf("%v first returns, expected %v from the header", len(first), byReturn)
+	}
+}
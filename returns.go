@@ -0,0 +1,41 @@
+package lidario
+
+// PointsByReturn returns the number of points of each return number; slots 6
+// to 15 are only used by the point formats of LAS 1.4. The counts in the
+// header are returned unless they are all zero, as many LAS 1.0 and 1.1 files
+// leave them, in which case the returns are counted with a single pass over
+// the points. The counts are computed once and cached.
+func (lf *LazFile) PointsByReturn() ([15]int, error) {
+	lf.RLock()
+	cached := lf.pointsByReturn
+	lf.RUnlock()
+	if cached != nil {
+		return *cached, nil
+	}
+
+	counts := lf.Header.NumberPointsByReturn
+	if counts == [15]int{} {
+		it, err := lf.Points()
+		if err != nil {
+			return counts, err
+		}
+		for it.Next() {
+			r := it.laszipPoint().ReturnNumber
+			if r == 0 {
+				// As in PointBitField.ReturnNumber, a zero return number
+				// counts as a first return.
+				r = 1
+			}
+			if r <= 15 {
+				counts[r-1]++
+			}
+		}
+		if it.Err() != nil {
+			return [15]int{}, it.Err()
+		}
+	}
+	lf.Lock()
+	lf.pointsByReturn = &counts
+	lf.Unlock()
+	return counts, nil
+}
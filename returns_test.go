@@ -0,0 +1,49 @@
+package lidario
+
+import (
+	"testing"
+)
+
+func TestPointsByReturnFromHeader(t *testing.T) {
+	// The header counts are used, so no reader is needed.
+	lf := &LazFile{fileMode: "r", Header: LasHeader{NumberPoints: 10, NumberPointsByReturn: [15]int{6, 3, 1}}}
+	counts, err := lf.PointsByReturn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts != lf.Header.NumberPointsByReturn {
+		t.Errorf("expected the header counts, got %v", counts)
+	}
+}
+
+func TestPointsByReturn(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	// Force the counts to be computed from the points.
+	lf.Header.NumberPointsByReturn = [15]int{}
+	counts, err := lf.PointsByReturn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := 0
+	for _, n := range counts {
+		sum += n
+	}
+	if sum != int(lf.GetPointCount()) {
+		t.Errorf("the return counts %v sum to %v, expected %v", counts, sum, lf.GetPointCount())
+	}
+
+	// A second call is answered from the cache.
+	reader := lf.reader
+	lf.reader = nil
+	again, err := lf.PointsByReturn()
+	lf.reader = reader
+	if err != nil || again != counts {
+		t.Errorf("expected the cached counts %v, got %v, %v", counts, again, err)
+	}
+}
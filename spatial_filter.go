@@ -0,0 +1,76 @@
+package lidario
+
+import (
+	"errors"
+	"math"
+)
+
+// ReadPointsInBounds returns the points whose XY coordinates lie within the
+// rectangle [minX, maxX] x [minY, maxY], edges included. The points are read
+// with a linear scan of the file; if the rectangle does not intersect the
+// extent in the header, no points are read and an empty slice is returned.
+func (lf *LazFile) ReadPointsInBounds(minX, minY, maxX, maxY float64) ([]LasPointer, error) {
+	if maxX < minX || maxY < minY {
+		return nil, errors.New("invalid bounding box")
+	}
+	points := []LasPointer{}
+	h := &lf.Header
+	if maxX < h.MinX || minX > h.MaxX || maxY < h.MinY || minY > h.MaxY {
+		return points, nil
+	}
+	it, err := lf.Points()
+	if err != nil {
+		return nil, err
+	}
+	for it.Next() {
+		p := it.Point()
+		pd := p.PointData()
+		if pd.X >= minX && pd.X <= maxX && pd.Y >= minY && pd.Y <= maxY {
+			points = append(points, p)
+		}
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return points, nil
+}
+
+// ReadPointsNear returns the points whose XY coordinates lie within radius of
+// (cx, cy), the circle included. As in ReadPointsInBounds, the points are read
+// with a linear scan of the file; if the circle does not intersect the extent
+// in the header, no points are read and an empty slice is returned.
+func (lf *LazFile) ReadPointsNear(cx, cy, radius float64) ([]LasPointer, error) {
+	if !(radius >= 0) {
+		return nil, errors.New("the radius must not be negative")
+	}
+	points := []LasPointer{}
+	h := &lf.Header
+	if !circleIntersectsRect(cx, cy, radius, h.MinX, h.MinY, h.MaxX, h.MaxY) {
+		return points, nil
+	}
+	it, err := lf.Points()
+	if err != nil {
+		return nil, err
+	}
+	r2 := radius * radius
+	for it.Next() {
+		p := it.Point()
+		pd := p.PointData()
+		if dx, dy := pd.X-cx, pd.Y-cy; dx*dx+dy*dy <= r2 {
+			points = append(points, p)
+		}
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return points, nil
+}
+
+// circleIntersectsRect returns true if the circle of the given center and
+// radius intersects the rectangle [minX, maxX] x [minY, maxY].
+func circleIntersectsRect(cx, cy, radius, minX, minY, maxX, maxY float64) bool {
+	// The distance from the center to the nearest point of the rectangle
+	dx := math.Max(math.Max(minX-cx, 0), cx-maxX)
+	dy := math.Max(math.Max(minY-cy, 0), cy-maxY)
+	return dx*dx+dy*dy <= radius*radius
+}
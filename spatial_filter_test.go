@@ -0,0 +1,124 @@
+package lidario
+
+import (
+	"math"
+	"testing"
+)
+
+func TestReadPointsInBoundsOutsideExtent(t *testing.T) {
+	// No reader is needed, since the box does not intersect the extent.
+	lf := &LazFile{fileMode: "r", Header: LasHeader{MinX: 0, MaxX: 10, MinY: 0, MaxY: 10}}
+	points, err := lf.ReadPointsInBounds(20, 20, 30, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 0 {
+		t.Errorf("expected no points, got %v", len(points))
+	}
+	if _, err = lf.ReadPointsInBounds(5, 5, 1, 1); err == nil {
+		t.Error("expected an error for an inverted box")
+	}
+}
+
+func TestReadPointsInBounds(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	h := lf.Header
+
+	all, err := lf.ReadPointsInBounds(h.MinX, h.MinY, h.MaxX, h.MaxY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != h.NumberPoints {
+		t.Errorf("a box covering the extent returned %v of %v points", len(all), h.NumberPoints)
+	}
+
+	none, err := lf.ReadPointsInBounds(h.MaxX+1, h.MaxY+1, h.MaxX+2, h.MaxY+2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none) != 0 {
+		t.Errorf("a box outside of the extent returned %v points", len(none))
+	}
+
+	// The south-western quarter of the extent.
+	midX, midY := (h.MinX+h.MaxX)/2, (h.MinY+h.MaxY)/2
+	part, err := lf.ReadPointsInBounds(h.MinX, h.MinY, midX, midY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(part) == 0 || len(part) >= len(all) {
+		t.Errorf("a partial box returned %v of %v points", len(part), len(all))
+	}
+	for _, p := range part {
+		if pd := p.PointData(); pd.X > midX || pd.Y > midY {
+			t.Fatalf("point (%v, %v) lies outside of the box", pd.X, pd.Y)
+		}
+	}
+}
+
+func TestCircleIntersectsRect(t *testing.T) {
+	cases := []struct {
+		cx, cy, radius float64
+		expected       bool
+	}{
+		{5, 5, 0, true},  // the center lies inside
+		{-1, 5, 1, true}, // touching the left edge
+		{-1, 5, 0.9, false},
+		{13, 14, 5, true}, // reaching the corner (10, 10)
+		{13, 14, 4.9, false},
+		{20, 20, 100, true}, // the circle contains the rectangle
+	}
+	for _, c := range cases {
+		if got := circleIntersectsRect(c.cx, c.cy, c.radius, 0, 0, 10, 10); got != c.expected {
+			t.Errorf("circle (%v, %v) r=%v: got %v", c.cx, c.cy, c.radius, got)
+		}
+	}
+}
+
+func TestReadPointsNearOutsideExtent(t *testing.T) {
+	// No reader is needed, since the circle does not intersect the extent.
+	lf := &LazFile{fileMode: "r", Header: LasHeader{MinX: 0, MaxX: 10, MinY: 0, MaxY: 10}}
+	points, err := lf.ReadPointsNear(13, 14, 4.9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 0 {
+		t.Errorf("expected no points, got %v", len(points))
+	}
+	if _, err = lf.ReadPointsNear(5, 5, -1); err == nil {
+		t.Error("expected an error for a negative radius")
+	}
+}
+
+func TestReadPointsNear(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	h := lf.Header
+
+	// A circle around the center reaching the corners covers the extent.
+	cx, cy := (h.MinX+h.MaxX)/2, (h.MinY+h.MaxY)/2
+	all, err := lf.ReadPointsNear(cx, cy, math.Hypot(h.MaxX-cx, h.MaxY-cy)+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != h.NumberPoints {
+		t.Errorf("a circle covering the extent returned %v of %v points", len(all), h.NumberPoints)
+	}
+
+	none, err := lf.ReadPointsNear(h.MaxX+10, h.MaxY+10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none) != 0 {
+		t.Errorf("a circle outside of the extent returned %v points", len(none))
+	}
+}
@@ -0,0 +1,84 @@
+package lidario
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// OtherClasses is the key under which SplitByClass reports the output holding
+// the points of every class that was not requested. Point formats 0-3 store
+// 5-bit classes, so the value cannot clash with a real class.
+const OtherClasses uint8 = 255
+
+// SplitByClass writes the points of each requested class to a separate LAS
+// file in outDir, named after the input file and the class (e.g.
+// tile_class2.las), and returns the output file name of each class. The
+// headers of the outputs are recomputed from the points they contain. Classes
+// without any points produce no output and are absent from the result. If
+// WithOtherClasses() is supplied, the remaining points are written to
+// tile_other.las, keyed by OtherClasses.
+func (las *LasFile) SplitByClass(outDir string, classes []uint8, opts ...WriterOption) (map[uint8]string, error) {
+	if las.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	o := newWriterOptions(opts)
+	if err := o.check(); err != nil {
+		return nil, err
+	}
+	requested := make(map[uint8]bool, len(classes))
+	for _, c := range classes {
+		if c > 31 {
+			return nil, fmt.Errorf("class %v cannot be stored in point format %v", c, las.Header.PointFormatID)
+		}
+		requested[c] = true
+	}
+
+	// Group the points first so that no output is created for an empty class.
+	groups := make(map[uint8][]int)
+	for i := range las.pointData {
+		c := las.pointData[i].ClassBitField.Classification()
+		if !requested[c] {
+			if !o.splitOther {
+				continue
+			}
+			c = OtherClasses
+		}
+		groups[c] = append(groups[c], i)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(las.fileName), filepath.Ext(las.fileName))
+	outputs := make(map[uint8]string, len(groups))
+	for c := range groups {
+		name := fmt.Sprintf("%v_class%v.las", base, c)
+		if c == OtherClasses {
+			name = fmt.Sprintf("%v_other.las", base)
+		}
+		outputs[c] = filepath.Join(outDir, name)
+		if err := o.checkOutput(outputs[c]); err != nil {
+			return nil, fmt.Errorf("%v: %w", outputs[c], err)
+		}
+	}
+
+	for c, indices := range groups {
+		out, err := InitializeUsingFile(outputs[c], las, opts...)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range indices {
+			p, err := las.LasPoint(i)
+			if err != nil {
+				out.Close()
+				return nil, err
+			}
+			if err = out.AddLasPoint(p); err != nil {
+				out.Close()
+				return nil, err
+			}
+		}
+		if err = out.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
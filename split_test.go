@@ -0,0 +1,60 @@
+package lidario
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSplitByClass(t *testing.T) {
+	classes := []uint8{2, 6, 2, 1, 6, 2, 5}
+	var points []LasPointer
+	for i, c := range classes {
+		p := &PointRecord0{X: float64(i), Y: float64(i), Z: 1.0}
+		p.ClassBitField.SetClassification(c)
+		points = append(points, p)
+	}
+	lf := createTestLasFile(t, 0, points)
+	outDir := t.TempDir()
+
+	outputs, err := lf.SplitByClass(outDir, []uint8{2, 6, 9}, WithOtherClasses())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 3 {
+		t.Fatalf("expected outputs for classes 2, 6 and the other classes, got %v", outputs)
+	}
+	if _, ok := outputs[9]; ok {
+		t.Error("an output was created for a class without points")
+	}
+
+	expected := map[uint8]int{2: 3, 6: 2, OtherClasses: 2}
+	for c, fileName := range outputs {
+		out, err := NewLasFile(fileName, "r")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Header.NumberPoints != expected[c] {
+			t.Errorf("class %v: expected %v points, got %v", c, expected[c], out.Header.NumberPoints)
+		}
+		if out.Header.NumberPointsByReturn[0] != expected[c] {
+			t.Errorf("class %v: expected %v first returns, got %v", c, expected[c], out.Header.NumberPointsByReturn[0])
+		}
+		for i := 0; i < out.Header.NumberPoints; i++ {
+			p, _ := out.LasPoint(i)
+			got := p.PointData().ClassBitField.Classification()
+			if c == OtherClasses && (got == 2 || got == 6) {
+				t.Errorf("the other output contains a point of class %v", got)
+			} else if c != OtherClasses && got != c {
+				t.Errorf("the class %v output contains a point of class %v", c, got)
+			}
+			if p.PointData().X < out.Header.MinX || p.PointData().X > out.Header.MaxX {
+				t.Errorf("class %v: the header bounds do not enclose the points", c)
+			}
+		}
+		out.Close()
+	}
+
+	if _, err = lf.SplitByClass(outDir, []uint8{2}); !errors.Is(err, ErrOutputExists) {
+		t.Errorf("expected ErrOutputExists, got %v", err)
+	}
+}
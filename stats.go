@@ -0,0 +1,93 @@
+package lidario
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// AxisStats summarizes the values of a point attribute.
+type AxisStats struct {
+	Min    float64
+	Max    float64
+	Mean   float64
+	StdDev float64 // population standard deviation
+}
+
+// PointStats holds statistics computed from the points of a file.
+type PointStats struct {
+	Count          uint64
+	X              AxisStats
+	Y              AxisStats
+	Z              AxisStats
+	Intensity      AxisStats
+	Classification map[uint8]uint64 // the number of points of each class
+}
+
+// statsAccumulator accumulates the statistics of a single attribute. The mean
+// and variance are updated with Welford's algorithm, which is numerically
+// stable for large clouds with large coordinate values.
+type statsAccumulator struct {
+	n        float64
+	min, max float64
+	mean, m2 float64
+}
+
+func (a *statsAccumulator) add(v float64) {
+	if a.n == 0 {
+		a.min, a.max = v, v
+	}
+	a.min = math.Min(a.min, v)
+	a.max = math.Max(a.max, v)
+	a.n++
+	delta := v - a.mean
+	a.mean += delta / a.n
+	a.m2 += delta * (v - a.mean)
+}
+
+func (a *statsAccumulator) result() AxisStats {
+	if a.n == 0 {
+		return AxisStats{}
+	}
+	return AxisStats{Min: a.min, Max: a.max, Mean: a.mean, StdDev: math.Sqrt(a.m2 / a.n)}
+}
+
+// ComputeStatistics reads every point once, sequentially, and returns the
+// range, mean and standard deviation of the coordinates and intensities along
+// with a histogram of the classes. Unlike the header extent, the statistics
+// reflect the points actually stored in the file.
+func (lf *LazFile) ComputeStatistics() (*PointStats, error) {
+	return lf.ComputeStatisticsCtx(context.Background())
+}
+
+// ComputeStatisticsCtx is like ComputeStatistics, but returns the error of ctx
+// if it is cancelled or its deadline passes during the scan.
+func (lf *LazFile) ComputeStatisticsCtx(ctx context.Context) (*PointStats, error) {
+	it, err := lf.PointsCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var x, y, z, intensity statsAccumulator
+	stats := &PointStats{Classification: make(map[uint8]uint64)}
+	for it.Next() {
+		// The LASzip point holds the classes above 31 of point formats 6-10.
+		p := it.laszipPoint()
+		x.add(p.X)
+		y.add(p.Y)
+		z.add(p.Z)
+		intensity.add(float64(p.Intensity))
+		stats.Classification[lf.mapClass(p.Classification)]++
+		stats.Count++
+	}
+	if err = it.Err(); err != nil {
+		return nil, err
+	}
+	if stats.Count == 0 {
+		return nil, errors.New("the file does not contain any points")
+	}
+	stats.X, stats.Y, stats.Z, stats.Intensity = x.result(), y.result(), z.result(), intensity.result()
+	lf.Lock()
+	lf.stats = stats
+	lf.Unlock()
+	return stats, nil
+}
@@ -0,0 +1,46 @@
+package lidario
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStatsAccumulator(t *testing.T) {
+	var a statsAccumulator
+	for _, v := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		a.add(v + 4e6)
+	}
+	s := a.result()
+	if s.Min != 4e6+2 || s.Max != 4e6+9 || math.Abs(s.Mean-(4e6+5)) > 1e-9 || math.Abs(s.StdDev-2) > 1e-9 {
+		t.Errorf("unexpected statistics %+v", s)
+	}
+}
+
+func TestComputeStatistics(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	stats, err := lf.ComputeStatistics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != uint64(lf.GetPointCount()) {
+		t.Errorf("expected %v points, got %v", lf.GetPointCount(), stats.Count)
+	}
+	tolerance := lf.Header.XScaleFactor
+	if math.Abs(stats.X.Min-lf.Header.MinX) > tolerance || math.Abs(stats.X.Max-lf.Header.MaxX) > tolerance {
+		t.Errorf("computed X range [%v, %v] does not match the header extent [%v, %v]",
+			stats.X.Min, stats.X.Max, lf.Header.MinX, lf.Header.MaxX)
+	}
+	var classified uint64
+	for _, n := range stats.Classification {
+		classified += n
+	}
+	if classified != stats.Count {
+		t.Errorf("the class histogram counts %v points, expected %v", classified, stats.Count)
+	}
+}
@@ -0,0 +1,76 @@
+package lidario
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ReadTilesConcurrent opens each of the files in paths with NewLidarFile and
+// calls fn with it, processing up to workers files at a time (runtime.NumCPU()
+// if workers is not positive). Every file gets its own reader, since the
+// LASzip state of a LazFile cannot be shared; for the same reason a single
+// LidarFile should not be used from several goroutines, and fn must not retain
+// the file, which is closed once fn returns. No further files are opened once
+// an error occurs, and the first error is returned.
+func ReadTilesConcurrent(paths []string, workers int, fn func(LidarFile) error) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		failed   = make(chan struct{})
+		jobs     = make(chan string)
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			close(failed)
+		})
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				select {
+				case <-failed:
+					continue
+				default:
+				}
+				if err := readTile(path, fn); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+dispatch:
+	for _, path := range paths {
+		select {
+		case jobs <- path:
+		case <-failed:
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return firstErr
+}
+
+// readTile opens a file, passes it to fn and closes it.
+func readTile(path string, fn func(LidarFile) error) error {
+	lf, err := NewLidarFile(path, "r")
+	if err != nil {
+		return fmt.Errorf("%v: %w", path, err)
+	}
+	err = fn(lf)
+	if closeErr := lf.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("%v: %w", path, err)
+	}
+	return nil
+}
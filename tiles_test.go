@@ -0,0 +1,92 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// copyTiles writes n copies of a file to a temporary directory.
+func copyTiles(t *testing.T, src string, n int) []string {
+	t.Helper()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("tile%v%v", i, filepath.Ext(src)))
+		if err = os.WriteFile(paths[i], data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func testReadTilesConcurrent(t *testing.T, src string) {
+	paths := copyTiles(t, src, 8)
+	var total, active, maxActive int64
+	err := ReadTilesConcurrent(paths, 3, func(lf LidarFile) error {
+		n := atomic.AddInt64(&active, 1)
+		defer atomic.AddInt64(&active, -1)
+		for {
+			m := atomic.LoadInt64(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt64(&maxActive, m, n) {
+				break
+			}
+		}
+		if _, _, _, err := lf.GetXYZ(0); err != nil {
+			return err
+		}
+		atomic.AddInt64(&total, int64(lf.GetPointCount()))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf, err := NewLidarFile(src, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	if expected := int64(len(paths)) * int64(lf.GetPointCount()); total != expected {
+		t.Errorf("expected %v points in total, got %v", expected, total)
+	}
+	if maxActive > 3 {
+		t.Errorf("expected at most 3 files to be read at once, got %v", maxActive)
+	}
+}
+
+func TestReadTilesConcurrent(t *testing.T) {
+	testReadTilesConcurrent(t, "testdata/sample.las")
+}
+
+func TestReadTilesConcurrentLaz(t *testing.T) {
+	requireSampleLaz(t)
+	testReadTilesConcurrent(t, sampleLazFile)
+}
+
+func TestReadTilesConcurrentError(t *testing.T) {
+	paths := copyTiles(t, "testdata/sample.las", 4)
+	errStop := errors.New("stop")
+	var calls int64
+	err := ReadTilesConcurrent(paths, 1, func(lf LidarFile) error {
+		atomic.AddInt64(&calls, 1)
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no files to be read after the first error, got %v calls", calls)
+	}
+
+	missing := append(paths, filepath.Join(t.TempDir(), "missing.las"))
+	if err = ReadTilesConcurrent(missing, 2, func(LidarFile) error { return nil }); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
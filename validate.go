@@ -0,0 +1,94 @@
+package lidario
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// wktRecordID is the record ID of the OGC coordinate system WKT record.
+const wktRecordID = 2112
+
+// isWKTRecord returns true if the record holds an OGC coordinate system WKT.
+func isWKTRecord(userID string, recordID int) bool {
+	return userID == "LASF_Projection" && recordID == wktRecordID
+}
+
+// readEVLRHeaders reads the headers of the extended variable length records
+// that start at the given offset. The record payloads are not read; the
+// returned records only carry their user ID, record ID and description.
+func readEVLRHeaders(r io.ReaderAt, start uint64, count int) ([]VLR, error) {
+	var evlrs []VLR
+	b := make([]byte, evlrHeaderSize)
+	offset := int64(start)
+	for i := 0; i < count; i++ {
+		if _, err := r.ReadAt(b, offset); err != nil {
+			return evlrs, fmt.Errorf("reading EVLR %v: %v", i, err)
+		}
+		evlr := parseEVLRHeader(b)
+		evlrs = append(evlrs, evlr.vlr())
+		offset += evlrHeaderSize + int64(evlr.RecordLengthAfterHeader)
+	}
+	return evlrs, nil
+}
+
+// wktIssues cross-checks the WKT bit of the global encoding against the
+// presence of a WKT record among the VLRs and EVLRs.
+func wktIssues(h LasHeader, records []VLR) []ValidationIssue {
+	issues := []ValidationIssue{}
+	hasWKT := false
+	for _, vlr := range records {
+		if isWKTRecord(vlr.UserID, vlr.RecordID) {
+			hasWKT = true
+			break
+		}
+	}
+	wktBit := h.GlobalEncoding.CoordinateReferenceSystemMethod() == WellKnownText
+	if wktBit && !hasWKT {
+		issues = append(issues, ValidationIssue{Check: "wkt",
+			Message: "the global encoding WKT bit is set but the file has no WKT coordinate system record"})
+	}
+	if !wktBit && hasWKT && h.versionAtLeast(1, 4) {
+		issues = append(issues, ValidationIssue{Check: "wkt",
+			Message: "the file has a WKT coordinate system record but the global encoding WKT bit is not set"})
+	}
+	return issues
+}
+
+// ValidateWKT checks that the WKT bit of the global encoding agrees with the
+// presence of a WKT coordinate system VLR or EVLR. An inconsistency signals a
+// malformed file. An empty slice is returned for a consistent file.
+func (las *LasFile) ValidateWKT() []ValidationIssue {
+	records := append([]VLR{}, las.VlrData...)
+	if las.Header.NumberOfEVLRs > 0 && las.Header.StartOfFirstEVLR > 0 {
+		f, err := os.Open(las.fileName)
+		if err != nil {
+			return []ValidationIssue{{Check: "evlr", Message: err.Error()}}
+		}
+		defer f.Close()
+		evlrs, err := readEVLRHeaders(f, las.Header.StartOfFirstEVLR, las.Header.NumberOfEVLRs)
+		if err != nil {
+			return []ValidationIssue{{Check: "evlr", Message: err.Error()}}
+		}
+		records = append(records, evlrs...)
+	}
+	return wktIssues(las.Header, records)
+}
+
+// FindInvalidReturns returns the indices of the points whose return number is
+// zero or exceeds their number of returns. The raw bit field values are
+// inspected, since the PointBitField accessors substitute 1 for a zero value.
+func (las *LasFile) FindInvalidReturns() ([]int, error) {
+	if las.fileMode == "rh" {
+		return nil, errHeaderOnly
+	}
+	invalid := []int{}
+	for i := range las.pointData {
+		v := las.pointData[i].BitField.Value
+		returnNumber, numberOfReturns := v&7, (v>>3)&7
+		if returnNumber == 0 || returnNumber > numberOfReturns {
+			invalid = append(invalid, i)
+		}
+	}
+	return invalid, nil
+}
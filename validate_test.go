@@ -0,0 +1,65 @@
+package lidario
+
+import (
+	"os"
+	"testing"
+)
+
+func TestValidateWKTBitWithoutRecord(t *testing.T) {
+	lf := createTestLasFile(t, 0, []LasPointer{&PointRecord0{X: 1, Y: 1, Z: 1}})
+	if issues := lf.ValidateWKT(); len(issues) != 0 {
+		t.Fatalf("expected no issues before setting the WKT bit, got %v", issues)
+	}
+	lf.Close()
+
+	// Set the WKT bit (bit 4) of the global encoding.
+	f, err := os.OpenFile(lf.fileName, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.WriteAt([]byte{16, 0}, 6); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	lf, err = NewLasFile(lf.fileName, "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+	issues := lf.ValidateWKT()
+	if len(issues) != 1 || issues[0].Check != "wkt" {
+		t.Errorf("expected a single WKT issue, got %v", issues)
+	}
+}
+
+func TestWKTIssuesWithRecord(t *testing.T) {
+	h := LasHeader{VersionMajor: 1, VersionMinor: 4, GlobalEncoding: GlobalEncodingField{Value: 16}}
+	records := []VLR{{UserID: "LASF_Projection", RecordID: 2112}}
+	if issues := wktIssues(h, records); len(issues) != 0 {
+		t.Errorf("expected no issues when the WKT record is present, got %v", issues)
+	}
+	h.GlobalEncoding.Value = 0
+	if issues := wktIssues(h, records); len(issues) != 1 {
+		t.Errorf("expected an issue when the WKT bit is missing, got %v", issues)
+	}
+}
+
+func TestFindInvalidReturns(t *testing.T) {
+	returns := []struct{ returnNumber, numberOfReturns byte }{
+		{1, 1}, {1, 2}, {2, 2}, {3, 2}, {0, 1}, {2, 3},
+	}
+	var points []LasPointer
+	for i, r := range returns {
+		points = append(points, &PointRecord0{X: float64(i), Y: 1, Z: 1,
+			BitField: PointBitField{Value: r.returnNumber | r.numberOfReturns<<3}})
+	}
+	lf := createTestLasFile(t, 0, points)
+	invalid, err := lf.FindInvalidReturns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invalid) != 2 || invalid[0] != 3 || invalid[1] != 4 {
+		t.Errorf("expected points 3 and 4 to be invalid, got %v", invalid)
+	}
+}
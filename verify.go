@@ -0,0 +1,23 @@
+package lidario
+
+import "fmt"
+
+// VerifyAllPoints decompresses every point of the file, in order, and returns
+// the number of points that decompressed successfully. If a point fails to
+// decompress, the error identifies it; its index is the returned count. Unlike
+// Validate, the points are not compared with the header, which makes this a
+// cheap integrity check before ingesting a file.
+func (lf *LazFile) VerifyAllPoints() (uint64, error) {
+	it, err := lf.Points()
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for it.Next() {
+		n++
+	}
+	if err = it.Err(); err != nil {
+		return n, fmt.Errorf("point %v failed to decompress: %w", n, err)
+	}
+	return n, nil
+}
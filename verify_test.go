@@ -0,0 +1,33 @@
+package lidario
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyAllPoints(t *testing.T) {
+	requireSampleLaz(t)
+	lf, err := NewLazFile(sampleLazFile, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lf.Close()
+
+	n, err := lf.VerifyAllPoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != uint64(lf.GetPointCount()) {
+		t.Errorf("%v points decompressed, expected %v", n, lf.GetPointCount())
+	}
+}
+
+func TestVerifyAllPointsHeaderOnly(t *testing.T) {
+	lf, err := NewLazFile(writeHeaderOnlyLaz(t), "rh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = lf.VerifyAllPoints(); !errors.Is(err, errHeaderOnly) {
+		t.Errorf("expected errHeaderOnly, got %v", err)
+	}
+}
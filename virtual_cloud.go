@@ -0,0 +1,197 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// VirtualCloud presents several LAS or LAZ files, such as the tiles of a
+// survey delivery, as a single cloud. The points of the files are numbered
+// consecutively in the order of the files, and the combined header holds the
+// union of their extents and the sum of their point counts.
+//
+// The coordinates of the combined header use the finest scale factor of the
+// files and the offsets of the first file. The coordinates of the files with
+// a different scale factor or offset are rescaled to that grid on read, so
+// that every point of the cloud can be stored with the combined header.
+type VirtualCloud struct {
+	Header LasHeader
+	files  []LidarFile
+	// starts holds the index of the first point of each file
+	starts []int
+	// rescale marks the files whose coordinates are rescaled on read
+	rescale []bool
+}
+
+// Ensure VirtualCloud implements LidarFile interface
+var _ LidarFile = (*VirtualCloud)(nil)
+
+// NewVirtualCloud opens the files in paths with NewLidarFile and presents them
+// as a single cloud. The files must share a point format.
+func NewVirtualCloud(paths []string) (*VirtualCloud, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("a virtual cloud needs at least one file")
+	}
+	vc := &VirtualCloud{}
+	for _, path := range paths {
+		lf, err := NewLidarFile(path, "r")
+		if err != nil {
+			vc.Close()
+			return nil, fmt.Errorf("opening %v: %w", path, err)
+		}
+		vc.files = append(vc.files, lf)
+	}
+
+	first := vc.files[0].GetHeader()
+	vc.Header = *first
+	h := &vc.Header
+	h.NumberPoints, h.ExtendedNumberPoints, h.NumberPointsByReturn = 0, 0, [15]int{}
+	for i, lf := range vc.files {
+		fh := lf.GetHeader()
+		if fh.PointFormatID != first.PointFormatID {
+			vc.Close()
+			return nil, fmt.Errorf("%v has point format %v but %v has point format %v",
+				paths[i], fh.PointFormatID, paths[0], first.PointFormatID)
+		}
+		vc.starts = append(vc.starts, h.NumberPoints)
+		h.NumberPoints += int(fh.pointCount())
+		if h.versionAtLeast(1, 4) {
+			h.ExtendedNumberPoints += fh.pointCount()
+		}
+		for r, n := range fh.NumberPointsByReturn {
+			h.NumberPointsByReturn[r] += n
+		}
+		h.MinX, h.MaxX = math.Min(h.MinX, fh.MinX), math.Max(h.MaxX, fh.MaxX)
+		h.MinY, h.MaxY = math.Min(h.MinY, fh.MinY), math.Max(h.MaxY, fh.MaxY)
+		h.MinZ, h.MaxZ = math.Min(h.MinZ, fh.MinZ), math.Max(h.MaxZ, fh.MaxZ)
+		h.XScaleFactor = math.Min(h.XScaleFactor, fh.XScaleFactor)
+		h.YScaleFactor = math.Min(h.YScaleFactor, fh.YScaleFactor)
+		h.ZScaleFactor = math.Min(h.ZScaleFactor, fh.ZScaleFactor)
+	}
+	for _, lf := range vc.files {
+		fh := lf.GetHeader()
+		vc.rescale = append(vc.rescale, fh.XScaleFactor != h.XScaleFactor || fh.YScaleFactor != h.YScaleFactor ||
+			fh.ZScaleFactor != h.ZScaleFactor || fh.XOffset != h.XOffset || fh.YOffset != h.YOffset || fh.ZOffset != h.ZOffset)
+	}
+	return vc, nil
+}
+
+// locate returns the file holding a point of the cloud and the index of the
+// point within the file.
+func (vc *VirtualCloud) locate(pointIndex int) (int, int, error) {
+	if pointIndex < 0 || pointIndex >= vc.Header.NumberPoints {
+		return 0, 0, fmt.Errorf("%w: %v", ErrPointOutOfRange, pointIndex)
+	}
+	// The last file starting at or before the point; empty files share
+	// their start with the next file.
+	f := sort.Search(len(vc.starts), func(i int) bool { return vc.starts[i] > pointIndex }) - 1
+	return f, pointIndex - vc.starts[f], nil
+}
+
+// snapToGrid rounds a coordinate to the grid of the given scale and offset.
+func snapToGrid(v, scale, offset float64) float64 {
+	return math.Round((v-offset)/scale)*scale + offset
+}
+
+// rescaleXYZ rounds coordinates to the grid of the combined header.
+func (vc *VirtualCloud) rescaleXYZ(x, y, z float64) (float64, float64, float64) {
+	h := &vc.Header
+	return snapToGrid(x, h.XScaleFactor, h.XOffset), snapToGrid(y, h.YScaleFactor, h.YOffset), snapToGrid(z, h.ZScaleFactor, h.ZOffset)
+}
+
+// LasPoint reads a point of the cloud from the file holding it.
+func (vc *VirtualCloud) LasPoint(pointIndex int) (LasPointer, error) {
+	f, local, err := vc.locate(pointIndex)
+	if err != nil {
+		return nil, err
+	}
+	p, err := vc.files[f].LasPoint(local)
+	if err != nil || !vc.rescale[f] {
+		return p, err
+	}
+	// The point may be owned by the file, so the rescaled point is a copy.
+	pd := *p.PointData()
+	pd.X, pd.Y, pd.Z = vc.rescaleXYZ(pd.X, pd.Y, pd.Z)
+	return withPointData(p, &pd), nil
+}
+
+// withPointData returns a copy of p whose core fields are those of pd.
+func withPointData(p LasPointer, pd *PointRecord0) LasPointer {
+	switch p := p.(type) {
+	case *PointRecord1:
+		return &PointRecord1{PointRecord0: pd, GPSTime: p.GPSTime}
+	case *PointRecord2:
+		return &PointRecord2{PointRecord0: pd, RGB: p.RGB}
+	case *PointRecord3:
+		return &PointRecord3{PointRecord0: pd, GPSTime: p.GPSTime, RGB: p.RGB}
+	case *PointRecord4:
+		return &PointRecord4{PointRecord1: &PointRecord1{PointRecord0: pd, GPSTime: p.GPSTime}}
+	case *PointRecord5:
+		return &PointRecord5{PointRecord3: &PointRecord3{PointRecord0: pd, GPSTime: p.GPSTime, RGB: p.RGB}}
+	case *PointRecord6:
+		return &PointRecord6{PointRecord0: pd, GPSTime: p.GPSTime, Extended: p.Extended}
+	case *PointRecord7:
+		return &PointRecord7{PointRecord0: pd, GPSTime: p.GPSTime, RGB: p.RGB, Extended: p.Extended}
+	case *PointRecord9:
+		return &PointRecord9{PointRecord6: &PointRecord6{PointRecord0: pd, GPSTime: p.GPSTime, Extended: p.Extended}}
+	case *PointRecord8:
+		return &PointRecord8{PointRecord0: pd, GPSTime: p.GPSTime, RGB: p.RGB, NIR: p.NIR, Extended: p.Extended}
+	case *PointRecord10:
+		return &PointRecord10{PointRecord8: &PointRecord8{PointRecord0: pd, GPSTime: p.GPSTime, RGB: p.RGB, NIR: p.NIR, Extended: p.Extended}}
+	}
+	return pd
+}
+
+// GetXYZ gets the coordinates of a point of the cloud.
+func (vc *VirtualCloud) GetXYZ(pointIndex int) (float64, float64, float64, error) {
+	f, local, err := vc.locate(pointIndex)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	x, y, z, err := vc.files[f].GetXYZ(local)
+	if err != nil || !vc.rescale[f] {
+		return x, y, z, err
+	}
+	x, y, z = vc.rescaleXYZ(x, y, z)
+	return x, y, z, nil
+}
+
+// Close closes every file of the cloud and returns the first error.
+func (vc *VirtualCloud) Close() error {
+	var firstErr error
+	for _, lf := range vc.files {
+		if err := lf.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// GetHeader returns the combined header of the cloud (implement interface)
+func (vc *VirtualCloud) GetHeader() *LasHeader {
+	return &vc.Header
+}
+
+// GetPointCount returns the number of points of the cloud (implement
+// interface). The count is truncated for clouds of more than 2^32-1 points;
+// see GetPointCount64.
+func (vc *VirtualCloud) GetPointCount() uint32 {
+	return uint32(vc.Header.NumberPoints)
+}
+
+// GetPointCount64 returns the number of points of the cloud (implement interface)
+func (vc *VirtualCloud) GetPointCount64() uint64 {
+	return uint64(vc.Header.NumberPoints)
+}
+
+// IsCompressed returns true if any file of the cloud is compressed
+func (vc *VirtualCloud) IsCompressed() bool {
+	for _, lf := range vc.files {
+		if lf.IsCompressed() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package lidario
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestVirtualCloud(t *testing.T) {
+	las, err := NewLasFile("testdata/sample.las", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+	n := las.Header.NumberPoints
+
+	vc, err := NewVirtualCloud([]string{"testdata/sample.las", "testdata/sample.las"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vc.Close()
+	if vc.GetPointCount64() != 2*uint64(n) || vc.Header.MinX != las.Header.MinX || vc.Header.MaxZ != las.Header.MaxZ {
+		t.Errorf("unexpected combined header: %v points, X [%v, %v]", vc.GetPointCount64(), vc.Header.MinX, vc.Header.MaxX)
+	}
+
+	// Reading across the boundary between the files
+	for _, i := range []int{n - 2, n - 1, n, n + 1, 2*n - 1} {
+		expected, err := las.LasPoint(i % n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := vc.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *p.PointData() != *expected.PointData() || p.GpsTimeData() != expected.GpsTimeData() {
+			t.Errorf("point %v: got %+v, expected %+v", i, p.PointData(), expected.PointData())
+		}
+		x, y, z, err := vc.GetXYZ(i)
+		if err != nil || x != expected.PointData().X || y != expected.PointData().Y || z != expected.PointData().Z {
+			t.Errorf("point %v: GetXYZ returned (%v, %v, %v) and %v", i, x, y, z, err)
+		}
+	}
+	for _, i := range []int{-1, 2 * n} {
+		if _, err = vc.LasPoint(i); err == nil {
+			t.Errorf("expected an error for point %v", i)
+		}
+	}
+}
+
+func TestVirtualCloudRescale(t *testing.T) {
+	las, err := NewLasFile("testdata/sample.las", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer las.Close()
+
+	// A tile with a coarser scale factor and a shifted offset
+	h := las.Header
+	h.XScaleFactor, h.YScaleFactor, h.ZScaleFactor = 0.1, 0.1, 0.1
+	h.XOffset, h.YOffset, h.ZOffset = 0.005, 0.005, 0.005
+	coarse := filepath.Join(t.TempDir(), "coarse.las")
+	lw, err := NewLasWriter(coarse, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		p, _ := las.LasPoint(i)
+		if err = lw.WritePoint(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	vc, err := NewVirtualCloud([]string{"testdata/sample.las", coarse})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vc.Close()
+	if vc.Header.XScaleFactor != 0.01 || vc.GetPointCount() != las.GetPointCount()+100 {
+		t.Fatalf("unexpected combined header: scale %v, %v points", vc.Header.XScaleFactor, vc.GetPointCount())
+	}
+	for i := las.Header.NumberPoints; i < int(vc.GetPointCount()); i++ {
+		x, y, z, err := vc.GetXYZ(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range []float64{x, y, z} {
+			if steps := v / 0.01; math.Abs(steps-math.Round(steps)) > 1e-6 {
+				t.Fatalf("point %v: %v is not on the grid of the combined header", i, v)
+			}
+		}
+		p, err := vc.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pd := p.PointData(); pd.X != x || pd.Y != y || pd.Z != z {
+			t.Errorf("point %v: LasPoint returned (%v, %v, %v), GetXYZ (%v, %v, %v)", i, pd.X, pd.Y, pd.Z, x, y, z)
+		}
+	}
+}
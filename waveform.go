@@ -0,0 +1,204 @@
+package lidario
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// ErrMissingWaveformDescriptors is returned when a point format stores wave
+// packets but the file carries no waveform packet descriptor VLRs.
+var ErrMissingWaveformDescriptors = errors.New("the point format contains wave packets but the file has no waveform packet descriptors")
+
+// ErrNoWaveform is returned when a point does not reference a waveform packet
+// (its wave packet descriptor index is zero).
+var ErrNoWaveform = errors.New("the point has no waveform packet")
+
+// WaveformDescriptor is a waveform packet descriptor (LASF_Spec, record IDs 100-354).
+type WaveformDescriptor struct {
+	Index           int
+	BitsPerSample   uint8
+	CompressionType uint8
+	NumberOfSamples uint32
+	TemporalSpacing uint32 // in picoseconds
+	DigitizerGain   float64
+	DigitizerOffset float64
+}
+
+// WaveformPacket holds the wave packet information stored with a point. The
+// Descriptor and Data fields are only filled in by GetWaveform.
+type WaveformPacket struct {
+	DescriptorIndex     uint8
+	ByteOffset          uint64
+	PacketSize          uint32
+	ReturnPointLocation float32 // in picoseconds
+	Xt                  float32
+	Yt                  float32
+	Zt                  float32
+	Descriptor          WaveformDescriptor
+	Data                []byte
+}
+
+// parseWavePacket parses the 29 wave packet bytes of a point record.
+func parseWavePacket(b []byte) WaveformPacket {
+	f32 := func(o int) float32 { return math.Float32frombits(binary.LittleEndian.Uint32(b[o : o+4])) }
+	return WaveformPacket{
+		DescriptorIndex:     b[0],
+		ByteOffset:          binary.LittleEndian.Uint64(b[1:9]),
+		PacketSize:          binary.LittleEndian.Uint32(b[9:13]),
+		ReturnPointLocation: f32(13),
+		Xt:                  f32(17),
+		Yt:                  f32(21),
+		Zt:                  f32(25),
+	}
+}
+
+// Samples returns the digitized waveform as voltages (gain * sample + offset).
+// Only uncompressed packets are supported.
+func (wp *WaveformPacket) Samples() ([]float64, error) {
+	d := wp.Descriptor
+	if d.CompressionType != 0 {
+		return nil, fmt.Errorf("unsupported waveform compression type %v", d.CompressionType)
+	}
+	if d.BitsPerSample == 0 || d.BitsPerSample > 32 {
+		return nil, fmt.Errorf("unsupported number of bits per sample (%v)", d.BitsPerSample)
+	}
+	size := int(d.BitsPerSample+7) / 8
+	if len(wp.Data) < int(d.NumberOfSamples)*size {
+		return nil, fmt.Errorf("the waveform packet holds %v bytes, too few for %v samples", len(wp.Data), d.NumberOfSamples)
+	}
+	samples := make([]float64, d.NumberOfSamples)
+	b := make([]byte, 4)
+	for i := range samples {
+		copy(b, wp.Data[i*size:(i+1)*size])
+		for j := size; j < 4; j++ {
+			b[j] = 0
+		}
+		samples[i] = d.DigitizerGain*float64(binary.LittleEndian.Uint32(b)) + d.DigitizerOffset
+	}
+	return samples, nil
+}
+
+// hasWavePackets returns true if the point format stores wave packet data.
+func hasWavePackets(format byte) bool {
+	return format == 4 || format == 5 || format == 9 || format == 10
+}
+
+// parseWaveformDescriptors returns the waveform packet descriptors found in
+// the VLRs, keyed on their index (record ID - 99).
+func parseWaveformDescriptors(vlrs []VLR) map[int]WaveformDescriptor {
+	descriptors := make(map[int]WaveformDescriptor)
+	for _, vlr := range vlrs {
+		if vlr.UserID != "LASF_Spec" || vlr.RecordID < 100 || vlr.RecordID > 354 || len(vlr.BinaryData) < 26 {
+			continue
+		}
+		b := vlr.BinaryData
+		descriptors[vlr.RecordID-99] = WaveformDescriptor{
+			Index:           vlr.RecordID - 99,
+			BitsPerSample:   b[0],
+			CompressionType: b[1],
+			NumberOfSamples: binary.LittleEndian.Uint32(b[2:6]),
+			TemporalSpacing: binary.LittleEndian.Uint32(b[6:10]),
+			DigitizerGain:   math.Float64frombits(binary.LittleEndian.Uint64(b[10:18])),
+			DigitizerOffset: math.Float64frombits(binary.LittleEndian.Uint64(b[18:26])),
+		}
+	}
+	return descriptors
+}
+
+// loadWaveformDescriptors parses the waveform packet descriptors the first time
+// waveform data are requested.
+func (lf *LazFile) loadWaveformDescriptors() error {
+	lf.Lock()
+	defer lf.Unlock()
+	if lf.waveformDescriptors != nil {
+		return nil
+	}
+	if !hasWavePackets(lf.Header.PointFormatID) {
+		return errors.New("the point format does not contain wave packets")
+	}
+	descriptors := parseWaveformDescriptors(lf.VlrData)
+	if len(descriptors) == 0 {
+		return ErrMissingWaveformDescriptors
+	}
+	lf.waveformDescriptors = descriptors
+	return nil
+}
+
+// Waveform returns the wave packet information of a point. The waveform packet
+// descriptors are validated when Waveform is first called;
+// ErrMissingWaveformDescriptors is returned if the format claims wave packets
+// but the file has no descriptors, and ErrNoWaveform if the point does not
+// reference a waveform. The waveform samples are not read; see GetWaveform.
+func (lf *LazFile) Waveform(pointIndex int) (*WaveformPacket, error) {
+	if err := lf.loadWaveformDescriptors(); err != nil {
+		return nil, err
+	}
+	lf.Lock()
+	defer lf.Unlock()
+	lp, err := lf.readPoint(pointIndex)
+	if err != nil {
+		return nil, err
+	}
+	wp := parseWavePacket(lp.WavePacket[:])
+	if wp.DescriptorIndex == 0 {
+		return nil, ErrNoWaveform
+	}
+	descriptor, ok := lf.waveformDescriptors[int(wp.DescriptorIndex)]
+	if !ok {
+		return nil, fmt.Errorf("point %v references the missing waveform packet descriptor %v", pointIndex, wp.DescriptorIndex)
+	}
+	wp.Descriptor = descriptor
+	return &wp, nil
+}
+
+// GetWaveform returns the wave packet of a point along with its waveform
+// data. The data are read from the waveform data packet record of the file or,
+// if the global encoding flags external data, from the .wdp file next to it.
+func (lf *LazFile) GetWaveform(pointIndex int) (*WaveformPacket, error) {
+	wp, err := lf.Waveform(pointIndex)
+	if err != nil {
+		return nil, err
+	}
+	var r rawReader
+	if lf.Header.GlobalEncoding.WaveformDataExternal() {
+		r, err = openRawFile(strings.TrimSuffix(lf.fileName, filepath.Ext(lf.fileName)) + ".wdp")
+	} else {
+		r, err = lf.openRaw()
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	if wp.Data, err = readWaveformData(r, &lf.Header, wp); err != nil {
+		return nil, err
+	}
+	return wp, nil
+}
+
+// readWaveformData reads the raw data of a wave packet from r, which is the
+// file or, when the data are stored externally, the .wdp file. The byte offset
+// of a packet is relative to the start of the waveform data packet record
+// header, which begins the .wdp file.
+func readWaveformData(r io.ReaderAt, h *LasHeader, wp *WaveformPacket) ([]byte, error) {
+	var start uint64
+	switch {
+	case h.GlobalEncoding.WaveformDataInternal():
+		if h.WaveformDataStart == 0 {
+			return nil, errors.New("the header does not locate the waveform data packet record")
+		}
+		start = h.WaveformDataStart
+	case h.GlobalEncoding.WaveformDataExternal():
+	default:
+		return nil, errors.New("the global encoding does not locate the waveform data")
+	}
+	data := make([]byte, wp.PacketSize)
+	if _, err := r.ReadAt(data, int64(start+wp.ByteOffset)); err != nil {
+		return nil, fmt.Errorf("reading waveform packet at offset %v: %v", wp.ByteOffset, err)
+	}
+	return data, nil
+}
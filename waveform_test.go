@@ -0,0 +1,81 @@
+package lidario
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestWaveformMissingDescriptors(t *testing.T) {
+	lf := &LazFile{Header: LasHeader{PointFormatID: 9, NumberPoints: 1}}
+	if _, err := lf.Waveform(0); err != ErrMissingWaveformDescriptors {
+		t.Errorf("expected ErrMissingWaveformDescriptors, got %v", err)
+	}
+}
+
+func TestParseWaveformDescriptors(t *testing.T) {
+	b := make([]byte, 26)
+	b[0] = 8
+	binary.LittleEndian.PutUint32(b[2:6], 256)
+	binary.LittleEndian.PutUint32(b[6:10], 1000)
+	binary.LittleEndian.PutUint64(b[10:18], math.Float64bits(0.5))
+	vlrs := []VLR{
+		{UserID: "LASF_Projection", RecordID: 34735, BinaryData: []byte{1, 2}},
+		{UserID: "LASF_Spec", RecordID: 100, RecordLengthAfterHeader: 26, BinaryData: b},
+	}
+	descriptors := parseWaveformDescriptors(vlrs)
+	d, ok := descriptors[1]
+	if len(descriptors) != 1 || !ok {
+		t.Fatalf("expected a single descriptor with index 1, got %v", descriptors)
+	}
+	if d.BitsPerSample != 8 || d.NumberOfSamples != 256 || d.TemporalSpacing != 1000 || d.DigitizerGain != 0.5 {
+		t.Errorf("unexpected descriptor %+v", d)
+	}
+}
+
+func TestParseWavePacket(t *testing.T) {
+	b := make([]byte, 29)
+	b[0] = 2
+	binary.LittleEndian.PutUint64(b[1:9], 60)
+	binary.LittleEndian.PutUint32(b[9:13], 4)
+	binary.LittleEndian.PutUint32(b[13:17], math.Float32bits(1500))
+	binary.LittleEndian.PutUint32(b[25:29], math.Float32bits(-0.25))
+	wp := parseWavePacket(b)
+	if wp.DescriptorIndex != 2 || wp.ByteOffset != 60 || wp.PacketSize != 4 || wp.ReturnPointLocation != 1500 || wp.Zt != -0.25 {
+		t.Errorf("unexpected wave packet %+v", wp)
+	}
+}
+
+func TestReadWaveformData(t *testing.T) {
+	payload := []byte{10, 0, 20, 0}
+	wp := &WaveformPacket{
+		ByteOffset: 60,
+		PacketSize: 4,
+		Descriptor: WaveformDescriptor{BitsPerSample: 16, NumberOfSamples: 2, DigitizerGain: 0.5, DigitizerOffset: 1},
+	}
+
+	// Internal data: the offset is relative to the start of the record header.
+	data := append(make([]byte, 100+60), payload...)
+	h := &LasHeader{GlobalEncoding: GlobalEncodingField{Value: 2}, WaveformDataStart: 100}
+	got, err := readWaveformData(bytes.NewReader(data), h, wp)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Errorf("internal data: got %v, %v", got, err)
+	}
+
+	// External data are read from the start of the .wdp file.
+	h = &LasHeader{GlobalEncoding: GlobalEncodingField{Value: 4}}
+	got, err = readWaveformData(bytes.NewReader(append(make([]byte, 60), payload...)), h, wp)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Errorf("external data: got %v, %v", got, err)
+	}
+
+	wp.Data = got
+	samples, err := wp.Samples()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0] != 6 || samples[1] != 11 {
+		t.Errorf("expected samples [6 11], got %v", samples)
+	}
+}
@@ -0,0 +1,121 @@
+package lidario
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// ErrOutputExists is returned by the writer-producing helpers when the output
+// file already exists and overwriting has not been enabled.
+var ErrOutputExists = errors.New("the output file already exists")
+
+// WriterOption configures the helpers that create output files.
+type WriterOption func(*writerOptions)
+
+type writerOptions struct {
+	overwrite      bool
+	intensityScale float64
+	splitOther     bool
+	// convertFormat is set if the points are written in pointFormat
+	convertFormat bool
+	pointFormat   uint8
+	vlrs          []VLR
+}
+
+// WithOverwrite controls whether an existing output file may be replaced. The
+// default is false, in which case ErrOutputExists is returned.
+func WithOverwrite(overwrite bool) WriterOption {
+	return func(o *writerOptions) {
+		o.overwrite = overwrite
+	}
+}
+
+// IntensityScale multiplies the intensity of every point by factor when the
+// file is written. Results are rounded and saturate at the uint16 range, so
+// values that would exceed 65535 are written as 65535. This is useful for
+// bringing files from sensors with different calibrations to a common range.
+// The factor must be positive; the writers return an error otherwise.
+func IntensityScale(factor float64) WriterOption {
+	return func(o *writerOptions) {
+		o.intensityScale = factor
+	}
+}
+
+// WithOtherClasses makes SplitByClass write the points whose class was not
+// requested to an additional output, keyed by OtherClasses.
+func WithOtherClasses() WriterOption {
+	return func(o *writerOptions) {
+		o.splitOther = true
+	}
+}
+
+// ConvertPointFormat writes the points in the given point format, 0-3,
+// whatever the format of the input, e.g. to normalise a directory of tiles of
+// mixed formats. Attributes that the format does not store, such as the RGB
+// colour of a format 3 point written as format 1, are dropped; those that the
+// input lacks, such as the GPS time of a format 2 point written as format 3,
+// are written as zero. An error is returned for other formats. NewLasWriter
+// and NewLazWriter convert the points to the format of their header in the
+// same way.
+func ConvertPointFormat(target byte) WriterOption {
+	return func(o *writerOptions) {
+		o.convertFormat = true
+		o.pointFormat = target
+	}
+}
+
+// WithVLRs adds VLRs, such as those describing the coordinate system, to
+// those written after the header by NewLazWriter and NewLasWriter. Repeated
+// options add to the VLRs rather than replacing them.
+func WithVLRs(vlrs ...VLR) WriterOption {
+	return func(o *writerOptions) {
+		o.vlrs = append(o.vlrs, vlrs...)
+	}
+}
+
+func newWriterOptions(opts []WriterOption) writerOptions {
+	o := writerOptions{intensityScale: 1.0}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// checkOutput returns ErrOutputExists if fileName exists and overwriting is disabled.
+func (o writerOptions) checkOutput(fileName string) error {
+	if o.overwrite {
+		return nil
+	}
+	if _, err := os.Stat(fileName); err == nil {
+		return ErrOutputExists
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// check returns an error if the format requested by ConvertPointFormat cannot
+// be written or the factor of IntensityScale is not positive.
+func (o writerOptions) check() error {
+	if o.convertFormat && o.pointFormat > 3 {
+		return fmt.Errorf("point format %v is not supported for writing", o.pointFormat)
+	}
+	if !(o.intensityScale > 0) {
+		return fmt.Errorf("the intensity scale factor must be positive, got %v", o.intensityScale)
+	}
+	return nil
+}
+
+// scaleIntensity scales an intensity value, saturating at the uint16 range.
+func scaleIntensity(intensity uint16, factor float64) uint16 {
+	v := math.Round(float64(intensity) * factor)
+	if v >= math.MaxUint16 {
+		return math.MaxUint16
+	}
+	if v <= 0 || math.IsNaN(v) {
+		return 0
+	}
+	return uint16(v)
+}
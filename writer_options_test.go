@@ -0,0 +1,185 @@
+package lidario
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithOverwrite(t *testing.T) {
+	lf := createTestLasFile(t, 0, []LasPointer{&PointRecord0{X: 1, Y: 1, Z: 1}})
+
+	fileName := filepath.Join(t.TempDir(), "existing.las")
+	if err := os.WriteFile(fileName, []byte("existing"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := InitializeUsingFile(fileName, lf); err != ErrOutputExists {
+		t.Fatalf("expected ErrOutputExists, got %v", err)
+	}
+
+	newLf, err := InitializeUsingFile(fileName, lf, WithOverwrite(true))
+	if err != nil {
+		t.Fatalf("expected overwriting to succeed, got %v", err)
+	}
+	if err = newLf.AddLasPoint(&PointRecord0{X: 1, Y: 1, Z: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err = newLf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if GetFileType(fileName) != "LAS" {
+		t.Error("expected the existing file to have been replaced by a LAS file")
+	}
+}
+
+func TestIntensityScale(t *testing.T) {
+	intensities := []uint16{0, 100, 1000, 32767, 40000, 65535}
+	var points []LasPointer
+	for i, intensity := range intensities {
+		points = append(points, &PointRecord0{X: float64(i), Y: float64(i), Z: 1.0, Intensity: intensity})
+	}
+	lf := createTestLasFile(t, 0, points)
+
+	fileName := filepath.Join(t.TempDir(), "scaled.las")
+	newLf, err := InitializeUsingFile(fileName, lf, IntensityScale(2.0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < lf.Header.NumberPoints; i++ {
+		p, err := lf.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = newLf.AddLasPoint(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = newLf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	scaled, err := NewLasFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer scaled.Close()
+	expected := []uint16{0, 200, 2000, 65534, 65535, 65535}
+	for i, e := range expected {
+		p, err := scaled.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.PointData().Intensity; got != e {
+			t.Errorf("point %v: expected intensity %v, got %v", i, e, got)
+		}
+	}
+
+	// A factor that is not positive is rejected before the output is created.
+	for _, factor := range []float64{0, -1, math.NaN()} {
+		fileName := filepath.Join(t.TempDir(), "invalid.las")
+		if _, err := InitializeUsingFile(fileName, lf, IntensityScale(factor)); err == nil {
+			t.Errorf("expected an error for the intensity scale factor %v", factor)
+		}
+		if _, err := os.Stat(fileName); !os.IsNotExist(err) {
+			t.Errorf("factor %v: expected no output file, got %v", factor, err)
+		}
+	}
+}
+
+func TestConvertPointFormat(t *testing.T) {
+	var points []LasPointer
+	for i := 0; i < 3; i++ {
+		points = append(points, &PointRecord3{
+			PointRecord0: &PointRecord0{X: float64(i), Y: float64(i), Z: 1, Intensity: uint16(i)},
+			GPSTime:      100.5 + float64(i),
+			RGB:          &RgbData{Red: 1000, Green: 2000, Blue: 3000},
+		})
+	}
+	lf := createTestLasFile(t, 3, points)
+
+	fileName := filepath.Join(t.TempDir(), "format1.las")
+	out, err := InitializeUsingFile(fileName, lf, ConvertPointFormat(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < lf.Header.NumberPoints; i++ {
+		p, err := lf.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = out.AddLasPoint(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	converted, err := NewLasFile(fileName, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer converted.Close()
+	if converted.Header.PointFormatID != 1 || converted.Header.PointRecordLength != 28 {
+		t.Fatalf("point format %v with %v-byte records, expected format 1 with 28-byte records",
+			converted.Header.PointFormatID, converted.Header.PointRecordLength)
+	}
+	for i := 0; i < converted.Header.NumberPoints; i++ {
+		p, err := converted.LasPoint(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := p.(*PointRecord1); !ok {
+			t.Fatalf("point %v: expected a *PointRecord1, got %T", i, p)
+		}
+		if got := p.GpsTimeData(); got != 100.5+float64(i) {
+			t.Errorf("point %v: expected GPS time %v, got %v", i, 100.5+float64(i), got)
+		}
+		if pd := p.PointData(); pd.X != float64(i) || pd.Intensity != uint16(i) {
+			t.Errorf("point %v: unexpected point %+v", i, pd)
+		}
+	}
+
+	// Formats that cannot be written are rejected.
+	for _, format := range []byte{4, 6, 11} {
+		if _, err = InitializeUsingFile(filepath.Join(t.TempDir(), "bad.las"), lf, ConvertPointFormat(format)); err == nil {
+			t.Errorf("expected an error for point format %v", format)
+		}
+	}
+}
+
+func TestConvertPointFormatZeroFill(t *testing.T) {
+	p := convertPointFormat(&PointRecord2{PointRecord0: &PointRecord0{X: 1}, RGB: &RgbData{Red: 7}}, 3)
+	if p.Format() != 3 || p.GpsTimeData() != 0 || p.RgbData().Red != 7 {
+		t.Errorf("expected a format 3 point with zero GPS time and the colour kept, got %+v", p)
+	}
+	p = convertPointFormat(&PointRecord1{PointRecord0: &PointRecord0{X: 1}, GPSTime: 5}, 0)
+	if p.Format() != 0 || p.PointData().X != 1 {
+		t.Errorf("expected a format 0 point, got %+v", p)
+	}
+}
+
+func TestWithVLRs(t *testing.T) {
+	a, b := VLR{UserID: "a", RecordID: 1}, VLR{UserID: "b", RecordID: 2}
+	o := newWriterOptions([]WriterOption{WithVLRs(a), WithVLRs(b)})
+	if len(o.vlrs) != 2 || o.vlrs[0].UserID != "a" || o.vlrs[1].UserID != "b" {
+		t.Errorf("got VLRs %+v, expected a and b", o.vlrs)
+	}
+
+	large := VLR{UserID: "large", BinaryData: make([]byte, 65536)}
+	lazFile := filepath.Join(t.TempDir(), "large.laz")
+	if _, err := NewLazWriter(lazFile, LasHeader{}, WithVLRs(large)); err == nil {
+		t.Error("expected NewLazWriter to reject an oversized VLR")
+	}
+	lasFile := filepath.Join(t.TempDir(), "large.las")
+	if _, err := NewLasWriter(lasFile, LasHeader{}, WithVLRs(large)); err == nil {
+		t.Error("expected NewLasWriter to reject an oversized VLR")
+	}
+	for _, fileName := range []string{lazFile, lasFile} {
+		if _, err := os.Stat(fileName); !os.IsNotExist(err) {
+			t.Errorf("%v was created despite the invalid VLR", fileName)
+		}
+	}
+}